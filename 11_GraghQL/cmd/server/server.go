@@ -5,6 +5,8 @@ import (
 	"log"
 	"net/http"
 	"os"
+	"strconv"
+	"strings"
 
 	_ "github.com/mattn/go-sqlite3"
 
@@ -14,11 +16,18 @@ import (
 	"github.com/99designs/gqlgen/graphql/handler/transport"
 	"github.com/99designs/gqlgen/graphql/playground"
 	"github.com/ElizCarvalho/FC_PosGolang/11_GraphQL/graph"
+	"github.com/ElizCarvalho/FC_PosGolang/11_GraphQL/graph/loaders"
 	"github.com/ElizCarvalho/FC_PosGolang/11_GraphQL/internal/database"
+	"github.com/ElizCarvalho/FC_PosGolang/pkg/gqlsecurity"
 	"github.com/vektah/gqlparser/v2/ast"
 )
 
-const defaultPort = "8080"
+const (
+	defaultPort            = "8080"
+	defaultComplexityLimit = 1000
+	defaultDepthLimit      = 5
+	defaultPersistedPath   = "persisted-queries.json"
+)
 
 func main() {
 	db, err := sql.Open("sqlite3", "file:./db.sqlite")
@@ -30,30 +39,63 @@ func main() {
 	categoryDB := database.NewCategory(db)
 	courseDB := database.NewCourse(db)
 
-	port := os.Getenv("PORT")
-	if port == "" {
-		port = defaultPort
-	}
+	port := getEnv("PORT", defaultPort)
+
+	// GRAPHQL_MODE=production: só queries do allowlist, sem introspecção nem playground
+	production := os.Getenv("GRAPHQL_MODE") == "production"
 
-	srv := handler.New(graph.NewExecutableSchema(graph.Config{Resolvers: &graph.Resolver{
-		CategoryDB: categoryDB,
-		CourseDB:   courseDB,
-	}}))
+	srv := handler.New(graph.NewExecutableSchema(graph.NewConfig(graph.NewResolver(categoryDB, courseDB))))
 
+	// em produção só as origens de GRAPHQL_ALLOWED_ORIGINS (ou a do próprio servidor) abrem subscriptions
+	srv.AddTransport(gqlsecurity.Websocket(production, strings.Split(os.Getenv("GRAPHQL_ALLOWED_ORIGINS"), ",")))
 	srv.AddTransport(transport.Options{})
 	srv.AddTransport(transport.GET{})
 	srv.AddTransport(transport.POST{})
 
 	srv.SetQueryCache(lru.New[*ast.QueryDocument](1000))
 
-	srv.Use(extension.Introspection{})
-	srv.Use(extension.AutomaticPersistedQuery{
-		Cache: lru.New[string](100),
-	})
+	// loaders por resposta agrupam as buscas de Category.courses e Course.category
+	srv.Use(loaders.Extension{CategoryDB: categoryDB, CourseDB: courseDB})
+	srv.Use(extension.FixedComplexityLimit(getEnvInt("GRAPHQL_COMPLEXITY_LIMIT", defaultComplexityLimit)))
+	srv.Use(gqlsecurity.DepthLimit{Limit: getEnvInt("GRAPHQL_DEPTH_LIMIT", defaultDepthLimit)})
+
+	if production {
+		path := getEnv("GRAPHQL_PERSISTED_QUERIES", defaultPersistedPath)
+		allowlist, err := gqlsecurity.LoadAllowlist(path)
+		if err != nil {
+			log.Fatalf("load persisted queries: %v", err)
+		}
+		srv.Use(allowlist)
+		log.Printf("production mode: %d persisted queries allowed from %s", allowlist.Len(), path)
+	} else {
+		srv.Use(extension.Introspection{})
+		srv.Use(extension.AutomaticPersistedQuery{
+			Cache: lru.New[string](100),
+		})
+		http.Handle("/", playground.Handler("GraphQL playground", "/query"))
+		log.Printf("connect to http://localhost:%s/ for GraphQL playground", port)
+	}
 
-	http.Handle("/", playground.Handler("GraphQL playground", "/query"))
 	http.Handle("/query", srv)
 
-	log.Printf("connect to http://localhost:%s/ for GraphQL playground", port)
 	log.Fatal(http.ListenAndServe(":"+port, nil))
 }
+
+func getEnv(key, fallback string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return fallback
+}
+
+func getEnvInt(key string, fallback int) int {
+	v := os.Getenv(key)
+	if v == "" {
+		return fallback
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil || n <= 0 {
+		log.Fatalf("invalid %s: %q", key, v)
+	}
+	return n
+}
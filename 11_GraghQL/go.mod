@@ -6,6 +6,8 @@ toolchain go1.24.8
 
 require (
 	github.com/99designs/gqlgen v0.17.81
+	github.com/ElizCarvalho/FC_PosGolang/pkg/gqlsecurity v0.0.0
+	github.com/ElizCarvalho/FC_PosGolang/pkg/pubsub v0.0.0
 	github.com/google/uuid v1.6.0
 	github.com/mattn/go-sqlite3 v1.14.32
 	github.com/vektah/gqlparser/v2 v2.5.30
@@ -27,3 +29,8 @@ require (
 	golang.org/x/tools v0.37.0 // indirect
 	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
+
+replace (
+	github.com/ElizCarvalho/FC_PosGolang/pkg/gqlsecurity => ../pkg/gqlsecurity
+	github.com/ElizCarvalho/FC_PosGolang/pkg/pubsub => ../pkg/pubsub
+)
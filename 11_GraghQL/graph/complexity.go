@@ -0,0 +1,15 @@
+package graph
+
+import "github.com/ElizCarvalho/FC_PosGolang/pkg/gqlsecurity"
+
+// NewConfig monta a Config do schema com o custo dos campos que retornam listas.
+// Sem ele cada campo custa 1 e categories { courses { category { courses ... } } } parece barato.
+func NewConfig(resolver *Resolver) Config {
+	cfg := Config{Resolvers: resolver}
+
+	cfg.Complexity.Query.Categories = gqlsecurity.ListComplexity
+	cfg.Complexity.Query.Courses = gqlsecurity.ListComplexity
+	cfg.Complexity.Category.Courses = gqlsecurity.ListComplexity
+
+	return cfg
+}
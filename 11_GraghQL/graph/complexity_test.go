@@ -0,0 +1,133 @@
+package graph
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/99designs/gqlgen/client"
+	"github.com/99designs/gqlgen/graphql/handler/extension"
+	"github.com/vektah/gqlparser/v2"
+
+	"github.com/ElizCarvalho/FC_PosGolang/pkg/gqlsecurity"
+)
+
+const nestedQuery = `{ categories { courses { category { courses { id } } } } }`
+
+func expectError(t *testing.T, err error, code, message string) {
+	t.Helper()
+	if err == nil {
+		t.Fatalf("expected %s error, got nil", code)
+	}
+	if !strings.Contains(err.Error(), code) || !strings.Contains(err.Error(), message) {
+		t.Fatalf("expected %s (%q), got %v", code, message, err)
+	}
+}
+
+func TestComplexityLimitCountsListFanOut(t *testing.T) {
+	c, _, _ := setupTest(t, extension.FixedComplexityLimit(1000))
+
+	var resp map[string]any
+	// courses { id } = 1 + 1*10; category = 1 + 11; courses = 1 + 12*10; categories = 1 + 121*10
+	err := c.Post(nestedQuery, &resp)
+	expectError(t, err, "COMPLEXITY_LIMIT_EXCEEDED", "operation has complexity 1211, which exceeds the limit of 1000")
+
+	if err := c.Post(`{ categories { id name courses { id name } } }`, &resp); err != nil {
+		t.Fatalf("expected usual query to pass, got %v", err)
+	}
+}
+
+func TestDepthLimit(t *testing.T) {
+	c, _, _ := setupTest(t, gqlsecurity.DepthLimit{Limit: 4})
+
+	var resp map[string]any
+	err := c.Post(nestedQuery, &resp)
+	expectError(t, err, "DEPTH_LIMIT_EXCEEDED", "operation has depth 5, which exceeds the limit of 4")
+
+	// fragments contam como se estivessem inline
+	err = c.Post(`query { categories { ...C } } fragment C on Category { courses { category { courses { id } } } }`, &resp)
+	expectError(t, err, "DEPTH_LIMIT_EXCEEDED", "depth 5")
+
+	if err := c.Post(`{ categories { courses { category { name } } } }`, &resp); err != nil {
+		t.Fatalf("expected depth 4 to pass, got %v", err)
+	}
+}
+
+func TestAllowlistOnlyRunsPersistedQueries(t *testing.T) {
+	const listCategories = `query ListCategories { categories { id name } }`
+	allowlist := gqlsecurity.NewAllowlist(map[string]string{gqlsecurity.QueryHash(listCategories): listCategories})
+	c, _, _ := setupTest(t, allowlist)
+
+	var resp struct {
+		Categories []struct{ ID, Name string }
+	}
+
+	// só o hash
+	err := c.Post("", &resp, client.Extensions(map[string]any{
+		"persistedQuery": map[string]any{"version": 1, "sha256Hash": gqlsecurity.QueryHash(listCategories)},
+	}))
+	if err != nil {
+		t.Fatalf("expected persisted hash to run, got %v", err)
+	}
+
+	// a query completa, idêntica à do manifesto
+	if err := c.Post(listCategories, &resp); err != nil {
+		t.Fatalf("expected allowlisted query text to run, got %v", err)
+	}
+
+	var other map[string]any
+	err = c.Post(`{ courses { id } }`, &other)
+	expectError(t, err, "PERSISTED_QUERY_NOT_ALLOWED", "is not in the allowlist")
+
+	err = c.Post("", &other, client.Extensions(map[string]any{
+		"persistedQuery": map[string]any{"version": 1, "sha256Hash": "unknown"},
+	}))
+	expectError(t, err, "PERSISTED_QUERY_NOT_ALLOWED", "persisted query unknown is not in the allowlist")
+
+	err = c.Post("", &other)
+	expectError(t, err, "PERSISTED_QUERY_NOT_ALLOWED", "only persisted queries are accepted")
+}
+
+// em produção o servidor não registra extension.Introspection
+func TestIntrospectionIsOffWithoutExtension(t *testing.T) {
+	c, _, _ := setupTest(t)
+
+	var resp map[string]any
+	err := c.Post(`{ __schema { queryType { name } } }`, &resp)
+	if err == nil || !strings.Contains(err.Error(), "introspection disabled") {
+		t.Fatalf("expected introspection disabled, got %v", err)
+	}
+}
+
+// o manifesto usado em produção precisa continuar válido quando o schema mudar
+func TestShippedPersistedQueriesMatchSchema(t *testing.T) {
+	data, err := os.ReadFile(filepath.Join("..", "cmd", "server", "persisted-queries.json"))
+	if err != nil {
+		t.Fatalf("read manifest: %v", err)
+	}
+	var manifest struct {
+		Operations []struct{ Name, Body string }
+	}
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		t.Fatalf("parse manifest: %v", err)
+	}
+
+	schema := NewExecutableSchema(NewConfig(&Resolver{})).Schema()
+	for _, op := range manifest.Operations {
+		if _, errs := gqlparser.LoadQuery(schema, op.Body); errs != nil {
+			t.Errorf("%s: %v", op.Name, errs)
+		}
+	}
+}
+
+func TestShippedPersistedQueriesLoadAsAllowlist(t *testing.T) {
+	allowlist, err := gqlsecurity.LoadAllowlist(filepath.Join("..", "cmd", "server", "persisted-queries.json"))
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if allowlist.Len() == 0 {
+		t.Fatal("expected persisted queries in the manifest")
+	}
+}
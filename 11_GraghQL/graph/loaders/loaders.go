@@ -0,0 +1,93 @@
+// Package loaders cria os dataloaders de cada resposta GraphQL, evitando o N+1 nos
+// resolvers de Category.courses e Course.category.
+package loaders
+
+import (
+	"context"
+	"time"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/ElizCarvalho/FC_PosGolang/11_GraphQL/internal/database"
+	"github.com/ElizCarvalho/FC_PosGolang/11_GraphQL/internal/dataloader"
+)
+
+const (
+	// batchWait é a janela em que as chaves pedidas pelos resolvers são acumuladas
+	batchWait = 2 * time.Millisecond
+	// maxBatch limita o tamanho do IN (...) de cada query
+	maxBatch = 100
+)
+
+type ctxKey struct{}
+
+// Loaders agrupa os dataloaders de uma resposta.
+type Loaders struct {
+	CategoryByID        *dataloader.Loader[string, database.Category]
+	CoursesByCategoryID *dataloader.Loader[string, []database.Course]
+}
+
+// New cria loaders novos, com cache vazio.
+func New(categoryDB *database.Category, courseDB *database.Course) *Loaders {
+	return &Loaders{
+		CategoryByID:        dataloader.New(categoriesByID(categoryDB), batchWait, maxBatch),
+		CoursesByCategoryID: dataloader.New(coursesByCategoryID(courseDB), batchWait, maxBatch),
+	}
+}
+
+// Extension coloca loaders novos no contexto de cada resposta GraphQL.
+// Em uma subscription cada evento é uma resposta, então o cache não fica
+// velho durante a vida da conexão websocket.
+type Extension struct {
+	CategoryDB *database.Category
+	CourseDB   *database.Course
+}
+
+var _ interface {
+	graphql.HandlerExtension
+	graphql.ResponseInterceptor
+} = Extension{}
+
+func (Extension) ExtensionName() string { return "Loaders" }
+
+func (Extension) Validate(graphql.ExecutableSchema) error { return nil }
+
+func (e Extension) InterceptResponse(ctx context.Context, next graphql.ResponseHandler) *graphql.Response {
+	return next(context.WithValue(ctx, ctxKey{}, New(e.CategoryDB, e.CourseDB)))
+}
+
+// For retorna os loaders da resposta em execução.
+func For(ctx context.Context) *Loaders {
+	return ctx.Value(ctxKey{}).(*Loaders)
+}
+
+func categoriesByID(categoryDB *database.Category) dataloader.FetchFunc[string, database.Category] {
+	return func(ids []string) (map[string]database.Category, error) {
+		categories, err := categoryDB.GetByIDs(ids)
+		if err != nil {
+			return nil, err
+		}
+		result := make(map[string]database.Category, len(categories))
+		for _, category := range categories {
+			result[category.ID] = category
+		}
+		return result, nil
+	}
+}
+
+func coursesByCategoryID(courseDB *database.Course) dataloader.FetchFunc[string, []database.Course] {
+	return func(categoryIDs []string) (map[string][]database.Course, error) {
+		courses, err := courseDB.GetByCategoryIDs(categoryIDs)
+		if err != nil {
+			return nil, err
+		}
+		// toda categoria pedida tem resultado, mesmo que sem cursos
+		result := make(map[string][]database.Course, len(categoryIDs))
+		for _, id := range categoryIDs {
+			result[id] = nil
+		}
+		for _, course := range courses {
+			result[course.CategoryID] = append(result[course.CategoryID], course)
+		}
+		return result, nil
+	}
+}
@@ -1,8 +1,9 @@
 package model
 
+// Course não carrega a categoria: Course.category é resolvido pelo dataloader a partir de CategoryID
 type Course struct {
-	ID          string    `json:"id"`
-	Name        string    `json:"name"`
-	Description string    `json:"description"`
-	Category    *Category `json:"category"`
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	CategoryID  string `json:"-"`
 }
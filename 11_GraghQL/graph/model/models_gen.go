@@ -18,3 +18,6 @@ type NewCourse struct {
 
 type Query struct {
 }
+
+type Subscription struct {
+}
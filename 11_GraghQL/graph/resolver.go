@@ -1,6 +1,13 @@
 package graph
 
-import "github.com/ElizCarvalho/FC_PosGolang/11_GraphQL/internal/database"
+import (
+	"database/sql"
+	"errors"
+
+	"github.com/ElizCarvalho/FC_PosGolang/11_GraphQL/graph/model"
+	"github.com/ElizCarvalho/FC_PosGolang/11_GraphQL/internal/database"
+	"github.com/ElizCarvalho/FC_PosGolang/pkg/pubsub"
+)
 
 // This file will not be regenerated automatically.
 //
@@ -9,4 +16,53 @@ import "github.com/ElizCarvalho/FC_PosGolang/11_GraphQL/internal/database"
 type Resolver struct {
 	CategoryDB *database.Category
 	CourseDB   *database.Course
+
+	// CategoryEvents e CourseEvents alimentam as subscriptions categoryCreated e courseCreated
+	CategoryEvents *pubsub.Broker[*model.Category]
+	CourseEvents   *pubsub.Broker[*model.Course]
+}
+
+// subscriptionBuffer é quantos eventos um assinante pode acumular antes de perder os próximos
+const subscriptionBuffer = 16
+
+// NewResolver cria o resolver com os brokers das subscriptions.
+func NewResolver(categoryDB *database.Category, courseDB *database.Course) *Resolver {
+	return &Resolver{
+		CategoryDB:     categoryDB,
+		CourseDB:       courseDB,
+		CategoryEvents: pubsub.NewBroker[*model.Category](subscriptionBuffer),
+		CourseEvents:   pubsub.NewBroker[*model.Course](subscriptionBuffer),
+	}
+}
+
+var (
+	ErrCategoryNotFound   = errors.New("category not found")
+	ErrCourseNotFound     = errors.New("course not found")
+	ErrCategoryHasCourses = errors.New("category has courses")
+)
+
+// ensureCategory garante que a categoria existe antes de vincular um curso a ela
+func (r *Resolver) ensureCategory(id string) error {
+	_, err := r.CategoryDB.GetByID(id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrCategoryNotFound
+	}
+	return err
+}
+
+func toCategoryModel(category database.Category) *model.Category {
+	return &model.Category{
+		ID:          category.ID,
+		Name:        category.Name,
+		Description: category.Description,
+	}
+}
+
+func toCourseModel(course database.Course) *model.Course {
+	return &model.Course{
+		ID:          course.ID,
+		Name:        course.Name,
+		Description: course.Description,
+		CategoryID:  course.CategoryID,
+	}
 }
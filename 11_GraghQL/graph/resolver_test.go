@@ -0,0 +1,461 @@
+package graph
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/99designs/gqlgen/client"
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/99designs/gqlgen/graphql/handler"
+	"github.com/99designs/gqlgen/graphql/handler/transport"
+	"github.com/mattn/go-sqlite3"
+
+	"github.com/ElizCarvalho/FC_PosGolang/11_GraphQL/graph/loaders"
+	"github.com/ElizCarvalho/FC_PosGolang/11_GraphQL/internal/database"
+)
+
+// countingDriver envolve o sqlite3 e conta os SELECTs executados
+type countingDriver struct {
+	sqlite3.SQLiteDriver
+	selects atomic.Int64
+}
+
+type countingConn struct {
+	driver.Conn
+	counter *countingDriver
+}
+
+type countingStmt struct {
+	driver.Stmt
+	query   string
+	counter *countingDriver
+}
+
+func (d *countingDriver) Open(name string) (driver.Conn, error) {
+	conn, err := d.SQLiteDriver.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	return &countingConn{Conn: conn, counter: d}, nil
+}
+
+func (c *countingConn) Prepare(query string) (driver.Stmt, error) {
+	stmt, err := c.Conn.Prepare(query)
+	if err != nil {
+		return nil, err
+	}
+	return &countingStmt{Stmt: stmt, query: query, counter: c.counter}, nil
+}
+
+//nolint:staticcheck // database/sql usa Query quando o stmt não implementa StmtQueryContext
+func (s *countingStmt) Query(args []driver.Value) (driver.Rows, error) {
+	if strings.HasPrefix(strings.TrimSpace(strings.ToUpper(s.query)), "SELECT") {
+		s.counter.selects.Add(1)
+	}
+	return s.Stmt.Query(args)
+}
+
+var (
+	registerOnce sync.Once
+	counting     = &countingDriver{}
+	dbSeq        atomic.Int64
+)
+
+func setupTest(t *testing.T, extensions ...graphql.HandlerExtension) (*client.Client, *sql.DB, *Resolver) {
+	t.Helper()
+	registerOnce.Do(func() { sql.Register("sqlite3_counting", counting) })
+
+	dsn := fmt.Sprintf("file:graph_test_%d?mode=memory&cache=shared", dbSeq.Add(1))
+	db, err := sql.Open("sqlite3_counting", dsn)
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	// o wrapper só expõe Prepare, então cada statement vai em um Exec
+	for _, stmt := range []string{
+		`CREATE TABLE categories (id TEXT PRIMARY KEY, name TEXT NOT NULL, description TEXT)`,
+		`CREATE TABLE courses (id TEXT PRIMARY KEY, name TEXT NOT NULL, description TEXT, category_id TEXT NOT NULL, FOREIGN KEY (category_id) REFERENCES categories(id))`,
+	} {
+		if _, err := db.Exec(stmt); err != nil {
+			t.Fatalf("create tables: %v", err)
+		}
+	}
+
+	categoryDB := database.NewCategory(db)
+	courseDB := database.NewCourse(db)
+
+	resolver := NewResolver(categoryDB, courseDB)
+	srv := handler.New(NewExecutableSchema(NewConfig(resolver)))
+	srv.AddTransport(transport.Websocket{})
+	srv.AddTransport(transport.POST{})
+	srv.Use(loaders.Extension{CategoryDB: categoryDB, CourseDB: courseDB})
+	for _, ext := range extensions {
+		srv.Use(ext)
+	}
+
+	return client.New(srv), db, resolver
+}
+
+// seed cria n categorias com 2 cursos cada
+func seed(t *testing.T, c *client.Client, n int) {
+	t.Helper()
+	for i := range n {
+		var created struct {
+			CreateCategory struct{ ID string }
+		}
+		c.MustPost(`mutation($name: String!) { createCategory(input: {name: $name, description: "d"}) { id } }`,
+			&created, client.Var("name", fmt.Sprintf("category %d", i)))
+		for j := range 2 {
+			var course struct {
+				CreateCourse struct{ ID string }
+			}
+			c.MustPost(`mutation($name: String!, $categoryId: ID!) { createCourse(input: {name: $name, description: "d", categoryId: $categoryId}) { id } }`,
+				&course, client.Var("name", fmt.Sprintf("course %d.%d", i, j)), client.Var("categoryId", created.CreateCategory.ID))
+		}
+	}
+}
+
+func countSelects(fn func()) int64 {
+	before := counting.selects.Load()
+	fn()
+	return counting.selects.Load() - before
+}
+
+func TestCategoriesWithCoursesIsBatched(t *testing.T) {
+	c, _, _ := setupTest(t)
+	seed(t, c, 5)
+
+	var resp struct {
+		Categories []struct {
+			ID      string
+			Courses []struct {
+				ID       string
+				Category struct{ ID string }
+			}
+		}
+	}
+	selects := countSelects(func() {
+		c.MustPost(`{ categories { id courses { id category { id } } } }`, &resp)
+	})
+
+	// 1 para as categorias, 1 IN (...) para os cursos e 1 IN (...) para Course.category
+	if selects != 3 {
+		t.Fatalf("expected 3 queries, got %d", selects)
+	}
+	if len(resp.Categories) != 5 {
+		t.Fatalf("expected 5 categories, got %d", len(resp.Categories))
+	}
+	for _, category := range resp.Categories {
+		if len(category.Courses) != 2 {
+			t.Fatalf("category %s: expected 2 courses, got %d", category.ID, len(category.Courses))
+		}
+		for _, course := range category.Courses {
+			if course.Category.ID != category.ID {
+				t.Fatalf("course %s: expected category %s, got %s", course.ID, category.ID, course.Category.ID)
+			}
+		}
+	}
+}
+
+func TestCoursesWithCategoryIsBatched(t *testing.T) {
+	c, _, _ := setupTest(t)
+	seed(t, c, 4)
+
+	var resp struct {
+		Courses []struct {
+			Name     string
+			Category struct{ Name string }
+		}
+	}
+	selects := countSelects(func() {
+		c.MustPost(`{ courses { name category { name } } }`, &resp)
+	})
+
+	if selects != 2 {
+		t.Fatalf("expected 2 queries, got %d", selects)
+	}
+	if len(resp.Courses) != 8 {
+		t.Fatalf("expected 8 courses, got %d", len(resp.Courses))
+	}
+	for _, course := range resp.Courses {
+		if !strings.HasPrefix(course.Name, "course "+strings.TrimPrefix(course.Category.Name, "category ")+".") {
+			t.Fatalf("course %q resolved to %q", course.Name, course.Category.Name)
+		}
+	}
+}
+
+func TestCategoryWithoutCoursesReturnsEmptyList(t *testing.T) {
+	c, db, _ := setupTest(t)
+	if _, err := db.Exec(`INSERT INTO categories (id, name, description) VALUES ('empty', 'Empty', '')`); err != nil {
+		t.Fatalf("insert: %v", err)
+	}
+
+	var resp struct {
+		Categories []struct {
+			ID      string
+			Courses []struct{ ID string }
+		}
+	}
+	c.MustPost(`{ categories { id courses { id } } }`, &resp)
+
+	if len(resp.Categories) != 1 || resp.Categories[0].Courses == nil || len(resp.Categories[0].Courses) != 0 {
+		t.Fatalf("expected one category with an empty course list, got %+v", resp.Categories)
+	}
+}
+
+func createCategory(t *testing.T, c *client.Client, name string) string {
+	t.Helper()
+	var resp struct {
+		CreateCategory struct{ ID string }
+	}
+	c.MustPost(`mutation($name: String!) { createCategory(input: {name: $name, description: "d"}) { id } }`,
+		&resp, client.Var("name", name))
+	return resp.CreateCategory.ID
+}
+
+func TestCreateCourseResolvesItsCategory(t *testing.T) {
+	c, _, _ := setupTest(t)
+	categoryID := createCategory(t, c, "Backend")
+
+	var resp struct {
+		CreateCourse struct {
+			Name     string
+			Category struct{ ID, Name string }
+		}
+	}
+	c.MustPost(`mutation($categoryId: ID!) { createCourse(input: {name: "Go", description: "d", categoryId: $categoryId}) { name category { id name } } }`,
+		&resp, client.Var("categoryId", categoryID))
+
+	if resp.CreateCourse.Category.ID != categoryID || resp.CreateCourse.Category.Name != "Backend" {
+		t.Fatalf("expected category Backend (%s), got %+v", categoryID, resp.CreateCourse.Category)
+	}
+}
+
+func TestCreateCourseRequiresExistingCategory(t *testing.T) {
+	c, _, _ := setupTest(t)
+
+	var resp struct{ CreateCourse struct{ ID string } }
+	err := c.Post(`mutation { createCourse(input: {name: "Go", description: "d", categoryId: "missing"}) { id } }`, &resp)
+	if err == nil || !strings.Contains(err.Error(), ErrCategoryNotFound.Error()) {
+		t.Fatalf("expected %q, got %v", ErrCategoryNotFound, err)
+	}
+
+	var list struct{ Courses []struct{ ID string } }
+	c.MustPost(`{ courses { id } }`, &list)
+	if len(list.Courses) != 0 {
+		t.Fatalf("expected no course to be created, got %d", len(list.Courses))
+	}
+}
+
+func TestSingleItemQueries(t *testing.T) {
+	c, _, _ := setupTest(t)
+	categoryID := createCategory(t, c, "Backend")
+	var created struct{ CreateCourse struct{ ID string } }
+	c.MustPost(`mutation($categoryId: ID!) { createCourse(input: {name: "Go", description: "d", categoryId: $categoryId}) { id } }`,
+		&created, client.Var("categoryId", categoryID))
+
+	var resp struct {
+		Category *struct {
+			Name    string
+			Courses []struct{ Name string }
+		}
+		Course *struct {
+			Name     string
+			Category struct{ Name string }
+		}
+		MissingCategory *struct{ ID string }
+		MissingCourse   *struct{ ID string }
+	}
+	c.MustPost(`query($categoryId: ID!, $courseId: ID!) {
+		category(id: $categoryId) { name courses { name } }
+		course(id: $courseId) { name category { name } }
+		missingCategory: category(id: "missing") { id }
+		missingCourse: course(id: "missing") { id }
+	}`, &resp, client.Var("categoryId", categoryID), client.Var("courseId", created.CreateCourse.ID))
+
+	if resp.Category == nil || resp.Category.Name != "Backend" || len(resp.Category.Courses) != 1 {
+		t.Fatalf("unexpected category: %+v", resp.Category)
+	}
+	if resp.Course == nil || resp.Course.Name != "Go" || resp.Course.Category.Name != "Backend" {
+		t.Fatalf("unexpected course: %+v", resp.Course)
+	}
+	if resp.MissingCategory != nil || resp.MissingCourse != nil {
+		t.Fatalf("expected null for missing ids, got %+v / %+v", resp.MissingCategory, resp.MissingCourse)
+	}
+}
+
+func TestUpdateMutations(t *testing.T) {
+	c, _, _ := setupTest(t)
+	backend := createCategory(t, c, "Backend")
+	frontend := createCategory(t, c, "Frontend")
+	var created struct{ CreateCourse struct{ ID string } }
+	c.MustPost(`mutation($categoryId: ID!) { createCourse(input: {name: "Go", description: "d", categoryId: $categoryId}) { id } }`,
+		&created, client.Var("categoryId", backend))
+	courseID := created.CreateCourse.ID
+
+	var category struct {
+		UpdateCategory struct{ Name, Description string }
+	}
+	c.MustPost(`mutation($id: ID!) { updateCategory(id: $id, input: {name: "Back-end", description: "APIs"}) { name description } }`,
+		&category, client.Var("id", backend))
+	if category.UpdateCategory.Name != "Back-end" || category.UpdateCategory.Description != "APIs" {
+		t.Fatalf("unexpected updated category: %+v", category.UpdateCategory)
+	}
+
+	var course struct {
+		UpdateCourse struct {
+			Name     string
+			Category struct{ ID string }
+		}
+	}
+	c.MustPost(`mutation($id: ID!, $categoryId: ID!) { updateCourse(id: $id, input: {name: "React", description: "d", categoryId: $categoryId}) { name category { id } } }`,
+		&course, client.Var("id", courseID), client.Var("categoryId", frontend))
+	if course.UpdateCourse.Name != "React" || course.UpdateCourse.Category.ID != frontend {
+		t.Fatalf("unexpected updated course: %+v", course.UpdateCourse)
+	}
+
+	tests := []struct {
+		name  string
+		query string
+		vars  []client.Option
+		want  error
+	}{
+		{"missing category", `mutation { updateCategory(id: "missing", input: {name: "x", description: "x"}) { id } }`, nil, ErrCategoryNotFound},
+		{"missing course", `mutation($categoryId: ID!) { updateCourse(id: "missing", input: {name: "x", description: "x", categoryId: $categoryId}) { id } }`,
+			[]client.Option{client.Var("categoryId", backend)}, ErrCourseNotFound},
+		{"course to missing category", `mutation($id: ID!) { updateCourse(id: $id, input: {name: "x", description: "x", categoryId: "missing"}) { id } }`,
+			[]client.Option{client.Var("id", courseID)}, ErrCategoryNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var resp map[string]any
+			err := c.Post(tt.query, &resp, tt.vars...)
+			if err == nil || !strings.Contains(err.Error(), tt.want.Error()) {
+				t.Fatalf("expected %q, got %v", tt.want, err)
+			}
+		})
+	}
+}
+
+func TestDeleteMutations(t *testing.T) {
+	c, _, _ := setupTest(t)
+	categoryID := createCategory(t, c, "Backend")
+	var created struct{ CreateCourse struct{ ID string } }
+	c.MustPost(`mutation($categoryId: ID!) { createCourse(input: {name: "Go", description: "d", categoryId: $categoryId}) { id } }`,
+		&created, client.Var("categoryId", categoryID))
+
+	var resp map[string]any
+	err := c.Post(`mutation($id: ID!) { deleteCategory(id: $id) }`, &resp, client.Var("id", categoryID))
+	if err == nil || !strings.Contains(err.Error(), ErrCategoryHasCourses.Error()) {
+		t.Fatalf("expected %q, got %v", ErrCategoryHasCourses, err)
+	}
+
+	var deleted struct{ DeleteCourse, DeleteCategory bool }
+	c.MustPost(`mutation($courseId: ID!, $categoryId: ID!) {
+		deleteCourse(id: $courseId)
+		deleteCategory(id: $categoryId)
+	}`, &deleted, client.Var("courseId", created.CreateCourse.ID), client.Var("categoryId", categoryID))
+	if !deleted.DeleteCourse || !deleted.DeleteCategory {
+		t.Fatalf("expected both deletes to succeed, got %+v", deleted)
+	}
+
+	err = c.Post(`mutation($id: ID!) { deleteCourse(id: $id) }`, &resp, client.Var("id", created.CreateCourse.ID))
+	if err == nil || !strings.Contains(err.Error(), ErrCourseNotFound.Error()) {
+		t.Fatalf("expected %q, got %v", ErrCourseNotFound, err)
+	}
+	err = c.Post(`mutation($id: ID!) { deleteCategory(id: $id) }`, &resp, client.Var("id", categoryID))
+	if err == nil || !strings.Contains(err.Error(), ErrCategoryNotFound.Error()) {
+		t.Fatalf("expected %q, got %v", ErrCategoryNotFound, err)
+	}
+}
+
+// waitSubscribers espera a subscription ser registrada no broker antes de publicar
+func waitSubscribers(t *testing.T, count func() int, want int) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for count() != want {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected %d subscribers, got %d", want, count())
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestCategoryCreatedSubscription(t *testing.T) {
+	c, _, resolver := setupTest(t)
+
+	sub := c.Websocket(`subscription { categoryCreated { id name } }`)
+	defer sub.Close()
+	waitSubscribers(t, resolver.CategoryEvents.Subscribers, 1)
+
+	id := createCategory(t, c, "Backend")
+
+	var resp struct {
+		CategoryCreated struct{ ID, Name string }
+	}
+	if err := sub.Next(&resp); err != nil {
+		t.Fatalf("next: %v", err)
+	}
+	if resp.CategoryCreated.ID != id || resp.CategoryCreated.Name != "Backend" {
+		t.Fatalf("unexpected event: %+v", resp.CategoryCreated)
+	}
+}
+
+func TestCourseCreatedSubscriptionResolvesFreshCategory(t *testing.T) {
+	c, _, resolver := setupTest(t)
+	categoryID := createCategory(t, c, "Backend")
+
+	sub := c.Websocket(`subscription { courseCreated { name category { name } } }`)
+	defer sub.Close()
+	waitSubscribers(t, resolver.CourseEvents.Subscribers, 1)
+
+	type event struct {
+		CourseCreated struct {
+			Name     string
+			Category struct{ Name string }
+		}
+	}
+	createCourse := func(name string) event {
+		t.Helper()
+		var created map[string]any
+		c.MustPost(`mutation($name: String!, $categoryId: ID!) { createCourse(input: {name: $name, description: "d", categoryId: $categoryId}) { id } }`,
+			&created, client.Var("name", name), client.Var("categoryId", categoryID))
+		var resp event
+		if err := sub.Next(&resp); err != nil {
+			t.Fatalf("next: %v", err)
+		}
+		return resp
+	}
+
+	if got := createCourse("Go"); got.CourseCreated.Name != "Go" || got.CourseCreated.Category.Name != "Backend" {
+		t.Fatalf("unexpected first event: %+v", got)
+	}
+
+	// cada evento usa loaders novos, então a categoria renomeada aparece no próximo evento
+	var updated map[string]any
+	c.MustPost(`mutation($id: ID!) { updateCategory(id: $id, input: {name: "Back-end", description: "d"}) { id } }`,
+		&updated, client.Var("id", categoryID))
+
+	if got := createCourse("Rust"); got.CourseCreated.Name != "Rust" || got.CourseCreated.Category.Name != "Back-end" {
+		t.Fatalf("unexpected second event: %+v", got)
+	}
+}
+
+func TestSubscriptionUnsubscribesOnClose(t *testing.T) {
+	c, _, resolver := setupTest(t)
+
+	sub := c.Websocket(`subscription { categoryCreated { id } }`)
+	waitSubscribers(t, resolver.CategoryEvents.Subscribers, 1)
+
+	if err := sub.Close(); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+	waitSubscribers(t, resolver.CategoryEvents.Subscribers, 0)
+}
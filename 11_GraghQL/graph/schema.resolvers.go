@@ -6,53 +6,119 @@ package graph
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 
+	"github.com/ElizCarvalho/FC_PosGolang/11_GraphQL/graph/loaders"
 	"github.com/ElizCarvalho/FC_PosGolang/11_GraphQL/graph/model"
+	"github.com/ElizCarvalho/FC_PosGolang/11_GraphQL/internal/database"
+	"github.com/ElizCarvalho/FC_PosGolang/11_GraphQL/internal/dataloader"
 )
 
 // Courses is the resolver for the courses field.
 func (r *categoryResolver) Courses(ctx context.Context, obj *model.Category) ([]*model.Course, error) {
-	courses, err := r.CourseDB.GetByCategoryID(obj.ID)
+	// os cursos de todas as categorias da resposta saem de uma única query
+	courses, err := loaders.For(ctx).CoursesByCategoryID.Load(obj.ID)
 	if err != nil {
 		return nil, err
 	}
 
-	var result []*model.Course
+	result := make([]*model.Course, 0, len(courses))
 	for _, course := range courses {
-		result = append(result, &model.Course{
-			ID:          course.ID,
-			Name:        course.Name,
-			Description: course.Description,
-		})
+		result = append(result, toCourseModel(course))
 	}
 	return result, nil
 }
 
+// Category is the resolver for the category field.
+func (r *courseResolver) Category(ctx context.Context, obj *model.Course) (*model.Category, error) {
+	category, err := loaders.For(ctx).CategoryByID.Load(obj.CategoryID)
+	if errors.Is(err, dataloader.ErrNotFound) {
+		return nil, ErrCategoryNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return toCategoryModel(category), nil
+}
+
 // CreateCategory is the resolver for the createCategory field.
 func (r *mutationResolver) CreateCategory(ctx context.Context, input model.NewCategory) (*model.Category, error) {
 	category, err := r.CategoryDB.Create(input.Name, input.Description)
 	if err != nil {
 		return nil, err
 	}
-	return &model.Category{
-		ID:          category.ID,
-		Name:        category.Name,
-		Description: category.Description,
-	}, nil
+	result := toCategoryModel(category)
+	r.CategoryEvents.Publish(result)
+	return result, nil
+}
+
+// UpdateCategory is the resolver for the updateCategory field.
+func (r *mutationResolver) UpdateCategory(ctx context.Context, id string, input model.NewCategory) (*model.Category, error) {
+	category, err := r.CategoryDB.Update(id, input.Name, input.Description)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrCategoryNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return toCategoryModel(category), nil
+}
+
+// DeleteCategory is the resolver for the deleteCategory field.
+func (r *mutationResolver) DeleteCategory(ctx context.Context, id string) (bool, error) {
+	err := r.CategoryDB.Delete(id)
+	if errors.Is(err, database.ErrCategoryHasCourses) {
+		return false, ErrCategoryHasCourses
+	}
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, ErrCategoryNotFound
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
 }
 
 // CreateCourse is the resolver for the createCourse field.
 func (r *mutationResolver) CreateCourse(ctx context.Context, input model.NewCourse) (*model.Course, error) {
+	if err := r.ensureCategory(input.CategoryID); err != nil {
+		return nil, err
+	}
 	course, err := r.CourseDB.Create(input.Name, input.Description, input.CategoryID)
 	if err != nil {
 		return nil, err
 	}
-	return &model.Course{
-		ID:          course.ID,
-		Name:        course.Name,
-		Description: course.Description,
-		Category:    &model.Category{ID: course.Name, Name: course.Name, Description: course.Description},
-	}, nil
+	result := toCourseModel(course)
+	r.CourseEvents.Publish(result)
+	return result, nil
+}
+
+// UpdateCourse is the resolver for the updateCourse field.
+func (r *mutationResolver) UpdateCourse(ctx context.Context, id string, input model.NewCourse) (*model.Course, error) {
+	if err := r.ensureCategory(input.CategoryID); err != nil {
+		return nil, err
+	}
+	course, err := r.CourseDB.Update(id, input.Name, input.Description, input.CategoryID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrCourseNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return toCourseModel(course), nil
+}
+
+// DeleteCourse is the resolver for the deleteCourse field.
+func (r *mutationResolver) DeleteCourse(ctx context.Context, id string) (bool, error) {
+	err := r.CourseDB.Delete(id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, ErrCourseNotFound
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
 }
 
 // Categories is the resolver for the categories field.
@@ -76,37 +142,70 @@ func (r *queryResolver) Categories(ctx context.Context) ([]*model.Category, erro
 
 // Courses is the resolver for the courses field.
 func (r *queryResolver) Courses(ctx context.Context) ([]*model.Course, error) {
-	// 🚀 OTIMIZAÇÃO: 1 query com JOIN em vez de N+1 queries
-	courses, err := r.CourseDB.ListWithCategories()
+	// 🚀 OTIMIZAÇÃO: as categorias são buscadas pelo dataloader em 1 query, em vez de N+1
+	courses, err := r.CourseDB.List()
 	if err != nil {
 		return nil, err
 	}
 
-	var result []*model.Course
+	result := make([]*model.Course, 0, len(courses))
 	for _, course := range courses {
-		result = append(result, &model.Course{
-			ID:          course.CourseID,
-			Name:        course.CourseName,
-			Description: course.CourseDescription,
-			Category: &model.Category{
-				ID:          course.CategoryID,
-				Name:        course.CategoryName,
-				Description: course.CategoryDescription,
-			},
-		})
+		result = append(result, toCourseModel(course))
 	}
 	return result, nil
 }
 
+// Category is the resolver for the category field.
+func (r *queryResolver) Category(ctx context.Context, id string) (*model.Category, error) {
+	category, err := r.CategoryDB.GetByID(id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return toCategoryModel(category), nil
+}
+
+// Course is the resolver for the course field.
+func (r *queryResolver) Course(ctx context.Context, id string) (*model.Course, error) {
+	course, err := r.CourseDB.GetByID(id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return toCourseModel(course), nil
+}
+
+// CategoryCreated is the resolver for the categoryCreated field.
+func (r *subscriptionResolver) CategoryCreated(ctx context.Context) (<-chan *model.Category, error) {
+	return r.CategoryEvents.Subscribe(ctx), nil
+}
+
+// CourseCreated is the resolver for the courseCreated field.
+func (r *subscriptionResolver) CourseCreated(ctx context.Context) (<-chan *model.Course, error) {
+	return r.CourseEvents.Subscribe(ctx), nil
+}
+
 // Category returns CategoryResolver implementation.
 func (r *Resolver) Category() CategoryResolver { return &categoryResolver{r} }
 
+// Course returns CourseResolver implementation.
+func (r *Resolver) Course() CourseResolver { return &courseResolver{r} }
+
 // Mutation returns MutationResolver implementation.
 func (r *Resolver) Mutation() MutationResolver { return &mutationResolver{r} }
 
 // Query returns QueryResolver implementation.
 func (r *Resolver) Query() QueryResolver { return &queryResolver{r} }
 
+// Subscription returns SubscriptionResolver implementation.
+func (r *Resolver) Subscription() SubscriptionResolver { return &subscriptionResolver{r} }
+
 type categoryResolver struct{ *Resolver }
+type courseResolver struct{ *Resolver }
 type mutationResolver struct{ *Resolver }
 type queryResolver struct{ *Resolver }
+type subscriptionResolver struct{ *Resolver }
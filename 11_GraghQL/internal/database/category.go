@@ -2,10 +2,15 @@ package database
 
 import (
 	"database/sql"
+	"errors"
+	"strings"
 
 	"github.com/google/uuid"
 )
 
+// ErrCategoryHasCourses indica que a categoria ainda possui cursos e não pode ser removida
+var ErrCategoryHasCourses = errors.New("category has courses")
+
 type Category struct {
 	db          *sql.DB
 	ID          string
@@ -60,3 +65,92 @@ func (c *Category) GetByID(id string) (Category, error) {
 	}
 	return category, nil
 }
+
+// Update altera nome e descrição; retorna sql.ErrNoRows se a categoria não existir
+func (c *Category) Update(id, name, description string) (Category, error) {
+	result, err := c.db.Exec("UPDATE categories SET name = ?, description = ? WHERE id = ?", name, description, id)
+	if err != nil {
+		return Category{}, err
+	}
+	if err := checkAffected(result); err != nil {
+		return Category{}, err
+	}
+	return Category{ID: id, Name: name, Description: description}, nil
+}
+
+// Delete remove a categoria; retorna ErrCategoryHasCourses se ainda houver cursos e
+// sql.ErrNoRows se ela não existir. O SQLite não garante a FK sem PRAGMA foreign_keys, então
+// a contagem e o DELETE rodam na mesma transação.
+func (c *Category) Delete(id string) error {
+	tx, err := c.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var courses int
+	err = tx.QueryRow("SELECT COUNT(*) FROM courses WHERE category_id = ?", id).Scan(&courses)
+	if err != nil {
+		return err
+	}
+	if courses > 0 {
+		return ErrCategoryHasCourses
+	}
+
+	result, err := tx.Exec("DELETE FROM categories WHERE id = ?", id)
+	if err != nil {
+		return err
+	}
+	if err := checkAffected(result); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// GetByIDs busca várias categorias em uma única query com IN (...)
+func (c *Category) GetByIDs(ids []string) ([]Category, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	rows, err := c.db.Query("SELECT id, name, description FROM categories WHERE id IN ("+placeholders(len(ids))+")", toArgs(ids)...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var categories []Category
+	for rows.Next() {
+		var category Category
+		err := rows.Scan(&category.ID, &category.Name, &category.Description)
+		if err != nil {
+			return nil, err
+		}
+		categories = append(categories, category)
+	}
+	return categories, rows.Err()
+}
+
+// checkAffected converte um UPDATE/DELETE que não encontrou a linha em sql.ErrNoRows
+func checkAffected(result sql.Result) error {
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// placeholders monta "?, ?, ?" para n parâmetros
+func placeholders(n int) string {
+	return strings.TrimSuffix(strings.Repeat("?, ", n), ", ")
+}
+
+func toArgs(values []string) []any {
+	args := make([]any, len(values))
+	for i, v := range values {
+		args[i] = v
+	}
+	return args
+}
@@ -71,6 +71,29 @@ func (c *Course) GetByCategoryID(categoryID string) ([]Course, error) {
 	return courses, nil
 }
 
+// GetByCategoryIDs busca os cursos de várias categorias em uma única query com IN (...)
+func (c *Course) GetByCategoryIDs(categoryIDs []string) ([]Course, error) {
+	if len(categoryIDs) == 0 {
+		return nil, nil
+	}
+	rows, err := c.db.Query("SELECT id, name, description, category_id FROM courses WHERE category_id IN ("+placeholders(len(categoryIDs))+")", toArgs(categoryIDs)...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var courses []Course
+	for rows.Next() {
+		var course Course
+		err = rows.Scan(&course.ID, &course.Name, &course.Description, &course.CategoryID)
+		if err != nil {
+			return nil, err
+		}
+		courses = append(courses, course)
+	}
+	return courses, rows.Err()
+}
+
 func (c *Course) GetByID(id string) (Course, error) {
 	var course Course
 	err := c.db.QueryRow("SELECT id, name, description, category_id FROM courses WHERE id = ?", id).Scan(&course.ID, &course.Name, &course.Description, &course.CategoryID)
@@ -80,6 +103,27 @@ func (c *Course) GetByID(id string) (Course, error) {
 	return course, nil
 }
 
+// Update altera os dados do curso; retorna sql.ErrNoRows se o curso não existir
+func (c *Course) Update(id, name, description, categoryID string) (Course, error) {
+	result, err := c.db.Exec("UPDATE courses SET name = ?, description = ?, category_id = ? WHERE id = ?", name, description, categoryID, id)
+	if err != nil {
+		return Course{}, err
+	}
+	if err := checkAffected(result); err != nil {
+		return Course{}, err
+	}
+	return Course{ID: id, Name: name, Description: description, CategoryID: categoryID}, nil
+}
+
+// Delete remove o curso; retorna sql.ErrNoRows se ele não existir
+func (c *Course) Delete(id string) error {
+	result, err := c.db.Exec("DELETE FROM courses WHERE id = ?", id)
+	if err != nil {
+		return err
+	}
+	return checkAffected(result)
+}
+
 // CourseWithCategory representa um curso com dados da categoria
 type CourseWithCategory struct {
 	CourseID            string
@@ -0,0 +1,111 @@
+// Package dataloader agrupa as buscas por chave feitas dentro de uma janela curta
+// em uma única chamada ao banco, guardando o resultado de cada chave enquanto o
+// loader viver (um loader por requisição).
+package dataloader
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrNotFound é retornado quando a chave não aparece no resultado do lote.
+var ErrNotFound = errors.New("not found")
+
+// FetchFunc busca todas as chaves de um lote de uma vez.
+// Chaves ausentes no mapa retornado resultam em ErrNotFound.
+type FetchFunc[K comparable, V any] func(keys []K) (map[K]V, error)
+
+// Loader acumula chaves por até wait (ou até maxBatch chaves) antes de chamar fetch.
+type Loader[K comparable, V any] struct {
+	fetch    FetchFunc[K, V]
+	wait     time.Duration
+	maxBatch int
+
+	mu      sync.Mutex
+	cache   map[K]*result[V]
+	current *batch[K, V]
+}
+
+type result[V any] struct {
+	value V
+	err   error
+	done  chan struct{}
+}
+
+type batch[K comparable, V any] struct {
+	keys       []K
+	results    []*result[V]
+	dispatched bool
+}
+
+// New cria um loader. maxBatch <= 0 significa lotes sem limite de tamanho.
+func New[K comparable, V any](fetch FetchFunc[K, V], wait time.Duration, maxBatch int) *Loader[K, V] {
+	return &Loader[K, V]{
+		fetch:    fetch,
+		wait:     wait,
+		maxBatch: maxBatch,
+		cache:    make(map[K]*result[V]),
+	}
+}
+
+// Load retorna o valor da chave, esperando o lote em que ela entrou ser buscado.
+// Chaves repetidas reaproveitam o resultado já buscado (ou em andamento).
+func (l *Loader[K, V]) Load(key K) (V, error) {
+	l.mu.Lock()
+	if r, ok := l.cache[key]; ok {
+		l.mu.Unlock()
+		<-r.done
+		return r.value, r.err
+	}
+
+	r := &result[V]{done: make(chan struct{})}
+	l.cache[key] = r
+
+	if l.current == nil {
+		b := &batch[K, V]{}
+		l.current = b
+		time.AfterFunc(l.wait, func() { l.dispatch(b) })
+	}
+	b := l.current
+	b.keys = append(b.keys, key)
+	b.results = append(b.results, r)
+	full := l.maxBatch > 0 && len(b.keys) >= l.maxBatch
+	l.mu.Unlock()
+
+	if full {
+		l.dispatch(b)
+	}
+
+	<-r.done
+	return r.value, r.err
+}
+
+func (l *Loader[K, V]) dispatch(b *batch[K, V]) {
+	l.mu.Lock()
+	if b.dispatched {
+		l.mu.Unlock()
+		return
+	}
+	b.dispatched = true
+	if l.current == b {
+		l.current = nil
+	}
+	l.mu.Unlock()
+
+	values, err := l.fetch(b.keys)
+	for i, key := range b.keys {
+		r := b.results[i]
+		switch {
+		case err != nil:
+			r.err = err
+		default:
+			value, ok := values[key]
+			if !ok {
+				r.err = ErrNotFound
+			}
+			r.value = value
+		}
+		close(r.done)
+	}
+}
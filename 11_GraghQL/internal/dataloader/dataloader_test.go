@@ -0,0 +1,106 @@
+package dataloader
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestLoadBatchesConcurrentKeys(t *testing.T) {
+	var mu sync.Mutex
+	var calls [][]int
+	loader := New(func(keys []int) (map[int]string, error) {
+		mu.Lock()
+		calls = append(calls, append([]int(nil), keys...))
+		mu.Unlock()
+		values := make(map[int]string, len(keys))
+		for _, k := range keys {
+			if k != 99 {
+				values[k] = string(rune('a' + k))
+			}
+		}
+		return values, nil
+	}, 10*time.Millisecond, 0)
+
+	keys := []int{0, 1, 2, 1, 0, 99}
+	got := make([]string, len(keys))
+	errs := make([]error, len(keys))
+	var wg sync.WaitGroup
+	for i, k := range keys {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			got[i], errs[i] = loader.Load(k)
+		}()
+	}
+	wg.Wait()
+
+	if len(calls) != 1 {
+		t.Fatalf("expected 1 fetch, got %d: %v", len(calls), calls)
+	}
+	if len(calls[0]) != 4 {
+		t.Fatalf("expected 4 distinct keys in the batch, got %v", calls[0])
+	}
+	for i, k := range keys {
+		if k == 99 {
+			if !errors.Is(errs[i], ErrNotFound) {
+				t.Fatalf("expected ErrNotFound for missing key, got %v", errs[i])
+			}
+			continue
+		}
+		if errs[i] != nil || got[i] != string(rune('a'+k)) {
+			t.Fatalf("key %d: got %q, %v", k, got[i], errs[i])
+		}
+	}
+
+	// chave já carregada vem do cache, sem nova busca
+	if v, err := loader.Load(2); err != nil || v != "c" {
+		t.Fatalf("cached load: got %q, %v", v, err)
+	}
+	if len(calls) != 1 {
+		t.Fatalf("expected cached load to skip fetch, got %d fetches", len(calls))
+	}
+}
+
+func TestLoadSplitsAtMaxBatch(t *testing.T) {
+	var mu sync.Mutex
+	var sizes []int
+	loader := New(func(keys []int) (map[int]int, error) {
+		mu.Lock()
+		sizes = append(sizes, len(keys))
+		mu.Unlock()
+		values := make(map[int]int, len(keys))
+		for _, k := range keys {
+			values[k] = k * 10
+		}
+		return values, nil
+	}, time.Hour, 2)
+
+	var wg sync.WaitGroup
+	for k := range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if v, err := loader.Load(k); err != nil || v != k*10 {
+				t.Errorf("key %d: got %d, %v", k, v, err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if len(sizes) != 2 || sizes[0] != 2 || sizes[1] != 2 {
+		t.Fatalf("expected two batches of 2, got %v", sizes)
+	}
+}
+
+func TestLoadPropagatesFetchError(t *testing.T) {
+	boom := errors.New("boom")
+	loader := New(func(keys []string) (map[string]int, error) {
+		return nil, boom
+	}, time.Millisecond, 0)
+
+	if _, err := loader.Load("x"); !errors.Is(err, boom) {
+		t.Fatalf("expected fetch error, got %v", err)
+	}
+}
@@ -1,39 +1,172 @@
 package main
 
 import (
+	"context"
 	"database/sql"
+	"errors"
 	"log"
+	"log/slog"
 	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
 
+	"github.com/ElizCarvalho/FC_PosGolang/13_gRPC_FC/internal/auth"
+	"github.com/ElizCarvalho/FC_PosGolang/13_gRPC_FC/internal/config"
 	"github.com/ElizCarvalho/FC_PosGolang/13_gRPC_FC/internal/database"
+	"github.com/ElizCarvalho/FC_PosGolang/13_gRPC_FC/internal/gateway"
+	"github.com/ElizCarvalho/FC_PosGolang/13_gRPC_FC/internal/healthcheck"
 	"github.com/ElizCarvalho/FC_PosGolang/13_gRPC_FC/internal/pb"
 	"github.com/ElizCarvalho/FC_PosGolang/13_gRPC_FC/internal/service"
+	grpcgateway "github.com/ElizCarvalho/FC_PosGolang/pkg/grpckit/gateway"
+	"github.com/ElizCarvalho/FC_PosGolang/pkg/grpckit/interceptor"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 	"google.golang.org/grpc/reflection"
 
 	_ "github.com/mattn/go-sqlite3"
 )
 
 func main() {
+	cfg := config.LoadServer()
+	logger := slog.New(slog.NewJSONHandler(os.Stdout, nil))
+
 	db, err := sql.Open("sqlite3", "file:./db.dbgrpc")
 	if err != nil {
 		panic(err)
 	}
 	defer db.Close()
 
+	if err := database.CreateTables(db); err != nil {
+		panic(err)
+	}
+
 	categoryDB := database.NewCategory(db)
 	categoryService := service.NewCategoryService(*categoryDB)
+	courseDB := database.NewCourse(db)
+	courseService := service.NewCourseService(*courseDB, *categoryDB)
+
+	creds, err := cfg.ServerCredentials()
+	if err != nil {
+		log.Fatalf("Falha ao carregar TLS: %v", err)
+	}
 
-	grpcServer := grpc.NewServer()
+	// log, métricas, recuperação de panics, autenticação, prazo e validação em todas as chamadas
+	interceptorOptions := interceptor.Options{
+		Logger:  logger,
+		Metrics: interceptor.NewMetrics(prometheus.DefaultRegisterer),
+	}
+	if cfg.JWTSecret != "" {
+		interceptorOptions.Auth = auth.NewAuthenticator(cfg.JWTSecret, healthcheck.PublicMethods...)
+	} else {
+		logger.Warn("JWT_SECRET not set, gRPC calls are not authenticated")
+	}
+
+	grpcServer := grpc.NewServer(append(
+		interceptor.ServerOptions(interceptorOptions),
+		grpc.Creds(creds),
+	)...)
 	pb.RegisterCategoryServiceServer(grpcServer, categoryService)
-	reflection.Register(grpcServer) // permite que o cliente descubra os serviços disponíveis
+	pb.RegisterCourseServiceServer(grpcServer, courseService)
+
+	// O gateway fala com uma segunda instância em memória, com os mesmos serviços
+	// e interceptors mas sem TLS, já que a conexão não sai do processo
+	inProcessServer := grpc.NewServer(interceptor.ServerOptions(interceptorOptions)...)
+	pb.RegisterCategoryServiceServer(inProcessServer, categoryService)
+	pb.RegisterCourseServiceServer(inProcessServer, courseService)
+
+	// grpc.health.v1: NOT_SERVING enquanto o banco estiver inacessível
+	healthServer := health.NewServer()
+	healthpb.RegisterHealthServer(grpcServer, healthServer)
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+	go healthcheck.Monitor(ctx, healthServer, db, cfg.HealthCheckInterval, logger)
+
+	if cfg.Reflection {
+		reflection.Register(grpcServer) // permite que o cliente descubra os serviços disponíveis
+	}
 
-	listener, err := net.Listen("tcp", ":50051")
+	listener, err := net.Listen("tcp", cfg.Addr)
 	if err != nil {
 		panic(err)
 	}
 	defer listener.Close()
 
-	log.Printf("Server is running on port %s", listener.Addr().String())
-	log.Fatal(grpcServer.Serve(listener))
+	metricsServer := &http.Server{Addr: cfg.MetricsAddr, Handler: promhttp.Handler()}
+	go func() {
+		log.Printf("Metrics available on %s/metrics", cfg.MetricsAddr)
+		if err := metricsServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Fatal(err)
+		}
+	}()
+
+	var gatewayServer *http.Server
+	if cfg.GatewayAddr != "" {
+		gatewayServer = newGatewayServer(ctx, cfg, inProcessServer)
+		go func() {
+			log.Printf("REST gateway running on %s (OpenAPI em /openapi.json)", cfg.GatewayAddr)
+			var err error
+			if gatewayServer.TLSConfig != nil {
+				err = gatewayServer.ListenAndServeTLS("", "")
+			} else {
+				err = gatewayServer.ListenAndServe()
+			}
+			if err != nil && !errors.Is(err, http.ErrServerClosed) {
+				log.Fatal(err)
+			}
+		}()
+	}
+
+	serveErr := make(chan error, 1)
+	go func() {
+		log.Printf("Server is running on port %s (tls=%t, mtls=%t, auth=%t, reflection=%t)", listener.Addr().String(),
+			cfg.TLSEnabled(), cfg.TLSClientCAFile != "", interceptorOptions.Auth != nil, cfg.Reflection)
+		serveErr <- grpcServer.Serve(listener)
+	}()
+
+	select {
+	case err := <-serveErr:
+		log.Fatal(err)
+	case <-ctx.Done():
+	}
+
+	// SIGTERM/SIGINT: sai do balanceamento, espera as chamadas em andamento e para
+	log.Println("Shutting down gRPC server...")
+	gatewayStopped := make(chan struct{})
+	go func() {
+		// o servidor em memória só é parado depois das requisições HTTP em andamento
+		if gatewayServer != nil {
+			shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
+			gatewayServer.Shutdown(shutdownCtx)
+			cancel()
+		}
+		inProcessServer.Stop()
+		close(gatewayStopped)
+	}()
+	healthcheck.Drain(grpcServer, healthServer, cfg.DrainDelay, cfg.ShutdownTimeout, logger)
+	<-gatewayStopped
+	metricsServer.Shutdown(context.Background())
+	log.Println("Shutdown complete")
+}
+
+// newGatewayServer sobe o gateway REST/JSON apontando para o servidor em memória.
+// Com TLS habilitado o gateway usa o mesmo certificado (e a mesma exigência de mTLS).
+func newGatewayServer(ctx context.Context, cfg config.Server, inProcessServer *grpc.Server) *http.Server {
+	conn, err := grpcgateway.InProcess(inProcessServer)
+	if err != nil {
+		log.Fatalf("Falha ao conectar o gateway: %v", err)
+	}
+	handler, err := gateway.New(ctx, conn)
+	if err != nil {
+		log.Fatalf("Falha ao registrar o gateway: %v", err)
+	}
+	tlsConfig, err := cfg.TLSConfig()
+	if err != nil {
+		log.Fatalf("Falha ao carregar TLS: %v", err)
+	}
+	return &http.Server{Addr: cfg.GatewayAddr, Handler: handler, TLSConfig: tlsConfig}
 }
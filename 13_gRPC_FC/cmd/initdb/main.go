@@ -0,0 +1,23 @@
+package main
+
+import (
+	"database/sql"
+	"log"
+
+	"github.com/ElizCarvalho/FC_PosGolang/13_gRPC_FC/internal/database"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+func main() {
+	db, err := sql.Open("sqlite3", "file:./db.dbgrpc")
+	if err != nil {
+		panic(err)
+	}
+	defer db.Close()
+
+	if err := database.CreateTables(db); err != nil {
+		log.Fatalf("Falha ao criar tabelas: %v", err)
+	}
+	log.Println("Tabelas categories e courses prontas")
+}
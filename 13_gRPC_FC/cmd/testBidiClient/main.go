@@ -8,14 +8,19 @@ import (
 	"time"
 
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials/insecure"
 
+	"github.com/ElizCarvalho/FC_PosGolang/13_gRPC_FC/internal/config"
 	pb "github.com/ElizCarvalho/FC_PosGolang/13_gRPC_FC/internal/pb"
 )
 
 func main() {
-	// Conecta ao servidor gRPC
-	conn, err := grpc.Dial("localhost:50051", grpc.WithTransportCredentials(insecure.NewCredentials()))
+	// Conecta ao servidor gRPC (TLS e token vêm das variáveis de ambiente)
+	cfg := config.LoadClient()
+	opts, err := cfg.DialOptions()
+	if err != nil {
+		log.Fatalf("Falha ao configurar conexão: %v", err)
+	}
+	conn, err := grpc.NewClient(cfg.Addr, opts...)
 	if err != nil {
 		log.Fatalf("Falha ao conectar: %v", err)
 	}
@@ -40,7 +45,7 @@ func main() {
 	go func() {
 		count := 0
 		for {
-			category, err := stream.Recv()
+			result, err := stream.Recv()
 			if err == io.EOF {
 				// Servidor terminou de enviar
 				fmt.Println("\n✅ Servidor terminou de enviar respostas")
@@ -52,7 +57,12 @@ func main() {
 			}
 
 			count++
-			fmt.Printf("📥 [%d] Recebido: %s (ID: %s)\n", count, category.Name, category.Id)
+			if result.Error != "" {
+				// o erro é só deste item; o stream continua aberto
+				fmt.Printf("⚠️  [%d] Item %d falhou: %s\n", count, result.Index+1, result.Error)
+				continue
+			}
+			fmt.Printf("📥 [%d] Recebido: %s (ID: %s)\n", count, result.Category.Name, result.Category.Id)
 		}
 	}()
 
@@ -64,6 +74,7 @@ func main() {
 		{"Backend", "Desenvolvimento backend"},
 		{"Frontend", "Desenvolvimento frontend"},
 		{"DevOps", "Infraestrutura e deploy"},
+		{"", "Sem nome: volta com erro e o stream segue"},
 		{"Mobile", "Desenvolvimento mobile"},
 		{"Data Science", "Ciência de dados"},
 	}
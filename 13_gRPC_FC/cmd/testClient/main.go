@@ -8,14 +8,19 @@ import (
 	"time"
 
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials/insecure"
 
+	"github.com/ElizCarvalho/FC_PosGolang/13_gRPC_FC/internal/config"
 	pb "github.com/ElizCarvalho/FC_PosGolang/13_gRPC_FC/internal/pb"
 )
 
 func main() {
-	// Conecta ao servidor gRPC
-	conn, err := grpc.Dial("localhost:50051", grpc.WithTransportCredentials(insecure.NewCredentials()))
+	// Conecta ao servidor gRPC (TLS e token vêm das variáveis de ambiente)
+	cfg := config.LoadClient()
+	opts, err := cfg.DialOptions()
+	if err != nil {
+		log.Fatalf("Falha ao configurar conexão: %v", err)
+	}
+	conn, err := grpc.NewClient(cfg.Addr, opts...)
 	if err != nil {
 		log.Fatalf("Falha ao conectar: %v", err)
 	}
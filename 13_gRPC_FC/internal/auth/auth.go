@@ -0,0 +1,106 @@
+// Package auth autentica as chamadas gRPC com o bearer token JWT da 7_APIS.
+// O Authenticator entra na cadeia do pkg/grpckit/interceptor pelo campo Auth.
+package auth
+
+import (
+	"context"
+	"strings"
+
+	"github.com/go-chi/jwtauth"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+type userIDKey struct{}
+
+// Authenticator valida o bearer token de cada chamada. Os tokens são os mesmos
+// emitidos pela 7_APIS em /users/generate_token: HS256, com user_id e exp.
+type Authenticator struct {
+	tokenAuth *jwtauth.JWTAuth
+	public    map[string]bool
+}
+
+// NewAuthenticator cria o validador com o JWT_SECRET compartilhado. Os métodos
+// em publicMethods (nome completo, ex.: "/grpc.health.v1.Health/Check") não exigem token.
+func NewAuthenticator(secret string, publicMethods ...string) *Authenticator {
+	public := make(map[string]bool, len(publicMethods))
+	for _, method := range publicMethods {
+		public[method] = true
+	}
+	return &Authenticator{
+		tokenAuth: jwtauth.New("HS256", []byte(secret), nil),
+		public:    public,
+	}
+}
+
+func (a *Authenticator) UnaryInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		ctx, err := a.authenticate(ctx, info.FullMethod)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+func (a *Authenticator) StreamInterceptor() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := a.authenticate(ss.Context(), info.FullMethod)
+		if err != nil {
+			return err
+		}
+		return handler(srv, &contextStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+func (a *Authenticator) authenticate(ctx context.Context, method string) (context.Context, error) {
+	if a.public[method] {
+		return ctx, nil
+	}
+
+	tokenString, ok := bearerToken(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "missing bearer token")
+	}
+	token, err := jwtauth.VerifyToken(a.tokenAuth, tokenString)
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, "invalid token")
+	}
+	userID, _ := token.Get("user_id")
+	id, _ := userID.(string)
+	if id == "" {
+		return nil, status.Error(codes.Unauthenticated, "invalid token")
+	}
+	return context.WithValue(ctx, userIDKey{}, id), nil
+}
+
+// UserID devolve o usuário autenticado na chamada
+func UserID(ctx context.Context) (string, bool) {
+	id, ok := ctx.Value(userIDKey{}).(string)
+	return id, ok
+}
+
+func bearerToken(ctx context.Context) (string, bool) {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return "", false
+	}
+	for _, value := range md.Get("authorization") {
+		if len(value) > 7 && strings.EqualFold(value[:7], "bearer ") {
+			return strings.TrimSpace(value[7:]), true
+		}
+	}
+	return "", false
+}
+
+// contextStream troca o contexto do stream para que o handler enxergue o usuário
+type contextStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *contextStream) Context() context.Context {
+	return s.ctx
+}
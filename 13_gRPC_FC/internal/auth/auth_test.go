@@ -0,0 +1,79 @@
+package auth
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-chi/jwtauth"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const testSecret = "secret"
+
+var unaryInfo = &grpc.UnaryServerInfo{FullMethod: "/pb.CategoryService/CreateCategory"}
+
+func assertCode(t *testing.T, err error, want codes.Code) {
+	t.Helper()
+	if got := status.Code(err); got != want {
+		t.Fatalf("expected %s, got %s (%v)", want, got, err)
+	}
+}
+
+// newToken gera um token no mesmo formato do /users/generate_token da 7_APIS
+func newToken(t *testing.T, secret string, claims map[string]interface{}) string {
+	t.Helper()
+	_, token, err := jwtauth.New("HS256", []byte(secret), nil).Encode(claims)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return token
+}
+
+func withAuthorization(value string) context.Context {
+	return metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", value))
+}
+
+func TestAuthenticator(t *testing.T) {
+	auth := NewAuthenticator(testSecret, "/pb.CategoryService/ListCategories")
+	var userID string
+	handler := func(info *grpc.UnaryServerInfo) grpc.UnaryHandler {
+		return func(ctx context.Context, req any) (any, error) {
+			return auth.UnaryInterceptor()(ctx, req, info, func(ctx context.Context, req any) (any, error) {
+				userID, _ = UserID(ctx)
+				return nil, nil
+			})
+		}
+	}
+	protected := handler(unaryInfo)
+	exp := time.Now().Add(time.Minute).Unix()
+
+	valid := newToken(t, testSecret, map[string]interface{}{"user_id": "user-1", "exp": exp})
+	_, err := protected(withAuthorization("Bearer "+valid), nil)
+	assertCode(t, err, codes.OK)
+	if userID != "user-1" {
+		t.Fatalf("expected user-1 in context, got %q", userID)
+	}
+
+	cases := map[string]context.Context{
+		"missing":      context.Background(),
+		"not bearer":   withAuthorization("Basic dXNlcjpwYXNz"),
+		"wrong secret": withAuthorization("Bearer " + newToken(t, "other", map[string]interface{}{"user_id": "user-1", "exp": exp})),
+		"expired":      withAuthorization("Bearer " + newToken(t, testSecret, map[string]interface{}{"user_id": "user-1", "exp": time.Now().Add(-time.Minute).Unix()})),
+		"no user":      withAuthorization("Bearer " + newToken(t, testSecret, map[string]interface{}{"exp": exp})),
+	}
+	for name, ctx := range cases {
+		_, err := protected(ctx, nil)
+		if err == nil {
+			t.Fatalf("%s: expected error", name)
+		}
+		assertCode(t, err, codes.Unauthenticated)
+	}
+
+	public := handler(&grpc.UnaryServerInfo{FullMethod: "/pb.CategoryService/ListCategories"})
+	_, err = public(context.Background(), nil)
+	assertCode(t, err, codes.OK)
+}
@@ -0,0 +1,94 @@
+// Package config lê a configuração do servidor e dos clientes gRPC a partir de
+// variáveis de ambiente, com valores padrão para rodar localmente sem TLS.
+package config
+
+import (
+	"os"
+	"strconv"
+	"time"
+)
+
+type Server struct {
+	Addr        string // GRPC_ADDR
+	MetricsAddr string // METRICS_ADDR
+	// GatewayAddr é onde o gateway REST/JSON escuta; vazio desabilita o gateway
+	GatewayAddr string // GATEWAY_ADDR
+	// TLS é habilitado quando cert e key estão configurados; com ClientCAFile
+	// o servidor passa a exigir certificado do cliente (mTLS)
+	TLSCertFile     string // TLS_CERT_FILE
+	TLSKeyFile      string // TLS_KEY_FILE
+	TLSClientCAFile string // TLS_CLIENT_CA_FILE
+	// JWTSecret é o mesmo JWT_SECRET da 7_APIS; vazio desabilita a autenticação
+	JWTSecret string // JWT_SECRET
+	// Reflection expõe a lista de serviços para grpcurl/Evans
+	Reflection bool // GRPC_REFLECTION
+	// HealthCheckInterval é o intervalo entre os pings no banco
+	HealthCheckInterval time.Duration // HEALTH_CHECK_INTERVAL
+	// DrainDelay é o tempo em NOT_SERVING antes de parar de aceitar chamadas
+	DrainDelay time.Duration // SHUTDOWN_DRAIN_DELAY
+	// ShutdownTimeout limita a espera pelas chamadas em andamento no desligamento
+	ShutdownTimeout time.Duration // SHUTDOWN_TIMEOUT
+}
+
+type Client struct {
+	Addr string // GRPC_ADDR
+	// CAFile habilita TLS no cliente, validando o servidor com essa CA
+	CAFile     string // TLS_CA_FILE
+	ServerName string // TLS_SERVER_NAME
+	CertFile   string // TLS_CLIENT_CERT_FILE
+	KeyFile    string // TLS_CLIENT_KEY_FILE
+	Token      string // GRPC_TOKEN
+}
+
+func LoadServer() Server {
+	return Server{
+		Addr:            getEnv("GRPC_ADDR", ":50051"),
+		MetricsAddr:     getEnv("METRICS_ADDR", ":9092"),
+		GatewayAddr:     getEnv("GATEWAY_ADDR", ":8081"),
+		TLSCertFile:     os.Getenv("TLS_CERT_FILE"),
+		TLSKeyFile:      os.Getenv("TLS_KEY_FILE"),
+		TLSClientCAFile: os.Getenv("TLS_CLIENT_CA_FILE"),
+		JWTSecret:       os.Getenv("JWT_SECRET"),
+		Reflection:      getBool("GRPC_REFLECTION"),
+
+		HealthCheckInterval: getDuration("HEALTH_CHECK_INTERVAL", 5*time.Second),
+		DrainDelay:          getDuration("SHUTDOWN_DRAIN_DELAY", 5*time.Second),
+		ShutdownTimeout:     getDuration("SHUTDOWN_TIMEOUT", 30*time.Second),
+	}
+}
+
+func LoadClient() Client {
+	return Client{
+		Addr:       getEnv("GRPC_ADDR", "localhost:50051"),
+		CAFile:     os.Getenv("TLS_CA_FILE"),
+		ServerName: os.Getenv("TLS_SERVER_NAME"),
+		CertFile:   os.Getenv("TLS_CLIENT_CERT_FILE"),
+		KeyFile:    os.Getenv("TLS_CLIENT_KEY_FILE"),
+		Token:      os.Getenv("GRPC_TOKEN"),
+	}
+}
+
+func (s Server) TLSEnabled() bool {
+	return s.TLSCertFile != "" && s.TLSKeyFile != ""
+}
+
+func getEnv(key, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return fallback
+}
+
+func getBool(key string) bool {
+	value, _ := strconv.ParseBool(os.Getenv(key))
+	return value
+}
+
+// getDuration aceita valores como "500ms" ou "10s"
+func getDuration(key string, fallback time.Duration) time.Duration {
+	value, err := time.ParseDuration(os.Getenv(key))
+	if err != nil {
+		return fallback
+	}
+	return value
+}
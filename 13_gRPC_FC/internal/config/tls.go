@@ -0,0 +1,115 @@
+package config
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+// ServerCredentials devolve as credenciais de transporte do servidor: TLS quando
+// há certificado configurado, mTLS quando há também uma CA de clientes e
+// conexão sem criptografia caso contrário.
+func (s Server) ServerCredentials() (credentials.TransportCredentials, error) {
+	tlsConfig, err := s.TLSConfig()
+	if err != nil {
+		return nil, err
+	}
+	if tlsConfig == nil {
+		return insecure.NewCredentials(), nil
+	}
+	return credentials.NewTLS(tlsConfig), nil
+}
+
+// TLSConfig é a configuração usada pelo gRPC e pelo gateway HTTP; nil quando
+// TLS não está habilitado.
+func (s Server) TLSConfig() (*tls.Config, error) {
+	if !s.TLSEnabled() {
+		if s.TLSClientCAFile != "" {
+			return nil, errors.New("TLS_CLIENT_CA_FILE requires TLS_CERT_FILE and TLS_KEY_FILE")
+		}
+		return nil, nil
+	}
+
+	cert, err := tls.LoadX509KeyPair(s.TLSCertFile, s.TLSKeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("loading server certificate: %w", err)
+	}
+	tlsConfig := &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		MinVersion:   tls.VersionTLS12,
+	}
+	if s.TLSClientCAFile != "" {
+		pool, err := loadCertPool(s.TLSClientCAFile)
+		if err != nil {
+			return nil, err
+		}
+		tlsConfig.ClientCAs = pool
+		tlsConfig.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+	return tlsConfig, nil
+}
+
+// DialOptions monta as opções de conexão do cliente: TLS (com certificado de
+// cliente para mTLS) quando CAFile está configurado e o token enviado em cada RPC.
+func (c Client) DialOptions() ([]grpc.DialOption, error) {
+	creds := insecure.NewCredentials()
+	if c.CAFile != "" {
+		pool, err := loadCertPool(c.CAFile)
+		if err != nil {
+			return nil, err
+		}
+		tlsConfig := &tls.Config{
+			RootCAs:    pool,
+			ServerName: c.ServerName,
+			MinVersion: tls.VersionTLS12,
+		}
+		if c.CertFile != "" || c.KeyFile != "" {
+			cert, err := tls.LoadX509KeyPair(c.CertFile, c.KeyFile)
+			if err != nil {
+				return nil, fmt.Errorf("loading client certificate: %w", err)
+			}
+			tlsConfig.Certificates = []tls.Certificate{cert}
+		}
+		creds = credentials.NewTLS(tlsConfig)
+	}
+
+	opts := []grpc.DialOption{grpc.WithTransportCredentials(creds)}
+	if c.Token != "" {
+		opts = append(opts, grpc.WithPerRPCCredentials(BearerToken{Token: c.Token, Insecure: c.CAFile == ""}))
+	}
+	return opts, nil
+}
+
+// BearerToken envia o JWT no metadata "authorization" de cada chamada. Sem TLS o
+// token trafega em texto puro, por isso Insecure precisa ser explícito.
+type BearerToken struct {
+	Token    string
+	Insecure bool
+}
+
+func (t BearerToken) GetRequestMetadata(ctx context.Context, uri ...string) (map[string]string, error) {
+	return map[string]string{"authorization": "Bearer " + t.Token}, nil
+}
+
+func (t BearerToken) RequireTransportSecurity() bool {
+	return !t.Insecure
+}
+
+func loadCertPool(file string) (*x509.CertPool, error) {
+	pem, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("reading CA file: %w", err)
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, fmt.Errorf("no certificates found in %s", file)
+	}
+	return pool, nil
+}
@@ -0,0 +1,178 @@
+package config
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ElizCarvalho/FC_PosGolang/13_gRPC_FC/internal/auth"
+	"github.com/ElizCarvalho/FC_PosGolang/pkg/grpckit/interceptor"
+	"github.com/go-chi/jwtauth"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// testCA gera uma CA autoassinada e emite certificados a partir dela
+type testCA struct {
+	dir  string
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	file string
+}
+
+func newTestCA(t *testing.T) *testCA {
+	t.Helper()
+	key, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, _ := x509.ParseCertificate(der)
+	ca := &testCA{dir: t.TempDir(), cert: cert, key: key}
+	ca.file = ca.write(t, "ca.pem", "CERTIFICATE", der)
+	return ca
+}
+
+// issue emite um certificado de servidor (para localhost) ou de cliente e devolve
+// os caminhos do certificado e da chave
+func (ca *testCA) issue(t *testing.T, name string, usage x509.ExtKeyUsage) (string, string) {
+	t.Helper()
+	key, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: name},
+		DNSNames:     []string{"localhost"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{usage},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, ca.cert, &key.PublicKey, ca.key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, _ := x509.MarshalECPrivateKey(key)
+	return ca.write(t, name+".pem", "CERTIFICATE", der), ca.write(t, name+"-key.pem", "EC PRIVATE KEY", keyDER)
+}
+
+func (ca *testCA) write(t *testing.T, name, blockType string, der []byte) string {
+	t.Helper()
+	path := filepath.Join(ca.dir, name)
+	if err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// startServer sobe um servidor com o serviço de health (protegido pela
+// autenticação) e devolve uma função para conectar com a configuração do cliente
+func startServer(t *testing.T, cfg Server) func(Client) healthpb.HealthClient {
+	t.Helper()
+	creds, err := cfg.ServerCredentials()
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := grpc.NewServer(append(
+		interceptor.ServerOptions(interceptor.Options{Auth: auth.NewAuthenticator(cfg.JWTSecret)}),
+		grpc.Creds(creds),
+	)...)
+	healthpb.RegisterHealthServer(server, health.NewServer())
+
+	listener := bufconn.Listen(1024 * 1024)
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	return func(client Client) healthpb.HealthClient {
+		opts, err := client.DialOptions()
+		if err != nil {
+			t.Fatal(err)
+		}
+		opts = append(opts, grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}))
+		conn, err := grpc.NewClient("passthrough:///localhost", opts...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() { conn.Close() })
+		return healthpb.NewHealthClient(conn)
+	}
+}
+
+func check(client healthpb.HealthClient) codes.Code {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	_, err := client.Check(ctx, &healthpb.HealthCheckRequest{})
+	return status.Code(err)
+}
+
+func validToken(t *testing.T) string {
+	_, token, err := jwtauth.New("HS256", []byte("secret"), nil).Encode(map[string]interface{}{
+		"user_id": "user-1",
+		"exp":     time.Now().Add(time.Minute).Unix(),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return token
+}
+
+func TestTLSWithBearerToken(t *testing.T) {
+	ca := newTestCA(t)
+	certFile, keyFile := ca.issue(t, "server", x509.ExtKeyUsageServerAuth)
+	connect := startServer(t, Server{TLSCertFile: certFile, TLSKeyFile: keyFile, JWTSecret: "secret"})
+
+	if code := check(connect(Client{CAFile: ca.file, Token: validToken(t)})); code != codes.OK {
+		t.Fatalf("expected OK over TLS, got %s", code)
+	}
+	if code := check(connect(Client{CAFile: ca.file})); code != codes.Unauthenticated {
+		t.Fatalf("expected Unauthenticated without token, got %s", code)
+	}
+	if code := check(connect(Client{Token: validToken(t)})); code != codes.Unavailable {
+		t.Fatalf("expected plaintext client to be rejected, got %s", code)
+	}
+}
+
+func TestMutualTLS(t *testing.T) {
+	ca := newTestCA(t)
+	certFile, keyFile := ca.issue(t, "server", x509.ExtKeyUsageServerAuth)
+	clientCert, clientKey := ca.issue(t, "client", x509.ExtKeyUsageClientAuth)
+	connect := startServer(t, Server{TLSCertFile: certFile, TLSKeyFile: keyFile, TLSClientCAFile: ca.file, JWTSecret: "secret"})
+
+	withCert := Client{CAFile: ca.file, CertFile: clientCert, KeyFile: clientKey, Token: validToken(t)}
+	if code := check(connect(withCert)); code != codes.OK {
+		t.Fatalf("expected OK with client certificate, got %s", code)
+	}
+	if code := check(connect(Client{CAFile: ca.file, Token: validToken(t)})); code != codes.Unavailable {
+		t.Fatalf("expected client without certificate to be rejected, got %s", code)
+	}
+}
+
+func TestServerCredentialsRequiresCertForMTLS(t *testing.T) {
+	if _, err := (Server{TLSClientCAFile: "ca.pem"}).ServerCredentials(); err == nil {
+		t.Fatal("expected error when client CA is set without server certificate")
+	}
+}
@@ -1,12 +1,17 @@
 package database
 
 import (
+	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 
 	"github.com/google/uuid"
 )
 
+// ErrCategoryHasCourses indica que a categoria ainda possui cursos e não pode ser removida
+var ErrCategoryHasCourses = errors.New("category has courses")
+
 type Category struct {
 	db          *sql.DB
 	ID          string
@@ -62,13 +67,113 @@ func (c *Category) GetByID(id string) (Category, error) {
 	return category, nil
 }
 
-// CreateMultiple cria múltiplas categorias simuladas para demonstração do streaming
+// Update altera nome e descrição da categoria, retornando sql.ErrNoRows se ela não existir
+func (c *Category) Update(id, name, description string) (Category, error) {
+	result, err := c.db.Exec("UPDATE categories SET name = ?, description = ? WHERE id = ?", name, description, id)
+	if err != nil {
+		return Category{}, err
+	}
+	if err := checkAffected(result); err != nil {
+		return Category{}, err
+	}
+	return Category{ID: id, Name: name, Description: description}, nil
+}
+
+// Delete remove a categoria, recusando se ainda houver cursos associados. A contagem e o
+// DELETE rodam na mesma transação, para um curso criado entre os dois não ficar órfão.
+func (c *Category) Delete(id string) error {
+	tx, err := c.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var courses int
+	err = tx.QueryRow("SELECT COUNT(*) FROM courses WHERE category_id = ?", id).Scan(&courses)
+	if err != nil {
+		return err
+	}
+	if courses > 0 {
+		return ErrCategoryHasCourses
+	}
+
+	result, err := tx.Exec("DELETE FROM categories WHERE id = ?", id)
+	if err != nil {
+		return err
+	}
+	if err := checkAffected(result); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// checkAffected converte um UPDATE/DELETE que não afetou linhas em sql.ErrNoRows
+func checkAffected(result sql.Result) error {
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// CategoryBulk grava várias categorias numa única transação: ou todas são
+// persistidas no Commit, ou nenhuma.
+type CategoryBulk struct {
+	tx   *sql.Tx
+	stmt *sql.Stmt
+}
+
+func (c *Category) BeginBulk(ctx context.Context) (*CategoryBulk, error) {
+	tx, err := c.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	stmt, err := tx.PrepareContext(ctx, "INSERT INTO categories (id, name, description) VALUES (?, ?, ?)")
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	return &CategoryBulk{tx: tx, stmt: stmt}, nil
+}
+
+func (b *CategoryBulk) Add(name, description string) (Category, error) {
+	id := uuid.New().String()
+	if _, err := b.stmt.Exec(id, name, description); err != nil {
+		return Category{}, err
+	}
+	return Category{ID: id, Name: name, Description: description}, nil
+}
+
+func (b *CategoryBulk) Commit() error {
+	b.stmt.Close()
+	return b.tx.Commit()
+}
+
+// Rollback desfaz o lote; depois de um Commit bem-sucedido não tem efeito
+func (b *CategoryBulk) Rollback() error {
+	b.stmt.Close()
+	err := b.tx.Rollback()
+	if errors.Is(err, sql.ErrTxDone) {
+		return nil
+	}
+	return err
+}
+
+// CreateMultiple cria múltiplas categorias simuladas para demonstração do
+// streaming, todas na mesma transação
 func (c *Category) CreateMultiple(baseName, baseDescription string, count int) ([]Category, error) {
-	var categories []Category
+	bulk, err := c.BeginBulk(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	defer bulk.Rollback()
 
-	// Simula criação de múltiplas categorias
+	categories := make([]Category, 0, count)
 	for i := 0; i < count; i++ {
-		category, err := c.Create(
+		category, err := bulk.Add(
 			fmt.Sprintf("%s %d", baseName, i+1),
 			fmt.Sprintf("%s - Categoria %d", baseDescription, i+1),
 		)
@@ -78,5 +183,8 @@ func (c *Category) CreateMultiple(baseName, baseDescription string, count int) (
 		categories = append(categories, category)
 	}
 
+	if err := bulk.Commit(); err != nil {
+		return nil, err
+	}
 	return categories, nil
 }
@@ -80,6 +80,27 @@ func (c *Course) GetByID(id string) (Course, error) {
 	return course, nil
 }
 
+// Update altera os dados do curso, retornando sql.ErrNoRows se ele não existir
+func (c *Course) Update(id, name, description, categoryID string) (Course, error) {
+	result, err := c.db.Exec("UPDATE courses SET name = ?, description = ?, category_id = ? WHERE id = ?", name, description, categoryID, id)
+	if err != nil {
+		return Course{}, err
+	}
+	if err := checkAffected(result); err != nil {
+		return Course{}, err
+	}
+	return Course{ID: id, Name: name, Description: description, CategoryID: categoryID}, nil
+}
+
+// Delete remove o curso, retornando sql.ErrNoRows se ele não existir
+func (c *Course) Delete(id string) error {
+	result, err := c.db.Exec("DELETE FROM courses WHERE id = ?", id)
+	if err != nil {
+		return err
+	}
+	return checkAffected(result)
+}
+
 // CourseWithCategory representa um curso com dados da categoria
 type CourseWithCategory struct {
 	CourseID            string
@@ -0,0 +1,22 @@
+package database
+
+import "database/sql"
+
+// CreateTables cria as tabelas usadas pelos serviços caso ainda não existam
+func CreateTables(db *sql.DB) error {
+	_, err := db.Exec(`
+		CREATE TABLE IF NOT EXISTS categories (
+			id TEXT PRIMARY KEY,
+			name TEXT NOT NULL,
+			description TEXT
+		);
+		CREATE TABLE IF NOT EXISTS courses (
+			id TEXT PRIMARY KEY,
+			name TEXT NOT NULL,
+			description TEXT,
+			category_id TEXT NOT NULL REFERENCES categories(id)
+		);
+		CREATE INDEX IF NOT EXISTS idx_courses_category_id ON courses(category_id);
+	`)
+	return err
+}
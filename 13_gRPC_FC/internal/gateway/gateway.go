@@ -0,0 +1,28 @@
+// Package gateway expõe os serviços gRPC como REST/JSON. As rotas vêm das
+// anotações google.api.http em proto/course_category.proto e o código de
+// tradução é gerado pelo protoc-gen-grpc-gateway (internal/pb/*.pb.gw.go). O mux,
+// o JSON e a conexão em memória ficam em pkg/grpckit/gateway, compartilhado com o 20_CleanArch.
+package gateway
+
+import (
+	"context"
+	_ "embed"
+	"net/http"
+
+	"github.com/ElizCarvalho/FC_PosGolang/13_gRPC_FC/internal/pb"
+	grpcgateway "github.com/ElizCarvalho/FC_PosGolang/pkg/grpckit/gateway"
+	"google.golang.org/grpc"
+)
+
+// openAPI é gerado pelo protoc-gen-openapiv2 a partir das mesmas anotações
+//
+//go:embed course_category.swagger.json
+var openAPI []byte
+
+// New monta o handler HTTP do CategoryService e do CourseService sobre conn
+func New(ctx context.Context, conn *grpc.ClientConn) (http.Handler, error) {
+	return grpcgateway.New(ctx, conn, openAPI,
+		pb.RegisterCategoryServiceHandler,
+		pb.RegisterCourseServiceHandler,
+	)
+}
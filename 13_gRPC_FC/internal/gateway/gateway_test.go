@@ -0,0 +1,198 @@
+package gateway
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ElizCarvalho/FC_PosGolang/13_gRPC_FC/internal/auth"
+	"github.com/ElizCarvalho/FC_PosGolang/13_gRPC_FC/internal/database"
+	"github.com/ElizCarvalho/FC_PosGolang/13_gRPC_FC/internal/pb"
+	"github.com/ElizCarvalho/FC_PosGolang/13_gRPC_FC/internal/service"
+	grpcgateway "github.com/ElizCarvalho/FC_PosGolang/pkg/grpckit/gateway"
+	"github.com/ElizCarvalho/FC_PosGolang/pkg/grpckit/interceptor"
+	"github.com/go-chi/jwtauth"
+	"google.golang.org/grpc"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+const testSecret = "secret"
+
+// newTestGateway sobe o gateway na frente de um servidor gRPC em memória com
+// autenticação habilitada e devolve a URL base
+func newTestGateway(t *testing.T) string {
+	t.Helper()
+	db, err := sql.Open("sqlite3", "file:"+t.Name()+"?mode=memory&cache=shared")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	if err := database.CreateTables(db); err != nil {
+		t.Fatal(err)
+	}
+
+	server := grpc.NewServer(interceptor.ServerOptions(interceptor.Options{
+		Logger: slog.New(slog.NewTextHandler(io.Discard, nil)),
+		Auth:   auth.NewAuthenticator(testSecret),
+	})...)
+	categoryDB, courseDB := database.NewCategory(db), database.NewCourse(db)
+	pb.RegisterCategoryServiceServer(server, service.NewCategoryService(*categoryDB))
+	pb.RegisterCourseServiceServer(server, service.NewCourseService(*courseDB, *categoryDB))
+	t.Cleanup(server.Stop)
+
+	conn, err := grpcgateway.InProcess(server)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	handler, err := New(context.Background(), conn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	httpServer := httptest.NewServer(handler)
+	t.Cleanup(httpServer.Close)
+	return httpServer.URL
+}
+
+func token(t *testing.T) string {
+	_, token, err := jwtauth.New("HS256", []byte(testSecret), nil).Encode(map[string]interface{}{
+		"user_id": "user-1",
+		"exp":     time.Now().Add(time.Minute).Unix(),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return token
+}
+
+func do(t *testing.T, method, url, body, bearer string) (int, map[string]any) {
+	t.Helper()
+	req, _ := http.NewRequest(method, url, strings.NewReader(body))
+	if bearer != "" {
+		req.Header.Set("Authorization", "Bearer "+bearer)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	var decoded map[string]any
+	json.NewDecoder(resp.Body).Decode(&decoded)
+	return resp.StatusCode, decoded
+}
+
+func TestCategoryRoutes(t *testing.T) {
+	baseURL := newTestGateway(t)
+	bearer := token(t)
+
+	code, body := do(t, http.MethodPost, baseURL+"/v1/categories", `{"name":"Go","description":"Golang"}`, bearer)
+	if code != http.StatusOK {
+		t.Fatalf("create: expected 200, got %d %v", code, body)
+	}
+	id := body["category"].(map[string]any)["id"].(string)
+
+	code, body = do(t, http.MethodGet, baseURL+"/v1/categories/"+id, "", bearer)
+	if code != http.StatusOK || body["category"].(map[string]any)["name"] != "Go" {
+		t.Fatalf("get: unexpected %d %v", code, body)
+	}
+
+	code, _ = do(t, http.MethodPut, baseURL+"/v1/categories/"+id, `{"name":"Golang"}`, bearer)
+	if code != http.StatusOK {
+		t.Fatalf("update: expected 200, got %d", code)
+	}
+
+	code, body = do(t, http.MethodGet, baseURL+"/v1/categories", "", bearer)
+	if categories := body["categories"].([]any); code != http.StatusOK || len(categories) != 1 {
+		t.Fatalf("list: unexpected %d %v", code, body)
+	}
+
+	code, _ = do(t, http.MethodDelete, baseURL+"/v1/categories/"+id, "", bearer)
+	if code != http.StatusOK {
+		t.Fatalf("delete: expected 200, got %d", code)
+	}
+}
+
+func TestStatusCodesAreMappedToHTTP(t *testing.T) {
+	baseURL := newTestGateway(t)
+	bearer := token(t)
+
+	cases := []struct {
+		name, method, path, body, bearer string
+		want                             int
+	}{
+		{"not found", http.MethodGet, "/v1/categories/missing", "", bearer, http.StatusNotFound},
+		{"invalid argument", http.MethodPost, "/v1/categories", `{"description":"no name"}`, bearer, http.StatusBadRequest},
+		{"unauthenticated", http.MethodGet, "/v1/categories", "", "", http.StatusUnauthorized},
+	}
+	for _, c := range cases {
+		code, body := do(t, c.method, baseURL+c.path, c.body, c.bearer)
+		if code != c.want {
+			t.Fatalf("%s: expected %d, got %d %v", c.name, c.want, code, body)
+		}
+	}
+}
+
+func TestServerStreamingRouteReturnsNDJSON(t *testing.T) {
+	baseURL := newTestGateway(t)
+	bearer := token(t)
+
+	_, body := do(t, http.MethodPost, baseURL+"/v1/categories", `{"name":"Go"}`, bearer)
+	categoryID := body["category"].(map[string]any)["id"].(string)
+	for _, name := range []string{"gRPC", "GraphQL"} {
+		do(t, http.MethodPost, baseURL+"/v1/courses", `{"name":"`+name+`","category_id":"`+categoryID+`"}`, bearer)
+	}
+
+	req, _ := http.NewRequest(http.MethodGet, baseURL+"/v1/categories/"+categoryID+"/courses", nil)
+	req.Header.Set("Authorization", "Bearer "+bearer)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	decoder := json.NewDecoder(resp.Body)
+	var received int
+	for decoder.More() {
+		var line struct {
+			Result map[string]any `json:"result"`
+		}
+		if err := decoder.Decode(&line); err != nil {
+			t.Fatal(err)
+		}
+		if line.Result["category_id"] != categoryID {
+			t.Fatalf("unexpected course %v", line.Result)
+		}
+		received++
+	}
+	if received != 2 {
+		t.Fatalf("expected 2 courses, got %d", received)
+	}
+}
+
+func TestOpenAPIDocument(t *testing.T) {
+	baseURL := newTestGateway(t)
+
+	resp, err := http.Get(baseURL + "/openapi.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	var spec struct {
+		Swagger string                    `json:"swagger"`
+		Paths   map[string]map[string]any `json:"paths"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&spec); err != nil {
+		t.Fatal(err)
+	}
+	if spec.Swagger != "2.0" || spec.Paths["/v1/categories/{id}"]["get"] == nil {
+		t.Fatalf("unexpected OpenAPI document: %v", spec)
+	}
+}
@@ -0,0 +1,88 @@
+// Package healthcheck liga o serviço padrão grpc.health.v1 ao estado do banco e
+// coordena o desligamento do servidor gRPC.
+package healthcheck
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// Services são os nomes reportados pelo health check; "" representa o servidor todo
+var Services = []string{"", "pb.CategoryService", "pb.CourseService"}
+
+// PublicMethods não exigem token, para que orquestradores consigam checar o servidor
+var PublicMethods = []string{
+	healthpb.Health_Check_FullMethodName,
+	healthpb.Health_Watch_FullMethodName,
+}
+
+type Pinger interface {
+	PingContext(ctx context.Context) error
+}
+
+// Monitor atualiza o status de Services a cada interval: SERVING enquanto o
+// banco responde ao ping e NOT_SERVING enquanto está inacessível. Retorna
+// quando ctx é cancelado.
+func Monitor(ctx context.Context, hs *health.Server, db Pinger, interval time.Duration, logger *slog.Logger) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	var serving, known bool
+	for {
+		pingCtx, cancel := context.WithTimeout(ctx, interval)
+		err := db.PingContext(pingCtx)
+		cancel()
+
+		if ctx.Err() != nil {
+			return
+		}
+		if !known || (err == nil) != serving {
+			known, serving = true, err == nil
+			status := healthpb.HealthCheckResponse_SERVING
+			if !serving {
+				status = healthpb.HealthCheckResponse_NOT_SERVING
+				logger.Error("database unreachable, reporting NOT_SERVING", slog.String("error", err.Error()))
+			}
+			for _, service := range Services {
+				hs.SetServingStatus(service, status)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Drain desliga o servidor sem cortar chamadas em andamento: marca todos os
+// serviços como NOT_SERVING, espera drainDelay para os balanceadores pararem de
+// enviar tráfego e chama GracefulStop, que recusa novas chamadas e aguarda as
+// pendentes, inclusive streams como CreateCategoryStreamBidirectional. Se elas
+// não terminarem em timeout, as conexões restantes são encerradas com Stop.
+func Drain(server *grpc.Server, hs *health.Server, drainDelay, timeout time.Duration, logger *slog.Logger) {
+	hs.Shutdown()
+	logger.Info("health set to NOT_SERVING, draining", slog.Duration("delay", drainDelay))
+	time.Sleep(drainDelay)
+
+	stopped := make(chan struct{})
+	go func() {
+		server.GracefulStop()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+		logger.Info("grpc server stopped gracefully")
+	case <-time.After(timeout):
+		logger.Warn("in-flight calls did not finish in time, forcing stop", slog.Duration("timeout", timeout))
+		server.Stop()
+		<-stopped
+	}
+}
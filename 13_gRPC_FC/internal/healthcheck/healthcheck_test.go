@@ -0,0 +1,174 @@
+package healthcheck
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"io"
+	"log/slog"
+	"net"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ElizCarvalho/FC_PosGolang/13_gRPC_FC/internal/database"
+	"github.com/ElizCarvalho/FC_PosGolang/13_gRPC_FC/internal/pb"
+	"github.com/ElizCarvalho/FC_PosGolang/13_gRPC_FC/internal/service"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/test/bufconn"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+var discardLogger = slog.New(slog.NewTextHandler(io.Discard, nil))
+
+type fakePinger struct {
+	down atomic.Bool
+}
+
+func (p *fakePinger) PingContext(ctx context.Context) error {
+	if p.down.Load() {
+		return errors.New("database is down")
+	}
+	return nil
+}
+
+// waitStatus espera o health server chegar ao status esperado
+func waitStatus(t *testing.T, hs *health.Server, service string, want healthpb.HealthCheckResponse_ServingStatus) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		resp, err := hs.Check(context.Background(), &healthpb.HealthCheckRequest{Service: service})
+		if err == nil && resp.Status == want {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("service %q: expected %s, got %v (%v)", service, want, resp.GetStatus(), err)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestMonitorFollowsDatabase(t *testing.T) {
+	hs := health.NewServer()
+	db := &fakePinger{}
+	db.down.Store(true)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go Monitor(ctx, hs, db, 10*time.Millisecond, discardLogger)
+
+	waitStatus(t, hs, "", healthpb.HealthCheckResponse_NOT_SERVING)
+	db.down.Store(false)
+	waitStatus(t, hs, "pb.CategoryService", healthpb.HealthCheckResponse_SERVING)
+	db.down.Store(true)
+	waitStatus(t, hs, "pb.CourseService", healthpb.HealthCheckResponse_NOT_SERVING)
+}
+
+func newTestServer(t *testing.T) (*grpc.Server, *health.Server, *grpc.ClientConn) {
+	t.Helper()
+	db, err := sql.Open("sqlite3", "file:"+t.Name()+"?mode=memory&cache=shared")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	if err := database.CreateTables(db); err != nil {
+		t.Fatal(err)
+	}
+
+	server := grpc.NewServer()
+	hs := health.NewServer()
+	healthpb.RegisterHealthServer(server, hs)
+	pb.RegisterCategoryServiceServer(server, service.NewCategoryService(*database.NewCategory(db)))
+
+	listener := bufconn.Listen(1024 * 1024)
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return listener.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return server, hs, conn
+}
+
+func openBidiStream(t *testing.T, conn *grpc.ClientConn) grpc.BidiStreamingClient[pb.CreateCategoryRequest, pb.CategoryResult] {
+	t.Helper()
+	stream, err := pb.NewCategoryServiceClient(conn).CreateCategoryStreamBidirectional(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	sendAndRecv(t, stream, "Go")
+	return stream
+}
+
+func sendAndRecv(t *testing.T, stream grpc.BidiStreamingClient[pb.CreateCategoryRequest, pb.CategoryResult], name string) {
+	t.Helper()
+	if err := stream.Send(&pb.CreateCategoryRequest{Name: name}); err != nil {
+		t.Fatal(err)
+	}
+	result, err := stream.Recv()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.GetCategory().GetName() != name {
+		t.Fatalf("expected category %q, got %v", name, result)
+	}
+}
+
+func TestDrainWaitsForInFlightStreams(t *testing.T) {
+	server, hs, conn := newTestServer(t)
+	stream := openBidiStream(t, conn)
+
+	drained := make(chan struct{})
+	go func() {
+		Drain(server, hs, 20*time.Millisecond, 5*time.Second, discardLogger)
+		close(drained)
+	}()
+
+	waitStatus(t, hs, "", healthpb.HealthCheckResponse_NOT_SERVING)
+	resp, err := healthpb.NewHealthClient(conn).Check(context.Background(), &healthpb.HealthCheckRequest{})
+	if err != nil || resp.Status != healthpb.HealthCheckResponse_NOT_SERVING {
+		t.Fatalf("expected NOT_SERVING while draining, got %v (%v)", resp.GetStatus(), err)
+	}
+
+	// o stream aberto antes do desligamento continua funcionando até o cliente encerrar
+	time.Sleep(50 * time.Millisecond)
+	sendAndRecv(t, stream, "Python")
+	select {
+	case <-drained:
+		t.Fatal("server stopped with a stream in flight")
+	default:
+	}
+
+	stream.CloseSend()
+	if _, err := stream.Recv(); err != io.EOF {
+		t.Fatalf("expected EOF, got %v", err)
+	}
+	select {
+	case <-drained:
+	case <-time.After(2 * time.Second):
+		t.Fatal("server did not stop after the stream finished")
+	}
+}
+
+func TestDrainForcesStopAfterTimeout(t *testing.T) {
+	server, hs, conn := newTestServer(t)
+	stream := openBidiStream(t, conn)
+
+	start := time.Now()
+	Drain(server, hs, 0, 100*time.Millisecond, discardLogger)
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("drain took %s, expected to force stop after the timeout", elapsed)
+	}
+	if _, err := stream.Recv(); err == nil {
+		t.Fatal("expected stream to be closed by the forced stop")
+	}
+}
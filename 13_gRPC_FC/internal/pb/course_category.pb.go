@@ -7,6 +7,7 @@
 package pb
 
 import (
+	_ "google.golang.org/genproto/googleapis/api/annotations"
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
 	reflect "reflect"
@@ -302,11 +303,743 @@ func (x *CategoryList) GetCategories() []*Category {
 	return nil
 }
 
+// CreateCategoriesSummary resume um CreateCategoriesBulk: todas as categorias
+// foram gravadas na mesma transação
+type CreateCategoriesSummary struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Created       int32                  `protobuf:"varint,1,opt,name=created,proto3" json:"created,omitempty"`
+	Categories    []*Category            `protobuf:"bytes,2,rep,name=categories,proto3" json:"categories,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateCategoriesSummary) Reset() {
+	*x = CreateCategoriesSummary{}
+	mi := &file_proto_course_category_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateCategoriesSummary) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateCategoriesSummary) ProtoMessage() {}
+
+func (x *CreateCategoriesSummary) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_course_category_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateCategoriesSummary.ProtoReflect.Descriptor instead.
+func (*CreateCategoriesSummary) Descriptor() ([]byte, []int) {
+	return file_proto_course_category_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *CreateCategoriesSummary) GetCreated() int32 {
+	if x != nil {
+		return x.Created
+	}
+	return 0
+}
+
+func (x *CreateCategoriesSummary) GetCategories() []*Category {
+	if x != nil {
+		return x.Categories
+	}
+	return nil
+}
+
+// CategoryResult é a resposta por item do stream bidirecional: traz a categoria
+// criada ou o erro daquele item, sem encerrar o stream
+type CategoryResult struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Index         int32                  `protobuf:"varint,1,opt,name=index,proto3" json:"index,omitempty"`
+	Category      *Category              `protobuf:"bytes,2,opt,name=category,proto3" json:"category,omitempty"`
+	Error         string                 `protobuf:"bytes,3,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CategoryResult) Reset() {
+	*x = CategoryResult{}
+	mi := &file_proto_course_category_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CategoryResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CategoryResult) ProtoMessage() {}
+
+func (x *CategoryResult) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_course_category_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CategoryResult.ProtoReflect.Descriptor instead.
+func (*CategoryResult) Descriptor() ([]byte, []int) {
+	return file_proto_course_category_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *CategoryResult) GetIndex() int32 {
+	if x != nil {
+		return x.Index
+	}
+	return 0
+}
+
+func (x *CategoryResult) GetCategory() *Category {
+	if x != nil {
+		return x.Category
+	}
+	return nil
+}
+
+func (x *CategoryResult) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+type UpdateCategoryRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Description   string                 `protobuf:"bytes,3,opt,name=description,proto3" json:"description,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateCategoryRequest) Reset() {
+	*x = UpdateCategoryRequest{}
+	mi := &file_proto_course_category_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateCategoryRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateCategoryRequest) ProtoMessage() {}
+
+func (x *UpdateCategoryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_course_category_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateCategoryRequest.ProtoReflect.Descriptor instead.
+func (*UpdateCategoryRequest) Descriptor() ([]byte, []int) {
+	return file_proto_course_category_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *UpdateCategoryRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *UpdateCategoryRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *UpdateCategoryRequest) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+type DeleteCategoryRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteCategoryRequest) Reset() {
+	*x = DeleteCategoryRequest{}
+	mi := &file_proto_course_category_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteCategoryRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteCategoryRequest) ProtoMessage() {}
+
+func (x *DeleteCategoryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_course_category_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteCategoryRequest.ProtoReflect.Descriptor instead.
+func (*DeleteCategoryRequest) Descriptor() ([]byte, []int) {
+	return file_proto_course_category_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *DeleteCategoryRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type Course struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Description   string                 `protobuf:"bytes,3,opt,name=description,proto3" json:"description,omitempty"`
+	CategoryId    string                 `protobuf:"bytes,4,opt,name=category_id,json=categoryId,proto3" json:"category_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Course) Reset() {
+	*x = Course{}
+	mi := &file_proto_course_category_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Course) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Course) ProtoMessage() {}
+
+func (x *Course) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_course_category_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Course.ProtoReflect.Descriptor instead.
+func (*Course) Descriptor() ([]byte, []int) {
+	return file_proto_course_category_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *Course) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Course) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Course) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *Course) GetCategoryId() string {
+	if x != nil {
+		return x.CategoryId
+	}
+	return ""
+}
+
+type CreateCourseRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Description   string                 `protobuf:"bytes,2,opt,name=description,proto3" json:"description,omitempty"`
+	CategoryId    string                 `protobuf:"bytes,3,opt,name=category_id,json=categoryId,proto3" json:"category_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateCourseRequest) Reset() {
+	*x = CreateCourseRequest{}
+	mi := &file_proto_course_category_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateCourseRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateCourseRequest) ProtoMessage() {}
+
+func (x *CreateCourseRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_course_category_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateCourseRequest.ProtoReflect.Descriptor instead.
+func (*CreateCourseRequest) Descriptor() ([]byte, []int) {
+	return file_proto_course_category_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *CreateCourseRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *CreateCourseRequest) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *CreateCourseRequest) GetCategoryId() string {
+	if x != nil {
+		return x.CategoryId
+	}
+	return ""
+}
+
+type UpdateCourseRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Description   string                 `protobuf:"bytes,3,opt,name=description,proto3" json:"description,omitempty"`
+	CategoryId    string                 `protobuf:"bytes,4,opt,name=category_id,json=categoryId,proto3" json:"category_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateCourseRequest) Reset() {
+	*x = UpdateCourseRequest{}
+	mi := &file_proto_course_category_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateCourseRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateCourseRequest) ProtoMessage() {}
+
+func (x *UpdateCourseRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_course_category_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateCourseRequest.ProtoReflect.Descriptor instead.
+func (*UpdateCourseRequest) Descriptor() ([]byte, []int) {
+	return file_proto_course_category_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *UpdateCourseRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *UpdateCourseRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *UpdateCourseRequest) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *UpdateCourseRequest) GetCategoryId() string {
+	if x != nil {
+		return x.CategoryId
+	}
+	return ""
+}
+
+type GetCourseRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetCourseRequest) Reset() {
+	*x = GetCourseRequest{}
+	mi := &file_proto_course_category_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetCourseRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetCourseRequest) ProtoMessage() {}
+
+func (x *GetCourseRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_course_category_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetCourseRequest.ProtoReflect.Descriptor instead.
+func (*GetCourseRequest) Descriptor() ([]byte, []int) {
+	return file_proto_course_category_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *GetCourseRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type DeleteCourseRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteCourseRequest) Reset() {
+	*x = DeleteCourseRequest{}
+	mi := &file_proto_course_category_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteCourseRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteCourseRequest) ProtoMessage() {}
+
+func (x *DeleteCourseRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_course_category_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteCourseRequest.ProtoReflect.Descriptor instead.
+func (*DeleteCourseRequest) Descriptor() ([]byte, []int) {
+	return file_proto_course_category_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *DeleteCourseRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type ListCoursesByCategoryRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	CategoryId    string                 `protobuf:"bytes,1,opt,name=category_id,json=categoryId,proto3" json:"category_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListCoursesByCategoryRequest) Reset() {
+	*x = ListCoursesByCategoryRequest{}
+	mi := &file_proto_course_category_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListCoursesByCategoryRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListCoursesByCategoryRequest) ProtoMessage() {}
+
+func (x *ListCoursesByCategoryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_course_category_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListCoursesByCategoryRequest.ProtoReflect.Descriptor instead.
+func (*ListCoursesByCategoryRequest) Descriptor() ([]byte, []int) {
+	return file_proto_course_category_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *ListCoursesByCategoryRequest) GetCategoryId() string {
+	if x != nil {
+		return x.CategoryId
+	}
+	return ""
+}
+
+type CourseResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Course        *Course                `protobuf:"bytes,1,opt,name=course,proto3" json:"course,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CourseResponse) Reset() {
+	*x = CourseResponse{}
+	mi := &file_proto_course_category_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CourseResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CourseResponse) ProtoMessage() {}
+
+func (x *CourseResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_course_category_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CourseResponse.ProtoReflect.Descriptor instead.
+func (*CourseResponse) Descriptor() ([]byte, []int) {
+	return file_proto_course_category_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *CourseResponse) GetCourse() *Course {
+	if x != nil {
+		return x.Course
+	}
+	return nil
+}
+
+type CourseList struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Courses       []*Course              `protobuf:"bytes,1,rep,name=courses,proto3" json:"courses,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CourseList) Reset() {
+	*x = CourseList{}
+	mi := &file_proto_course_category_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CourseList) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CourseList) ProtoMessage() {}
+
+func (x *CourseList) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_course_category_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CourseList.ProtoReflect.Descriptor instead.
+func (*CourseList) Descriptor() ([]byte, []int) {
+	return file_proto_course_category_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *CourseList) GetCourses() []*Course {
+	if x != nil {
+		return x.Courses
+	}
+	return nil
+}
+
+type CourseWithCategory struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Course        *Course                `protobuf:"bytes,1,opt,name=course,proto3" json:"course,omitempty"`
+	Category      *Category              `protobuf:"bytes,2,opt,name=category,proto3" json:"category,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CourseWithCategory) Reset() {
+	*x = CourseWithCategory{}
+	mi := &file_proto_course_category_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CourseWithCategory) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CourseWithCategory) ProtoMessage() {}
+
+func (x *CourseWithCategory) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_course_category_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CourseWithCategory.ProtoReflect.Descriptor instead.
+func (*CourseWithCategory) Descriptor() ([]byte, []int) {
+	return file_proto_course_category_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *CourseWithCategory) GetCourse() *Course {
+	if x != nil {
+		return x.Course
+	}
+	return nil
+}
+
+func (x *CourseWithCategory) GetCategory() *Category {
+	if x != nil {
+		return x.Category
+	}
+	return nil
+}
+
+type CourseWithCategoryList struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Courses       []*CourseWithCategory  `protobuf:"bytes,1,rep,name=courses,proto3" json:"courses,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CourseWithCategoryList) Reset() {
+	*x = CourseWithCategoryList{}
+	mi := &file_proto_course_category_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CourseWithCategoryList) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CourseWithCategoryList) ProtoMessage() {}
+
+func (x *CourseWithCategoryList) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_course_category_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CourseWithCategoryList.ProtoReflect.Descriptor instead.
+func (*CourseWithCategoryList) Descriptor() ([]byte, []int) {
+	return file_proto_course_category_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *CourseWithCategoryList) GetCourses() []*CourseWithCategory {
+	if x != nil {
+		return x.Courses
+	}
+	return nil
+}
+
 var File_proto_course_category_proto protoreflect.FileDescriptor
 
 const file_proto_course_category_proto_rawDesc = "" +
 	"\n" +
-	"\x1bproto/course_category.proto\x12\x02pb\"P\n" +
+	"\x1bproto/course_category.proto\x12\x02pb\x1a\x1cgoogle/api/annotations.proto\"P\n" +
 	"\bCategory\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
 	"\x04name\x18\x02 \x01(\tR\x04name\x12 \n" +
@@ -322,13 +1055,77 @@ const file_proto_course_category_proto_rawDesc = "" +
 	"\fCategoryList\x12,\n" +
 	"\n" +
 	"categories\x18\x01 \x03(\v2\f.pb.CategoryR\n" +
-	"categories2\xe3\x02\n" +
-	"\x0fCategoryService\x12C\n" +
-	"\x0eCreateCategory\x12\x19.pb.CreateCategoryRequest\x1a\x14.pb.CategoryResponse\"\x00\x12G\n" +
-	"\x14CreateCategoryStream\x12\x19.pb.CreateCategoryRequest\x1a\x10.pb.CategoryList\"\x000\x01\x12R\n" +
-	"!CreateCategoryStreamBidirectional\x12\x19.pb.CreateCategoryRequest\x1a\f.pb.Category\"\x00(\x010\x01\x12/\n" +
-	"\x0eListCategories\x12\t.pb.blank\x1a\x10.pb.CategoryList\"\x00\x12=\n" +
-	"\vGetCategory\x12\x16.pb.GetCategoryRequest\x1a\x14.pb.CategoryResponse\"\x00B\rZ\vinternal/pbb\x06proto3"
+	"categories\"a\n" +
+	"\x17CreateCategoriesSummary\x12\x18\n" +
+	"\acreated\x18\x01 \x01(\x05R\acreated\x12,\n" +
+	"\n" +
+	"categories\x18\x02 \x03(\v2\f.pb.CategoryR\n" +
+	"categories\"f\n" +
+	"\x0eCategoryResult\x12\x14\n" +
+	"\x05index\x18\x01 \x01(\x05R\x05index\x12(\n" +
+	"\bcategory\x18\x02 \x01(\v2\f.pb.CategoryR\bcategory\x12\x14\n" +
+	"\x05error\x18\x03 \x01(\tR\x05error\"]\n" +
+	"\x15UpdateCategoryRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12 \n" +
+	"\vdescription\x18\x03 \x01(\tR\vdescription\"'\n" +
+	"\x15DeleteCategoryRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"o\n" +
+	"\x06Course\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12 \n" +
+	"\vdescription\x18\x03 \x01(\tR\vdescription\x12\x1f\n" +
+	"\vcategory_id\x18\x04 \x01(\tR\n" +
+	"categoryId\"l\n" +
+	"\x13CreateCourseRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12 \n" +
+	"\vdescription\x18\x02 \x01(\tR\vdescription\x12\x1f\n" +
+	"\vcategory_id\x18\x03 \x01(\tR\n" +
+	"categoryId\"|\n" +
+	"\x13UpdateCourseRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12 \n" +
+	"\vdescription\x18\x03 \x01(\tR\vdescription\x12\x1f\n" +
+	"\vcategory_id\x18\x04 \x01(\tR\n" +
+	"categoryId\"\"\n" +
+	"\x10GetCourseRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"%\n" +
+	"\x13DeleteCourseRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"?\n" +
+	"\x1cListCoursesByCategoryRequest\x12\x1f\n" +
+	"\vcategory_id\x18\x01 \x01(\tR\n" +
+	"categoryId\"4\n" +
+	"\x0eCourseResponse\x12\"\n" +
+	"\x06course\x18\x01 \x01(\v2\n" +
+	".pb.CourseR\x06course\"2\n" +
+	"\n" +
+	"CourseList\x12$\n" +
+	"\acourses\x18\x01 \x03(\v2\n" +
+	".pb.CourseR\acourses\"b\n" +
+	"\x12CourseWithCategory\x12\"\n" +
+	"\x06course\x18\x01 \x01(\v2\n" +
+	".pb.CourseR\x06course\x12(\n" +
+	"\bcategory\x18\x02 \x01(\v2\f.pb.CategoryR\bcategory\"J\n" +
+	"\x16CourseWithCategoryList\x120\n" +
+	"\acourses\x18\x01 \x03(\v2\x16.pb.CourseWithCategoryR\acourses2\xfd\x05\n" +
+	"\x0fCategoryService\x12\\\n" +
+	"\x0eCreateCategory\x12\x19.pb.CreateCategoryRequest\x1a\x14.pb.CategoryResponse\"\x19\x82\xd3\xe4\x93\x02\x13:\x01*\"\x0e/v1/categories\x12g\n" +
+	"\x14CreateCategoryStream\x12\x19.pb.CreateCategoryRequest\x1a\x10.pb.CategoryList\" \x82\xd3\xe4\x93\x02\x1a:\x01*\"\x15/v1/categories:stream0\x01\x12X\n" +
+	"!CreateCategoryStreamBidirectional\x12\x19.pb.CreateCategoryRequest\x1a\x12.pb.CategoryResult\"\x00(\x010\x01\x12p\n" +
+	"\x14CreateCategoriesBulk\x12\x19.pb.CreateCategoryRequest\x1a\x1b.pb.CreateCategoriesSummary\"\x1e\x82\xd3\xe4\x93\x02\x18:\x01*\"\x13/v1/categories:bulk(\x01\x12E\n" +
+	"\x0eListCategories\x12\t.pb.blank\x1a\x10.pb.CategoryList\"\x16\x82\xd3\xe4\x93\x02\x10\x12\x0e/v1/categories\x12X\n" +
+	"\vGetCategory\x12\x16.pb.GetCategoryRequest\x1a\x14.pb.CategoryResponse\"\x1b\x82\xd3\xe4\x93\x02\x15\x12\x13/v1/categories/{id}\x12a\n" +
+	"\x0eUpdateCategory\x12\x19.pb.UpdateCategoryRequest\x1a\x14.pb.CategoryResponse\"\x1e\x82\xd3\xe4\x93\x02\x18:\x01*\x1a\x13/v1/categories/{id}\x12S\n" +
+	"\x0eDeleteCategory\x12\x19.pb.DeleteCategoryRequest\x1a\t.pb.blank\"\x1b\x82\xd3\xe4\x93\x02\x15*\x13/v1/categories/{id}2\xfb\x04\n" +
+	"\rCourseService\x12S\n" +
+	"\fCreateCourse\x12\x17.pb.CreateCourseRequest\x1a\x12.pb.CourseResponse\"\x16\x82\xd3\xe4\x93\x02\x10:\x01*\"\v/v1/courses\x12O\n" +
+	"\tGetCourse\x12\x14.pb.GetCourseRequest\x1a\x12.pb.CourseResponse\"\x18\x82\xd3\xe4\x93\x02\x12\x12\x10/v1/courses/{id}\x12=\n" +
+	"\vListCourses\x12\t.pb.blank\x1a\x0e.pb.CourseList\"\x13\x82\xd3\xe4\x93\x02\r\x12\v/v1/courses\x12f\n" +
+	"\x19ListCoursesWithCategories\x12\t.pb.blank\x1a\x1a.pb.CourseWithCategoryList\"\"\x82\xd3\xe4\x93\x02\x1c\x12\x1a/v1/courses:withCategories\x12X\n" +
+	"\fUpdateCourse\x12\x17.pb.UpdateCourseRequest\x1a\x12.pb.CourseResponse\"\x1b\x82\xd3\xe4\x93\x02\x15:\x01*\x1a\x10/v1/courses/{id}\x12L\n" +
+	"\fDeleteCourse\x12\x17.pb.DeleteCourseRequest\x1a\t.pb.blank\"\x18\x82\xd3\xe4\x93\x02\x12*\x10/v1/courses/{id}\x12u\n" +
+	"\x15ListCoursesByCategory\x12 .pb.ListCoursesByCategoryRequest\x1a\n" +
+	".pb.Course\",\x82\xd3\xe4\x93\x02&\x12$/v1/categories/{category_id}/courses0\x01B\rZ\vinternal/pbb\x06proto3"
 
 var (
 	file_proto_course_category_proto_rawDescOnce sync.Once
@@ -342,33 +1139,74 @@ func file_proto_course_category_proto_rawDescGZIP() []byte {
 	return file_proto_course_category_proto_rawDescData
 }
 
-var file_proto_course_category_proto_msgTypes = make([]protoimpl.MessageInfo, 6)
+var file_proto_course_category_proto_msgTypes = make([]protoimpl.MessageInfo, 20)
 var file_proto_course_category_proto_goTypes = []any{
-	(*Category)(nil),              // 0: pb.Category
-	(*CreateCategoryRequest)(nil), // 1: pb.CreateCategoryRequest
-	(*CategoryResponse)(nil),      // 2: pb.CategoryResponse
-	(*Blank)(nil),                 // 3: pb.blank
-	(*GetCategoryRequest)(nil),    // 4: pb.GetCategoryRequest
-	(*CategoryList)(nil),          // 5: pb.CategoryList
+	(*Category)(nil),                     // 0: pb.Category
+	(*CreateCategoryRequest)(nil),        // 1: pb.CreateCategoryRequest
+	(*CategoryResponse)(nil),             // 2: pb.CategoryResponse
+	(*Blank)(nil),                        // 3: pb.blank
+	(*GetCategoryRequest)(nil),           // 4: pb.GetCategoryRequest
+	(*CategoryList)(nil),                 // 5: pb.CategoryList
+	(*CreateCategoriesSummary)(nil),      // 6: pb.CreateCategoriesSummary
+	(*CategoryResult)(nil),               // 7: pb.CategoryResult
+	(*UpdateCategoryRequest)(nil),        // 8: pb.UpdateCategoryRequest
+	(*DeleteCategoryRequest)(nil),        // 9: pb.DeleteCategoryRequest
+	(*Course)(nil),                       // 10: pb.Course
+	(*CreateCourseRequest)(nil),          // 11: pb.CreateCourseRequest
+	(*UpdateCourseRequest)(nil),          // 12: pb.UpdateCourseRequest
+	(*GetCourseRequest)(nil),             // 13: pb.GetCourseRequest
+	(*DeleteCourseRequest)(nil),          // 14: pb.DeleteCourseRequest
+	(*ListCoursesByCategoryRequest)(nil), // 15: pb.ListCoursesByCategoryRequest
+	(*CourseResponse)(nil),               // 16: pb.CourseResponse
+	(*CourseList)(nil),                   // 17: pb.CourseList
+	(*CourseWithCategory)(nil),           // 18: pb.CourseWithCategory
+	(*CourseWithCategoryList)(nil),       // 19: pb.CourseWithCategoryList
 }
 var file_proto_course_category_proto_depIdxs = []int32{
-	0, // 0: pb.CategoryResponse.category:type_name -> pb.Category
-	0, // 1: pb.CategoryList.categories:type_name -> pb.Category
-	1, // 2: pb.CategoryService.CreateCategory:input_type -> pb.CreateCategoryRequest
-	1, // 3: pb.CategoryService.CreateCategoryStream:input_type -> pb.CreateCategoryRequest
-	1, // 4: pb.CategoryService.CreateCategoryStreamBidirectional:input_type -> pb.CreateCategoryRequest
-	3, // 5: pb.CategoryService.ListCategories:input_type -> pb.blank
-	4, // 6: pb.CategoryService.GetCategory:input_type -> pb.GetCategoryRequest
-	2, // 7: pb.CategoryService.CreateCategory:output_type -> pb.CategoryResponse
-	5, // 8: pb.CategoryService.CreateCategoryStream:output_type -> pb.CategoryList
-	0, // 9: pb.CategoryService.CreateCategoryStreamBidirectional:output_type -> pb.Category
-	5, // 10: pb.CategoryService.ListCategories:output_type -> pb.CategoryList
-	2, // 11: pb.CategoryService.GetCategory:output_type -> pb.CategoryResponse
-	7, // [7:12] is the sub-list for method output_type
-	2, // [2:7] is the sub-list for method input_type
-	2, // [2:2] is the sub-list for extension type_name
-	2, // [2:2] is the sub-list for extension extendee
-	0, // [0:2] is the sub-list for field type_name
+	0,  // 0: pb.CategoryResponse.category:type_name -> pb.Category
+	0,  // 1: pb.CategoryList.categories:type_name -> pb.Category
+	0,  // 2: pb.CreateCategoriesSummary.categories:type_name -> pb.Category
+	0,  // 3: pb.CategoryResult.category:type_name -> pb.Category
+	10, // 4: pb.CourseResponse.course:type_name -> pb.Course
+	10, // 5: pb.CourseList.courses:type_name -> pb.Course
+	10, // 6: pb.CourseWithCategory.course:type_name -> pb.Course
+	0,  // 7: pb.CourseWithCategory.category:type_name -> pb.Category
+	18, // 8: pb.CourseWithCategoryList.courses:type_name -> pb.CourseWithCategory
+	1,  // 9: pb.CategoryService.CreateCategory:input_type -> pb.CreateCategoryRequest
+	1,  // 10: pb.CategoryService.CreateCategoryStream:input_type -> pb.CreateCategoryRequest
+	1,  // 11: pb.CategoryService.CreateCategoryStreamBidirectional:input_type -> pb.CreateCategoryRequest
+	1,  // 12: pb.CategoryService.CreateCategoriesBulk:input_type -> pb.CreateCategoryRequest
+	3,  // 13: pb.CategoryService.ListCategories:input_type -> pb.blank
+	4,  // 14: pb.CategoryService.GetCategory:input_type -> pb.GetCategoryRequest
+	8,  // 15: pb.CategoryService.UpdateCategory:input_type -> pb.UpdateCategoryRequest
+	9,  // 16: pb.CategoryService.DeleteCategory:input_type -> pb.DeleteCategoryRequest
+	11, // 17: pb.CourseService.CreateCourse:input_type -> pb.CreateCourseRequest
+	13, // 18: pb.CourseService.GetCourse:input_type -> pb.GetCourseRequest
+	3,  // 19: pb.CourseService.ListCourses:input_type -> pb.blank
+	3,  // 20: pb.CourseService.ListCoursesWithCategories:input_type -> pb.blank
+	12, // 21: pb.CourseService.UpdateCourse:input_type -> pb.UpdateCourseRequest
+	14, // 22: pb.CourseService.DeleteCourse:input_type -> pb.DeleteCourseRequest
+	15, // 23: pb.CourseService.ListCoursesByCategory:input_type -> pb.ListCoursesByCategoryRequest
+	2,  // 24: pb.CategoryService.CreateCategory:output_type -> pb.CategoryResponse
+	5,  // 25: pb.CategoryService.CreateCategoryStream:output_type -> pb.CategoryList
+	7,  // 26: pb.CategoryService.CreateCategoryStreamBidirectional:output_type -> pb.CategoryResult
+	6,  // 27: pb.CategoryService.CreateCategoriesBulk:output_type -> pb.CreateCategoriesSummary
+	5,  // 28: pb.CategoryService.ListCategories:output_type -> pb.CategoryList
+	2,  // 29: pb.CategoryService.GetCategory:output_type -> pb.CategoryResponse
+	2,  // 30: pb.CategoryService.UpdateCategory:output_type -> pb.CategoryResponse
+	3,  // 31: pb.CategoryService.DeleteCategory:output_type -> pb.blank
+	16, // 32: pb.CourseService.CreateCourse:output_type -> pb.CourseResponse
+	16, // 33: pb.CourseService.GetCourse:output_type -> pb.CourseResponse
+	17, // 34: pb.CourseService.ListCourses:output_type -> pb.CourseList
+	19, // 35: pb.CourseService.ListCoursesWithCategories:output_type -> pb.CourseWithCategoryList
+	16, // 36: pb.CourseService.UpdateCourse:output_type -> pb.CourseResponse
+	3,  // 37: pb.CourseService.DeleteCourse:output_type -> pb.blank
+	10, // 38: pb.CourseService.ListCoursesByCategory:output_type -> pb.Course
+	24, // [24:39] is the sub-list for method output_type
+	9,  // [9:24] is the sub-list for method input_type
+	9,  // [9:9] is the sub-list for extension type_name
+	9,  // [9:9] is the sub-list for extension extendee
+	0,  // [0:9] is the sub-list for field type_name
 }
 
 func init() { file_proto_course_category_proto_init() }
@@ -382,9 +1220,9 @@ func file_proto_course_category_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_proto_course_category_proto_rawDesc), len(file_proto_course_category_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   6,
+			NumMessages:   20,
 			NumExtensions: 0,
-			NumServices:   1,
+			NumServices:   2,
 		},
 		GoTypes:           file_proto_course_category_proto_goTypes,
 		DependencyIndexes: file_proto_course_category_proto_depIdxs,
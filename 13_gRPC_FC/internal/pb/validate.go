@@ -0,0 +1,81 @@
+package pb
+
+import "errors"
+
+// Regras de validação dos requests. Ficam fora dos arquivos gerados pelo protoc
+// para não serem sobrescritas; o interceptor de validação chama Validate antes
+// de o request chegar ao serviço.
+
+var (
+	errIDRequired         = errors.New("id is required")
+	errNameRequired       = errors.New("name is required")
+	errCategoryIDRequired = errors.New("category_id is required")
+)
+
+func (r *CreateCategoryRequest) Validate() error {
+	if r.GetName() == "" {
+		return errNameRequired
+	}
+	return nil
+}
+
+func (r *GetCategoryRequest) Validate() error {
+	return requireID(r.GetId())
+}
+
+func (r *UpdateCategoryRequest) Validate() error {
+	if err := requireID(r.GetId()); err != nil {
+		return err
+	}
+	if r.GetName() == "" {
+		return errNameRequired
+	}
+	return nil
+}
+
+func (r *DeleteCategoryRequest) Validate() error {
+	return requireID(r.GetId())
+}
+
+func (r *CreateCourseRequest) Validate() error {
+	return validateCourse(r.GetName(), r.GetCategoryId())
+}
+
+func (r *UpdateCourseRequest) Validate() error {
+	if err := requireID(r.GetId()); err != nil {
+		return err
+	}
+	return validateCourse(r.GetName(), r.GetCategoryId())
+}
+
+func (r *GetCourseRequest) Validate() error {
+	return requireID(r.GetId())
+}
+
+func (r *DeleteCourseRequest) Validate() error {
+	return requireID(r.GetId())
+}
+
+func (r *ListCoursesByCategoryRequest) Validate() error {
+	if r.GetCategoryId() == "" {
+		return errCategoryIDRequired
+	}
+	return nil
+}
+
+func requireID(id string) error {
+	if id == "" {
+		return errIDRequired
+	}
+	return nil
+}
+
+func validateCourse(name, categoryID string) error {
+	if name == "" {
+		return errNameRequired
+	}
+	if categoryID == "" {
+		return errCategoryIDRequired
+	}
+	return nil
+}
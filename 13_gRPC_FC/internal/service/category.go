@@ -7,6 +7,7 @@ import (
 	"github.com/ElizCarvalho/FC_PosGolang/13_gRPC_FC/internal/database"
 	"github.com/ElizCarvalho/FC_PosGolang/13_gRPC_FC/internal/pb"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
 )
 
 type CategoryService struct {
@@ -19,9 +20,13 @@ func NewCategoryService(categoryDB database.Category) *CategoryService {
 }
 
 func (c *CategoryService) CreateCategory(ctx context.Context, req *pb.CreateCategoryRequest) (*pb.CategoryResponse, error) {
+	if err := requireField(req.Name, "name"); err != nil {
+		return nil, err
+	}
+
 	category, err := c.CategoryDB.Create(req.Name, req.Description)
 	if err != nil {
-		return nil, err
+		return nil, toStatus(err, "category not found")
 	}
 
 	categoryPB := &pb.Category{
@@ -38,7 +43,7 @@ func (c *CategoryService) CreateCategory(ctx context.Context, req *pb.CreateCate
 func (c *CategoryService) ListCategories(ctx context.Context, req *pb.Blank) (*pb.CategoryList, error) {
 	categories, err := c.CategoryDB.List()
 	if err != nil {
-		return nil, err
+		return nil, toStatus(err, "category not found")
 	}
 
 	categoryPB := make([]*pb.Category, 0, len(categories))
@@ -54,9 +59,13 @@ func (c *CategoryService) ListCategories(ctx context.Context, req *pb.Blank) (*p
 }
 
 func (c *CategoryService) GetCategory(ctx context.Context, req *pb.GetCategoryRequest) (*pb.CategoryResponse, error) {
+	if err := requireField(req.Id, "id"); err != nil {
+		return nil, err
+	}
+
 	category, err := c.CategoryDB.GetByID(req.Id)
 	if err != nil {
-		return nil, err
+		return nil, toStatus(err, "category not found")
 	}
 
 	categoryPB := &pb.Category{
@@ -70,13 +79,52 @@ func (c *CategoryService) GetCategory(ctx context.Context, req *pb.GetCategoryRe
 	}, nil
 }
 
+func (c *CategoryService) UpdateCategory(ctx context.Context, req *pb.UpdateCategoryRequest) (*pb.CategoryResponse, error) {
+	if err := requireField(req.Id, "id"); err != nil {
+		return nil, err
+	}
+	if err := requireField(req.Name, "name"); err != nil {
+		return nil, err
+	}
+
+	category, err := c.CategoryDB.Update(req.Id, req.Name, req.Description)
+	if err != nil {
+		return nil, toStatus(err, "category not found")
+	}
+
+	return &pb.CategoryResponse{
+		Category: &pb.Category{
+			Id:          category.ID,
+			Name:        category.Name,
+			Description: category.Description,
+		},
+	}, nil
+}
+
+// DeleteCategory remove a categoria; categorias com cursos retornam FailedPrecondition
+func (c *CategoryService) DeleteCategory(ctx context.Context, req *pb.DeleteCategoryRequest) (*pb.Blank, error) {
+	if err := requireField(req.Id, "id"); err != nil {
+		return nil, err
+	}
+
+	if err := c.CategoryDB.Delete(req.Id); err != nil {
+		return nil, toStatus(err, "category not found")
+	}
+	return &pb.Blank{}, nil
+}
+
 // CreateCategoryStream implementa server-side streaming
 // Cria múltiplas categorias e envia em lotes via stream
 func (c *CategoryService) CreateCategoryStream(req *pb.CreateCategoryRequest, stream grpc.ServerStreamingServer[pb.CategoryList]) error {
 	// Simula criação de 10 categorias para demonstração
+	if err := requireField(req.Name, "name"); err != nil {
+		return err
+	}
+
+	// As 10 categorias são gravadas na mesma transação: ou todas, ou nenhuma
 	categories, err := c.CategoryDB.CreateMultiple(req.Name, req.Description, 10)
 	if err != nil {
-		return err
+		return toStatus(err, "category not found")
 	}
 
 	// Processa em lotes de 3 categorias por vez
@@ -114,33 +162,89 @@ func (c *CategoryService) CreateCategoryStream(req *pb.CreateCategoryRequest, st
 }
 
 // CreateCategoryStreamBidirectional implementa bidirectional streaming
-// Cliente envia múltiplas requisições e servidor responde para cada uma
-func (c *CategoryService) CreateCategoryStreamBidirectional(stream grpc.BidiStreamingServer[pb.CreateCategoryRequest, pb.Category]) error {
-	for {
-		// Recebe a próxima requisição do cliente
-		req, err := stream.Recv()
-		if err == io.EOF {
-			// Cliente terminou de enviar
-			return nil
+// Cliente envia múltiplas requisições e servidor responde para cada uma. Um item
+// inválido ou que falhe no banco gera um CategoryResult com erro, e o stream segue.
+func (c *CategoryService) CreateCategoryStreamBidirectional(stream grpc.BidiStreamingServer[pb.CreateCategoryRequest, pb.CategoryResult]) error {
+	for item := range receive(stream, bidiBufferSize) {
+		if item.err != nil {
+			return item.err
 		}
+
+		result := &pb.CategoryResult{Index: int32(item.index)}
+		category, err := c.createItem(item.req, item.invalid)
 		if err != nil {
+			result.Error = status.Convert(err).Message()
+		} else {
+			result.Category = category
+		}
+
+		// Envia a resposta de volta ao cliente imediatamente
+		if err := stream.Send(result); err != nil {
 			return err
 		}
+	}
+	return nil
+}
+
+// CreateCategoriesBulk implementa client-side streaming: todas as categorias
+// recebidas são gravadas numa única transação, confirmada só quando o cliente
+// encerra o envio. O primeiro item inválido desfaz o lote inteiro.
+func (c *CategoryService) CreateCategoriesBulk(stream grpc.ClientStreamingServer[pb.CreateCategoryRequest, pb.CreateCategoriesSummary]) error {
+	bulk, err := c.CategoryDB.BeginBulk(stream.Context())
+	if err != nil {
+		return toStatus(err, "category not found")
+	}
+	defer bulk.Rollback()
 
-		// Cria a categoria
-		category, err := c.CategoryDB.Create(req.Name, req.Description)
+	summary := &pb.CreateCategoriesSummary{}
+	for index := 0; ; index++ {
+		// Recv só é chamado depois que o item anterior foi gravado, então um banco
+		// lento segura o cliente pelo controle de fluxo do HTTP/2
+		req, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
 		if err != nil {
-			return err
+			return itemError(index, err)
+		}
+		if err := requireField(req.Name, "name"); err != nil {
+			return itemError(index, err)
 		}
 
-		// Envia a resposta de volta ao cliente imediatamente
-		err = stream.Send(&pb.Category{
-			Id:          category.ID,
-			Name:        category.Name,
-			Description: category.Description,
-		})
+		category, err := bulk.Add(req.Name, req.Description)
 		if err != nil {
-			return err
+			return itemError(index, toStatus(err, "category not found"))
 		}
+		summary.Categories = append(summary.Categories, toCategoryPB(category))
+	}
+
+	if err := bulk.Commit(); err != nil {
+		return toStatus(err, "category not found")
+	}
+	summary.Created = int32(len(summary.Categories))
+	return stream.SendAndClose(summary)
+}
+
+// createItem grava um item do stream bidirecional. invalid é o erro de
+// validação que o interceptor já encontrou para o item, se houver.
+func (c *CategoryService) createItem(req *pb.CreateCategoryRequest, invalid error) (*pb.Category, error) {
+	if invalid != nil {
+		return nil, invalid
+	}
+	if err := requireField(req.Name, "name"); err != nil {
+		return nil, err
+	}
+	category, err := c.CategoryDB.Create(req.Name, req.Description)
+	if err != nil {
+		return nil, toStatus(err, "category not found")
+	}
+	return toCategoryPB(category), nil
+}
+
+func toCategoryPB(category database.Category) *pb.Category {
+	return &pb.Category{
+		Id:          category.ID,
+		Name:        category.Name,
+		Description: category.Description,
 	}
 }
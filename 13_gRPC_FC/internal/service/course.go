@@ -0,0 +1,165 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/ElizCarvalho/FC_PosGolang/13_gRPC_FC/internal/database"
+	"github.com/ElizCarvalho/FC_PosGolang/13_gRPC_FC/internal/pb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type CourseService struct {
+	pb.UnimplementedCourseServiceServer
+	CourseDB   database.Course
+	CategoryDB database.Category
+}
+
+func NewCourseService(courseDB database.Course, categoryDB database.Category) *CourseService {
+	return &CourseService{CourseDB: courseDB, CategoryDB: categoryDB}
+}
+
+func (c *CourseService) CreateCourse(ctx context.Context, req *pb.CreateCourseRequest) (*pb.CourseResponse, error) {
+	if err := c.validateCourse(req.Name, req.CategoryId); err != nil {
+		return nil, err
+	}
+
+	course, err := c.CourseDB.Create(req.Name, req.Description, req.CategoryId)
+	if err != nil {
+		return nil, toStatus(err, "course not found")
+	}
+	return &pb.CourseResponse{Course: toCoursePB(course)}, nil
+}
+
+func (c *CourseService) GetCourse(ctx context.Context, req *pb.GetCourseRequest) (*pb.CourseResponse, error) {
+	if err := requireField(req.Id, "id"); err != nil {
+		return nil, err
+	}
+
+	course, err := c.CourseDB.GetByID(req.Id)
+	if err != nil {
+		return nil, toStatus(err, "course not found")
+	}
+	return &pb.CourseResponse{Course: toCoursePB(course)}, nil
+}
+
+func (c *CourseService) ListCourses(ctx context.Context, req *pb.Blank) (*pb.CourseList, error) {
+	courses, err := c.CourseDB.List()
+	if err != nil {
+		return nil, toStatus(err, "course not found")
+	}
+
+	coursesPB := make([]*pb.Course, 0, len(courses))
+	for _, course := range courses {
+		coursesPB = append(coursesPB, toCoursePB(course))
+	}
+	return &pb.CourseList{Courses: coursesPB}, nil
+}
+
+// ListCoursesWithCategories devolve os cursos já acompanhados da sua categoria (JOIN)
+func (c *CourseService) ListCoursesWithCategories(ctx context.Context, req *pb.Blank) (*pb.CourseWithCategoryList, error) {
+	courses, err := c.CourseDB.ListWithCategories()
+	if err != nil {
+		return nil, toStatus(err, "course not found")
+	}
+
+	coursesPB := make([]*pb.CourseWithCategory, 0, len(courses))
+	for _, course := range courses {
+		coursesPB = append(coursesPB, &pb.CourseWithCategory{
+			Course: &pb.Course{
+				Id:          course.CourseID,
+				Name:        course.CourseName,
+				Description: course.CourseDescription,
+				CategoryId:  course.CategoryID,
+			},
+			Category: &pb.Category{
+				Id:          course.CategoryID,
+				Name:        course.CategoryName,
+				Description: course.CategoryDescription,
+			},
+		})
+	}
+	return &pb.CourseWithCategoryList{Courses: coursesPB}, nil
+}
+
+func (c *CourseService) UpdateCourse(ctx context.Context, req *pb.UpdateCourseRequest) (*pb.CourseResponse, error) {
+	if err := requireField(req.Id, "id"); err != nil {
+		return nil, err
+	}
+	if err := c.validateCourse(req.Name, req.CategoryId); err != nil {
+		return nil, err
+	}
+
+	course, err := c.CourseDB.Update(req.Id, req.Name, req.Description, req.CategoryId)
+	if err != nil {
+		return nil, toStatus(err, "course not found")
+	}
+	return &pb.CourseResponse{Course: toCoursePB(course)}, nil
+}
+
+func (c *CourseService) DeleteCourse(ctx context.Context, req *pb.DeleteCourseRequest) (*pb.Blank, error) {
+	if err := requireField(req.Id, "id"); err != nil {
+		return nil, err
+	}
+
+	if err := c.CourseDB.Delete(req.Id); err != nil {
+		return nil, toStatus(err, "course not found")
+	}
+	return &pb.Blank{}, nil
+}
+
+// ListCoursesByCategory implementa server-side streaming
+// Envia os cursos da categoria um a um, parando se o cliente cancelar
+func (c *CourseService) ListCoursesByCategory(req *pb.ListCoursesByCategoryRequest, stream grpc.ServerStreamingServer[pb.Course]) error {
+	if err := requireField(req.CategoryId, "category_id"); err != nil {
+		return err
+	}
+	if _, err := c.CategoryDB.GetByID(req.CategoryId); err != nil {
+		return toStatus(err, "category not found")
+	}
+
+	courses, err := c.CourseDB.GetByCategoryID(req.CategoryId)
+	if err != nil {
+		return toStatus(err, "course not found")
+	}
+
+	for _, course := range courses {
+		if err := stream.Context().Err(); err != nil {
+			return status.FromContextError(err).Err()
+		}
+		if err := stream.Send(toCoursePB(course)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validateCourse confere os campos obrigatórios e se a categoria informada existe
+func (c *CourseService) validateCourse(name, categoryID string) error {
+	if err := requireField(name, "name"); err != nil {
+		return err
+	}
+	if err := requireField(categoryID, "category_id"); err != nil {
+		return err
+	}
+	_, err := c.CategoryDB.GetByID(categoryID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return status.Errorf(codes.InvalidArgument, "category %s does not exist", categoryID)
+	}
+	if err != nil {
+		return toStatus(err, "category not found")
+	}
+	return nil
+}
+
+func toCoursePB(course database.Course) *pb.Course {
+	return &pb.Course{
+		Id:          course.ID,
+		Name:        course.Name,
+		Description: course.Description,
+		CategoryId:  course.CategoryID,
+	}
+}
@@ -0,0 +1,32 @@
+package service
+
+import (
+	"database/sql"
+	"errors"
+	"log"
+
+	"github.com/ElizCarvalho/FC_PosGolang/13_gRPC_FC/internal/database"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// toStatus converte erros do banco em status gRPC. Erros inesperados são logados
+// e devolvidos como Internal, sem expor detalhes do banco ao cliente.
+func toStatus(err error, notFoundMsg string) error {
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return status.Error(codes.NotFound, notFoundMsg)
+	case errors.Is(err, database.ErrCategoryHasCourses):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	default:
+		log.Printf("database error: %v", err)
+		return status.Error(codes.Internal, "internal error")
+	}
+}
+
+func requireField(value, field string) error {
+	if value == "" {
+		return status.Errorf(codes.InvalidArgument, "%s is required", field)
+	}
+	return nil
+}
@@ -0,0 +1,382 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"io"
+	"log/slog"
+	"net"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ElizCarvalho/FC_PosGolang/13_gRPC_FC/internal/database"
+	"github.com/ElizCarvalho/FC_PosGolang/13_gRPC_FC/internal/pb"
+	"github.com/ElizCarvalho/FC_PosGolang/pkg/grpckit/interceptor"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+type testClients struct {
+	categories pb.CategoryServiceClient
+	courses    pb.CourseServiceClient
+}
+
+// newTestServer sobe os serviços em memória (bufconn + sqlite) e devolve os clientes
+func newTestServer(t *testing.T) testClients {
+	t.Helper()
+	db, err := sql.Open("sqlite3", "file:"+t.Name()+"?mode=memory&cache=shared")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	if err := database.CreateTables(db); err != nil {
+		t.Fatal(err)
+	}
+
+	categoryDB := database.NewCategory(db)
+	courseDB := database.NewCourse(db)
+	// mesma cadeia de interceptors do servidor, para exercitar a validação nos streams
+	server := grpc.NewServer(interceptor.ServerOptions(interceptor.Options{
+		Logger: slog.New(slog.NewTextHandler(io.Discard, nil)),
+	})...)
+	pb.RegisterCategoryServiceServer(server, NewCategoryService(*categoryDB))
+	pb.RegisterCourseServiceServer(server, NewCourseService(*courseDB, *categoryDB))
+
+	listener := bufconn.Listen(1024 * 1024)
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return listener.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	return testClients{categories: pb.NewCategoryServiceClient(conn), courses: pb.NewCourseServiceClient(conn)}
+}
+
+func assertCode(t *testing.T, err error, want codes.Code) {
+	t.Helper()
+	if got := status.Code(err); got != want {
+		t.Fatalf("expected %s, got %s (%v)", want, got, err)
+	}
+}
+
+func TestGetCategoryNotFound(t *testing.T) {
+	c := newTestServer(t)
+	_, err := c.categories.GetCategory(context.Background(), &pb.GetCategoryRequest{Id: "missing"})
+	assertCode(t, err, codes.NotFound)
+
+	_, err = c.categories.GetCategory(context.Background(), &pb.GetCategoryRequest{})
+	assertCode(t, err, codes.InvalidArgument)
+}
+
+func TestUpdateAndDeleteCategory(t *testing.T) {
+	c := newTestServer(t)
+	ctx := context.Background()
+
+	created, err := c.categories.CreateCategory(ctx, &pb.CreateCategoryRequest{Name: "Go", Description: "Golang"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	id := created.Category.Id
+
+	updated, err := c.categories.UpdateCategory(ctx, &pb.UpdateCategoryRequest{Id: id, Name: "Go Expert", Description: "Go avançado"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if updated.Category.Name != "Go Expert" {
+		t.Fatalf("unexpected name %q", updated.Category.Name)
+	}
+
+	_, err = c.categories.UpdateCategory(ctx, &pb.UpdateCategoryRequest{Id: "missing", Name: "x"})
+	assertCode(t, err, codes.NotFound)
+
+	_, err = c.courses.CreateCourse(ctx, &pb.CreateCourseRequest{Name: "gRPC", CategoryId: id})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = c.categories.DeleteCategory(ctx, &pb.DeleteCategoryRequest{Id: id})
+	assertCode(t, err, codes.FailedPrecondition)
+
+	other, _ := c.categories.CreateCategory(ctx, &pb.CreateCategoryRequest{Name: "Empty"})
+	_, err = c.categories.DeleteCategory(ctx, &pb.DeleteCategoryRequest{Id: other.Category.Id})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = c.categories.GetCategory(ctx, &pb.GetCategoryRequest{Id: other.Category.Id})
+	assertCode(t, err, codes.NotFound)
+}
+
+func TestDeleteCategoryAfterRemovingItsCourses(t *testing.T) {
+	c := newTestServer(t)
+	ctx := context.Background()
+
+	category, err := c.categories.CreateCategory(ctx, &pb.CreateCategoryRequest{Name: "Go"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	course, err := c.courses.CreateCourse(ctx, &pb.CreateCourseRequest{Name: "gRPC", CategoryId: category.Category.Id})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A tentativa recusada desfaz a transação: as operações seguintes não ficam bloqueadas
+	_, err = c.categories.DeleteCategory(ctx, &pb.DeleteCategoryRequest{Id: category.Category.Id})
+	assertCode(t, err, codes.FailedPrecondition)
+	if _, err := c.courses.DeleteCourse(ctx, &pb.DeleteCourseRequest{Id: course.Course.Id}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.categories.DeleteCategory(ctx, &pb.DeleteCategoryRequest{Id: category.Category.Id}); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = c.categories.DeleteCategory(ctx, &pb.DeleteCategoryRequest{Id: category.Category.Id})
+	assertCode(t, err, codes.NotFound)
+}
+
+func TestCourseCRUD(t *testing.T) {
+	c := newTestServer(t)
+	ctx := context.Background()
+
+	category, _ := c.categories.CreateCategory(ctx, &pb.CreateCategoryRequest{Name: "Go"})
+	categoryID := category.Category.Id
+
+	_, err := c.courses.CreateCourse(ctx, &pb.CreateCourseRequest{Name: "gRPC", CategoryId: "missing"})
+	assertCode(t, err, codes.InvalidArgument)
+
+	created, err := c.courses.CreateCourse(ctx, &pb.CreateCourseRequest{Name: "gRPC", Description: "Curso", CategoryId: categoryID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	id := created.Course.Id
+
+	got, err := c.courses.GetCourse(ctx, &pb.GetCourseRequest{Id: id})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Course.CategoryId != categoryID {
+		t.Fatalf("unexpected category %q", got.Course.CategoryId)
+	}
+
+	_, err = c.courses.UpdateCourse(ctx, &pb.UpdateCourseRequest{Id: id, Name: "gRPC Avançado", CategoryId: categoryID})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	withCategories, err := c.courses.ListCoursesWithCategories(ctx, &pb.Blank{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(withCategories.Courses) != 1 || withCategories.Courses[0].Category.Name != "Go" {
+		t.Fatalf("unexpected courses %v", withCategories.Courses)
+	}
+
+	_, err = c.courses.DeleteCourse(ctx, &pb.DeleteCourseRequest{Id: id})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = c.courses.GetCourse(ctx, &pb.GetCourseRequest{Id: id})
+	assertCode(t, err, codes.NotFound)
+	_, err = c.courses.DeleteCourse(ctx, &pb.DeleteCourseRequest{Id: id})
+	assertCode(t, err, codes.NotFound)
+}
+
+func TestListCoursesByCategory(t *testing.T) {
+	c := newTestServer(t)
+	ctx := context.Background()
+
+	goCategory, _ := c.categories.CreateCategory(ctx, &pb.CreateCategoryRequest{Name: "Go"})
+	javaCategory, _ := c.categories.CreateCategory(ctx, &pb.CreateCategoryRequest{Name: "Java"})
+	for _, name := range []string{"gRPC", "GraphQL", "SQLC"} {
+		c.courses.CreateCourse(ctx, &pb.CreateCourseRequest{Name: name, CategoryId: goCategory.Category.Id})
+	}
+	c.courses.CreateCourse(ctx, &pb.CreateCourseRequest{Name: "Spring", CategoryId: javaCategory.Category.Id})
+
+	stream, err := c.courses.ListCoursesByCategory(ctx, &pb.ListCoursesByCategoryRequest{CategoryId: goCategory.Category.Id})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var received int
+	for {
+		course, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		if course.CategoryId != goCategory.Category.Id {
+			t.Fatalf("unexpected course %v", course)
+		}
+		received++
+	}
+	if received != 3 {
+		t.Fatalf("expected 3 courses, got %d", received)
+	}
+
+	stream, _ = c.courses.ListCoursesByCategory(ctx, &pb.ListCoursesByCategoryRequest{CategoryId: "missing"})
+	_, err = stream.Recv()
+	assertCode(t, err, codes.NotFound)
+}
+
+func countCategories(t *testing.T, c testClients) int {
+	t.Helper()
+	list, err := c.categories.ListCategories(context.Background(), &pb.Blank{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return len(list.Categories)
+}
+
+func TestCreateCategoriesBulk(t *testing.T) {
+	c := newTestServer(t)
+	ctx := context.Background()
+
+	stream, err := c.categories.CreateCategoriesBulk(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"Go", "Java", "Rust"} {
+		if err := stream.Send(&pb.CreateCategoryRequest{Name: name}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	summary, err := stream.CloseAndRecv()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if summary.Created != 3 || len(summary.Categories) != 3 || summary.Categories[2].Name != "Rust" {
+		t.Fatalf("unexpected summary %v", summary)
+	}
+	if got := countCategories(t, c); got != 3 {
+		t.Fatalf("expected 3 categories, got %d", got)
+	}
+}
+
+func TestCreateCategoriesBulkRollsBackOnInvalidItem(t *testing.T) {
+	c := newTestServer(t)
+	ctx := context.Background()
+
+	stream, err := c.categories.CreateCategoriesBulk(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stream.Send(&pb.CreateCategoryRequest{Name: "Go"})
+	stream.Send(&pb.CreateCategoryRequest{})
+	stream.Send(&pb.CreateCategoryRequest{Name: "Rust"})
+	_, err = stream.CloseAndRecv()
+	assertCode(t, err, codes.InvalidArgument)
+	if msg := status.Convert(err).Message(); msg != "item 1: name is required" {
+		t.Fatalf("unexpected message %q", msg)
+	}
+	if got := countCategories(t, c); got != 0 {
+		t.Fatalf("expected rollback, got %d categories", got)
+	}
+}
+
+func TestCreateCategoryStreamCreatesAllOrNothing(t *testing.T) {
+	c := newTestServer(t)
+
+	stream, err := c.categories.CreateCategoryStream(context.Background(), &pb.CreateCategoryRequest{Name: "Go"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var received int
+	for {
+		batch, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		received += len(batch.Categories)
+	}
+	if received != 10 || countCategories(t, c) != 10 {
+		t.Fatalf("expected 10 categories, got %d", received)
+	}
+}
+
+func TestBidirectionalStreamReportsItemErrors(t *testing.T) {
+	c := newTestServer(t)
+
+	stream, err := c.categories.CreateCategoryStreamBidirectional(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"Go", "", "Rust"} {
+		if err := stream.Send(&pb.CreateCategoryRequest{Name: name}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	stream.CloseSend()
+
+	var results []*pb.CategoryResult
+	for {
+		result, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		results = append(results, result)
+	}
+
+	if len(results) != 3 {
+		t.Fatalf("expected 3 results, got %d", len(results))
+	}
+	if results[0].Category.GetName() != "Go" || results[2].Category.GetName() != "Rust" {
+		t.Fatalf("unexpected results %v", results)
+	}
+	if results[1].Index != 1 || results[1].Error != "name is required" || results[1].Category != nil {
+		t.Fatalf("expected item 1 to fail, got %v", results[1])
+	}
+	if got := countCategories(t, c); got != 2 {
+		t.Fatalf("expected 2 categories, got %d", got)
+	}
+}
+
+// fakeStream entrega mensagens sem fim e conta quantas foram lidas
+type fakeStream struct {
+	grpc.ServerStream
+	ctx   context.Context
+	reads atomic.Int32
+}
+
+func (s *fakeStream) Context() context.Context { return s.ctx }
+
+func (s *fakeStream) RecvMsg(m any) error {
+	s.reads.Add(1)
+	m.(*pb.CreateCategoryRequest).Name = "Go"
+	return nil
+}
+
+func TestReceiveStopsReadingWhenBufferIsFull(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	stream := &fakeStream{ctx: ctx}
+
+	items := receive(stream, 4)
+	time.Sleep(50 * time.Millisecond)
+	// 4 itens no buffer e 1 lido esperando vaga
+	if got := stream.reads.Load(); got != 5 {
+		t.Fatalf("expected reading to stop at 5 messages, got %d", got)
+	}
+
+	<-items
+	time.Sleep(50 * time.Millisecond)
+	if got := stream.reads.Load(); got != 6 {
+		t.Fatalf("expected one more read after consuming an item, got %d", got)
+	}
+}
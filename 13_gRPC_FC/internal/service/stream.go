@@ -0,0 +1,68 @@
+package service
+
+import (
+	"errors"
+	"io"
+
+	"github.com/ElizCarvalho/FC_PosGolang/13_gRPC_FC/internal/pb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// bidiBufferSize é quantos itens o stream bidirecional lê à frente do banco.
+// Com o buffer cheio a leitura para, a janela do HTTP/2 se esgota e o cliente
+// passa a esperar no Send até o banco alcançar.
+const bidiBufferSize = 16
+
+type streamItem struct {
+	index int
+	req   *pb.CreateCategoryRequest
+	// invalid é o erro de validação do item; o stream continua
+	invalid error
+	// err é um erro de transporte; o stream deve ser encerrado
+	err error
+}
+
+// receive lê o stream numa goroutine separada e entrega os itens por um canal
+// com bufferSize posições, para que a leitura e a gravação no banco andem em
+// paralelo sem que a leitura dispare à frente indefinidamente.
+func receive(stream grpc.ServerStream, bufferSize int) <-chan streamItem {
+	items := make(chan streamItem, bufferSize)
+	go func() {
+		defer close(items)
+		for index := 0; ; index++ {
+			req := new(pb.CreateCategoryRequest)
+			err := stream.RecvMsg(req)
+			if errors.Is(err, io.EOF) {
+				return
+			}
+
+			item := streamItem{index: index, req: req}
+			switch {
+			case err == nil:
+			case status.Code(err) == codes.InvalidArgument:
+				// o interceptor de validação rejeitou só este item
+				item.invalid = err
+			default:
+				item.err = err
+			}
+
+			select {
+			case items <- item:
+			case <-stream.Context().Done():
+				return
+			}
+			if item.err != nil {
+				return
+			}
+		}
+	}()
+	return items
+}
+
+// itemError indica em qual item do stream a falha aconteceu, mantendo o código
+func itemError(index int, err error) error {
+	st := status.Convert(err)
+	return status.Errorf(st.Code(), "item %d: %s", index, st.Message())
+}
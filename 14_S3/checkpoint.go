@@ -0,0 +1,153 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// ==============================================================================
+// CHECKPOINT DO MULTIPART UPLOAD
+// ==============================================================================
+
+// defaultCheckpointDir guarda um arquivo por upload multipart ou download em partes em andamento
+const defaultCheckpointDir = ".upload-checkpoints"
+
+// completedPart é uma parte já aceita pelo storage
+type completedPart struct {
+	ETag string `json:"etag"`
+	Size int64  `json:"size"`
+}
+
+// uploadCheckpoint registra o UploadId e as partes concluídas, para que um upload
+// interrompido continue de onde parou em vez de reenviar o arquivo inteiro
+type uploadCheckpoint struct {
+	Location string                  `json:"location"` // Storage.Location() do destino
+	Key      string                  `json:"key"`
+	UploadID string                  `json:"upload_id"`
+	FileSize int64                   `json:"file_size"`
+	ModTime  time.Time               `json:"mod_time"`
+	PartSize int64                   `json:"part_size"`
+	Parts    map[int64]completedPart `json:"parts"`
+	Encoding *objectEncoding         `json:"encoding,omitempty"` // Compressão/criptografia do upload
+
+	path string
+}
+
+// checkpointPath deriva um nome de arquivo estável a partir do que identifica a transferência
+func checkpointPath(dir string, id ...string) string {
+	sum := sha256.Sum256([]byte(strings.Join(id, "/")))
+	return filepath.Join(dir, hex.EncodeToString(sum[:16])+".json")
+}
+
+// loadCheckpoint retorna nil (sem erro) quando não há checkpoint salvo
+func loadCheckpoint(path string) (*uploadCheckpoint, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var cp uploadCheckpoint
+	if err := json.Unmarshal(data, &cp); err != nil {
+		return nil, err
+	}
+	if cp.Parts == nil {
+		cp.Parts = make(map[int64]completedPart)
+	}
+	cp.path = path
+	return &cp, nil
+}
+
+// matches diz se o checkpoint ainda vale para o arquivo: se ele mudou, as partes enviadas não servem mais
+func (cp *uploadCheckpoint) matches(location, key string, info os.FileInfo, partSize int64) bool {
+	return cp.Location == location && cp.Key == key && cp.FileSize == info.Size() &&
+		cp.ModTime.Equal(info.ModTime()) && cp.PartSize == partSize && cp.UploadID != ""
+}
+
+func (cp *uploadCheckpoint) save() error   { return saveJSON(cp.path, cp) }
+func (cp *uploadCheckpoint) remove() error { return removeIfExists(cp.path) }
+
+// ==============================================================================
+// CHECKPOINT DO DOWNLOAD EM PARTES
+// ==============================================================================
+
+// downloadCheckpoint registra quais ranges já estão gravados no arquivo temporário.
+// O ETag amarra o checkpoint à versão do objeto: se ele mudar, o download recomeça.
+type downloadCheckpoint struct {
+	Location string         `json:"location"`
+	Key      string         `json:"key"`
+	ETag     string         `json:"etag"`
+	Size     int64          `json:"size"`
+	PartSize int64          `json:"part_size"`
+	Done     map[int64]bool `json:"done"`
+
+	path string
+}
+
+// loadDownloadCheckpoint retorna nil (sem erro) quando não há checkpoint salvo
+func loadDownloadCheckpoint(path string) (*downloadCheckpoint, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var cp downloadCheckpoint
+	if err := json.Unmarshal(data, &cp); err != nil {
+		return nil, err
+	}
+	if cp.Done == nil {
+		cp.Done = make(map[int64]bool)
+	}
+	cp.path = path
+	return &cp, nil
+}
+
+func (cp *downloadCheckpoint) matches(location, key, etag string, size, partSize int64) bool {
+	return cp.Location == location && cp.Key == key && cp.ETag == etag && cp.Size == size && cp.PartSize == partSize
+}
+
+// doneBytes soma o tamanho dos ranges já gravados
+func (cp *downloadCheckpoint) doneBytes() int64 {
+	var total int64
+	for partNumber := range cp.Done {
+		start := (partNumber - 1) * cp.PartSize
+		total += min(start+cp.PartSize, cp.Size) - start
+	}
+	return total
+}
+
+func (cp *downloadCheckpoint) save() error   { return saveJSON(cp.path, cp) }
+func (cp *downloadCheckpoint) remove() error { return removeIfExists(cp.path) }
+
+// saveJSON grava em um arquivo temporário e renomeia, para nunca deixar um checkpoint pela metade
+func saveJSON(path string, v any) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+func removeIfExists(path string) error {
+	err := os.Remove(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	return err
+}
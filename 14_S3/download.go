@@ -0,0 +1,396 @@
+package main
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ==============================================================================
+// DOWNLOAD EM PARTES (RANGED GET CONCORRENTE)
+// ==============================================================================
+
+// errObjectChanged indica que o objeto foi sobrescrito no meio do download
+var errObjectChanged = errors.New("object changed during download")
+
+// Resultado do download
+type DownloadResult struct {
+	Key     string
+	Path    string
+	Success bool
+	Error   error
+	Size    int64
+	Time    time.Duration
+}
+
+// DownloadWorker é o espelho do multipart upload: divide o objeto em ranges de partSize,
+// baixa até concurrency ranges ao mesmo tempo (ajustável com SetConcurrency) e grava
+// cada um direto no seu offset
+type DownloadWorker struct {
+	transferConfig
+	store Storage
+}
+
+func NewDownloadWorker(store Storage, opts ...TransferOption) *DownloadWorker {
+	return &DownloadWorker{
+		transferConfig: newTransferConfig(maxConcurrentParts, opts),
+		store:          store,
+	}
+}
+
+// Download baixa key para destPath. Os dados vão para destPath+".part" e só são
+// renomeados depois de conferir o checksum; se algo falhar, a próxima chamada
+// retoma pelos ranges que faltam.
+func (w *DownloadWorker) Download(ctx context.Context, key, destPath string) DownloadResult {
+	start := time.Now()
+	size, err := w.download(ctx, key, destPath)
+	return DownloadResult{
+		Key:     key,
+		Path:    destPath,
+		Success: err == nil,
+		Error:   err,
+		Size:    size,
+		Time:    time.Since(start),
+	}
+}
+
+func (w *DownloadWorker) download(ctx context.Context, key, destPath string) (int64, error) {
+	head, err := w.headObject(ctx, key, 0)
+	if err != nil {
+		return 0, err
+	}
+	size, etag := head.Size, head.ETag
+
+	// Objeto comprimido/cifrado: confere a chave antes de baixar qualquer byte
+	enc, err := parseObjectEncoding(head.Metadata)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", key, err)
+	}
+	if enc != nil {
+		if _, err := w.dataCipher(enc); err != nil {
+			return 0, fmt.Errorf("%s: %w", key, err)
+		}
+	}
+
+	cp, file, err := w.openDownload(key, destPath, etag, size)
+	if err != nil {
+		return 0, err
+	}
+	defer file.Close()
+
+	var tracker *progressTracker
+	if w.progress != nil {
+		tracker = newProgressTracker(w.progress)
+		tracker.track(key, size)
+		tracker.add(key, cp.doneBytes())
+	}
+
+	err = w.downloadParts(ctx, key, file, cp, tracker)
+	if errors.Is(err, errObjectChanged) {
+		// Os ranges já gravados são de outra versão: o próximo download começa do zero
+		cp.remove()
+	}
+	if err == nil {
+		size, err = w.finishDownload(ctx, key, destPath, head, file, cp, enc)
+	}
+	tracker.finish(key, err)
+	if err != nil {
+		return 0, err
+	}
+	return size, nil
+}
+
+// finishDownload confere o checksum do que veio do storage e, se o objeto foi transformado no
+// upload, descomprime/decifra para destPath. Devolve o tamanho final do arquivo.
+func (w *DownloadWorker) finishDownload(ctx context.Context, key, destPath string, head ObjectInfo, file *os.File, cp *downloadCheckpoint, enc *objectEncoding) (int64, error) {
+	discard := func(err error) (int64, error) {
+		file.Close()
+		os.Remove(file.Name())
+		cp.remove()
+		return 0, err
+	}
+
+	if err := file.Sync(); err != nil {
+		return 0, err
+	}
+	if err := w.verify(ctx, key, file.Name(), head); err != nil {
+		return discard(err)
+	}
+
+	if enc == nil {
+		if err := file.Close(); err != nil {
+			return 0, err
+		}
+		if err := os.Rename(file.Name(), destPath); err != nil {
+			return 0, err
+		}
+		return cp.Size, cp.remove()
+	}
+
+	size, err := w.decodeFile(file, destPath, enc)
+	if err != nil {
+		return discard(fmt.Errorf("%s: %w", key, err))
+	}
+	file.Close()
+	os.Remove(file.Name())
+	return size, cp.remove()
+}
+
+// decodeFile grava em destPath o conteúdo original de file, passando por um temporário
+// para que um erro no meio (dado adulterado, por exemplo) não deixe um arquivo pela metade
+func (w *DownloadWorker) decodeFile(file *os.File, destPath string, enc *objectEncoding) (int64, error) {
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return 0, err
+	}
+	decoded, err := w.decodeReader(file, enc)
+	if err != nil {
+		return 0, err
+	}
+	defer decoded.Close()
+
+	tmpPath := destPath + ".tmp"
+	out, err := os.Create(tmpPath)
+	if err != nil {
+		return 0, err
+	}
+	size, err := io.Copy(out, decoded)
+	if err == nil {
+		err = out.Sync()
+	}
+	if closeErr := out.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil && size != enc.PlainSize {
+		err = fmt.Errorf("decoded %d bytes, expected %d", size, enc.PlainSize)
+	}
+	if err != nil {
+		os.Remove(tmpPath)
+		return 0, err
+	}
+	return size, os.Rename(tmpPath, destPath)
+}
+
+// openDownload retoma o checkpoint de key -> destPath se ele ainda vale para a versão atual
+// do objeto, ou começa um arquivo temporário novo do tamanho do objeto
+func (w *DownloadWorker) openDownload(key, destPath, etag string, size int64) (*downloadCheckpoint, *os.File, error) {
+	absDest, err := filepath.Abs(destPath)
+	if err != nil {
+		return nil, nil, err
+	}
+	partPath := destPath + ".part"
+	location := w.store.Location()
+	path := checkpointPath(w.checkpointDir, "download", location, key, absDest)
+
+	cp, err := loadDownloadCheckpoint(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	if cp != nil && cp.matches(location, key, etag, size, w.partSize) {
+		if info, err := os.Stat(partPath); err == nil && info.Size() == size {
+			file, err := os.OpenFile(partPath, os.O_RDWR, 0o644)
+			return cp, file, err
+		}
+	}
+
+	file, err := os.OpenFile(partPath, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0o644)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := file.Truncate(size); err != nil {
+		file.Close()
+		return nil, nil, err
+	}
+
+	cp = &downloadCheckpoint{
+		Location: location,
+		Key:      key,
+		ETag:     etag,
+		Size:     size,
+		PartSize: w.partSize,
+		Done:     make(map[int64]bool),
+		path:     path,
+	}
+	if err := cp.save(); err != nil {
+		file.Close()
+		return nil, nil, err
+	}
+	return cp, file, nil
+}
+
+// downloadParts distribui os ranges pendentes entre goroutines limitadas pelo semáforo do worker
+func (w *DownloadWorker) downloadParts(ctx context.Context, key string, file *os.File, cp *downloadCheckpoint, tracker *progressTracker) error {
+	totalParts := (cp.Size + w.partSize - 1) / w.partSize
+
+	// Monta a fila antes de subir as goroutines, que passam a escrever em cp.Done
+	var pending []int64
+	for partNumber := int64(1); partNumber <= totalParts; partNumber++ {
+		if !cp.Done[partNumber] {
+			pending = append(pending, partNumber)
+		}
+	}
+
+	var (
+		mu       sync.Mutex
+		wg       sync.WaitGroup
+		firstErr error
+	)
+	fail := func(err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		if firstErr == nil {
+			firstErr = err
+		}
+	}
+	failed := func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return firstErr != nil
+	}
+
+	for _, partNumber := range pending {
+		if err := w.sem.Acquire(ctx); err != nil {
+			fail(err)
+			break
+		}
+		if failed() {
+			w.sem.Release()
+			break
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer w.sem.Release()
+
+			_, err := w.retry.Do(ctx, func() error {
+				return w.downloadPart(ctx, key, file, cp, partNumber, tracker)
+			})
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err == nil {
+				cp.Done[partNumber] = true
+				err = cp.save()
+			}
+			if err != nil && firstErr == nil {
+				firstErr = err
+			}
+		}()
+	}
+	wg.Wait()
+
+	return firstErr
+}
+
+// downloadPart busca um range com If-Match, para não misturar bytes de versões diferentes do objeto
+func (w *DownloadWorker) downloadPart(ctx context.Context, key string, file *os.File, cp *downloadCheckpoint, partNumber int64, tracker *progressTracker) error {
+	start := (partNumber - 1) * w.partSize
+	end := min(start+w.partSize, cp.Size) - 1
+
+	resp, _, err := w.store.GetObject(ctx, key, GetOptions{
+		Offset:  start,
+		Length:  end - start + 1,
+		IfMatch: cp.ETag,
+	})
+	if errors.Is(err, ErrPreconditionFailed) {
+		return fmt.Errorf("%s: %w", key, errObjectChanged)
+	}
+	if err != nil {
+		return err
+	}
+	defer resp.Close()
+
+	// Na resposta os bytes já estão vindo da rede: conta desde o primeiro
+	body := newTransferReader(ctx, resp, w.limiter, tracker.adder(key))
+
+	written, err := io.Copy(io.NewOffsetWriter(file, start), body)
+	if err != nil {
+		body.undo()
+		return fmt.Errorf("part %d of %s: %w", partNumber, key, err)
+	}
+	if written != end-start+1 {
+		body.undo()
+		return fmt.Errorf("part %d of %s: got %d of %d bytes: %w", partNumber, key, written, end-start+1, io.ErrUnexpectedEOF)
+	}
+	return nil
+}
+
+// md5ETag reconhece o ETag que o S3 calcula a partir do conteúdo: MD5 simples ou MD5 dos MD5
+// das partes + "-N"
+var md5ETag = regexp.MustCompile(`^[0-9a-f]{32}(-[0-9]+)?$`)
+
+// etagIsContentMD5 informa se dá para recalcular o ETag a partir do arquivo. Com SSE-KMS ou
+// SSE-C o S3 devolve um ETag que não é o MD5 do conteúdo, mesmo tendo o mesmo formato.
+func etagIsContentMD5(head ObjectInfo) bool {
+	switch head.Encryption {
+	case "", "AES256":
+		return md5ETag.MatchString(head.ETag)
+	}
+	return false
+}
+
+// verify recalcula o ETag do arquivo baixado. Em objetos multipart o tamanho de parte usado
+// no upload pode ser outro, então ele vem do HeadObject da parte 1. Quando o ETag não é
+// comparável, confere só o tamanho.
+func (w *DownloadWorker) verify(ctx context.Context, key, path string, head ObjectInfo) error {
+	etag := head.ETag
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	if !etagIsContentMD5(head) {
+		info, err := file.Stat()
+		if err != nil {
+			return err
+		}
+		if info.Size() != head.Size {
+			return fmt.Errorf("size mismatch for %s: expected %d bytes, got %d", key, head.Size, info.Size())
+		}
+		return nil
+	}
+
+	var got string
+	if strings.Contains(etag, "-") {
+		part, err := w.headObject(ctx, key, 1)
+		if err != nil {
+			return err
+		}
+		got, err = multipartETag(file, part.Size)
+		if err != nil {
+			return err
+		}
+	} else {
+		hash := md5.New()
+		if _, err := io.Copy(hash, file); err != nil {
+			return err
+		}
+		got = hex.EncodeToString(hash.Sum(nil))
+	}
+
+	if got != etag {
+		return fmt.Errorf("checksum mismatch for %s: expected ETag %s, got %s", key, etag, got)
+	}
+	return nil
+}
+
+// headObject consulta o objeto inteiro (partNumber 0) ou uma parte específica
+func (w *DownloadWorker) headObject(ctx context.Context, key string, partNumber int64) (ObjectInfo, error) {
+	var head ObjectInfo
+	_, err := w.retry.Do(ctx, func() error {
+		var err error
+		head, err = w.store.HeadObject(ctx, key, partNumber)
+		return err
+	})
+	return head, err
+}
@@ -0,0 +1,317 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/service/s3"
+)
+
+// newTestDownloader usa ranges de 1KB para que os testes rodem com objetos pequenos
+func newTestDownloader(client *s3.S3, checkpointDir string, opts ...TransferOption) *DownloadWorker {
+	opts = append([]TransferOption{WithRetryPolicy(fastRetry), WithCheckpointDir(checkpointDir), WithConcurrency(3)}, opts...)
+	w := NewDownloadWorker(NewS3Storage(client, testBucket), opts...)
+	w.partSize = 1024
+	return w
+}
+
+// failRange faz as próximas n requisições do range indicado falharem com status
+func (f *fakeS3) failRange(rng string, n, status int) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.inject = func(op string, r *http.Request) int {
+		if op != "GetObject" || r.Header.Get("Range") != "bytes="+rng || n == 0 {
+			return 0
+		}
+		n--
+		return status
+	}
+}
+
+func randomContent(t *testing.T, size int) []byte {
+	t.Helper()
+	path, content := writeTestFile(t, size)
+	os.Remove(path)
+	return content
+}
+
+func TestDownloadInParallelRanges(t *testing.T) {
+	fake, client := newFakeS3(t)
+	content := randomContent(t, 4500)
+	fake.put(testBucket, "big.bin", content, fake.now())
+	dest := filepath.Join(t.TempDir(), "big.bin")
+	checkpointDir := t.TempDir()
+
+	result := newTestDownloader(client, checkpointDir).Download(context.Background(), "big.bin", dest)
+
+	if !result.Success {
+		t.Fatalf("download failed: %v", result.Error)
+	}
+	got, _ := os.ReadFile(dest)
+	if !bytes.Equal(got, content) || result.Size != int64(len(content)) {
+		t.Fatal("downloaded file does not match the object")
+	}
+	for _, rng := range []string{"0-1023", "1024-2047", "2048-3071", "3072-4095", "4096-4499"} {
+		if fake.count("GetObject:"+rng) != 1 {
+			t.Fatalf("range %s requested %d times, calls: %v", rng, fake.count("GetObject:"+rng), fake.calls)
+		}
+	}
+	if _, err := os.Stat(dest + ".part"); !os.IsNotExist(err) {
+		t.Fatal("temporary file should be renamed")
+	}
+	if files := checkpointFiles(t, checkpointDir); len(files) != 0 {
+		t.Fatalf("checkpoint should be removed after completion, found %v", files)
+	}
+}
+
+func TestDownloadVerifiesMultipartObjects(t *testing.T) {
+	fake, client := newFakeS3(t)
+	path, content := writeTestFile(t, 3000)
+
+	// Envia em partes de 1KB e baixa em ranges de 700 bytes: a verificação usa o tamanho de parte do upload
+	if results := newTestWorker(client, t.TempDir()).UploadItems(context.Background(), []UploadItem{{Path: path, Key: "multi.bin"}}); !results[0].Success {
+		t.Fatal(results[0].Error)
+	}
+	downloader := newTestDownloader(client, t.TempDir())
+	downloader.partSize = 700
+	dest := filepath.Join(t.TempDir(), "multi.bin")
+
+	result := downloader.Download(context.Background(), "multi.bin", dest)
+
+	if !result.Success {
+		t.Fatalf("download failed: %v", result.Error)
+	}
+	if got, _ := os.ReadFile(dest); !bytes.Equal(got, content) {
+		t.Fatal("downloaded file does not match the object")
+	}
+	if fake.count("GetObject:0-699") != 1 {
+		t.Fatalf("expected 700 byte ranges, calls: %v", fake.calls)
+	}
+}
+
+func TestDownloadRetriesFailedRange(t *testing.T) {
+	fake, client := newFakeS3(t)
+	content := randomContent(t, 3000)
+	fake.put(testBucket, "obj", content, fake.now())
+	fake.failRange("1024-2047", 2, 503)
+	dest := filepath.Join(t.TempDir(), "obj")
+
+	result := newTestDownloader(client, t.TempDir()).Download(context.Background(), "obj", dest)
+
+	if !result.Success {
+		t.Fatalf("download failed: %v", result.Error)
+	}
+	if fake.count("GetObject:1024-2047") != 3 || fake.count("GetObject:0-1023") != 1 {
+		t.Fatalf("only the failed range should be retried, calls: %v", fake.calls)
+	}
+	if got, _ := os.ReadFile(dest); !bytes.Equal(got, content) {
+		t.Fatal("downloaded file does not match the object")
+	}
+}
+
+func TestDownloadResumesAfterInterruption(t *testing.T) {
+	fake, client := newFakeS3(t)
+	content := randomContent(t, 5000)
+	fake.put(testBucket, "obj", content, fake.now())
+	fake.failRange("2048-3071", 1000, 500)
+	dest := filepath.Join(t.TempDir(), "obj")
+	checkpointDir := t.TempDir()
+
+	result := newTestDownloader(client, checkpointDir).Download(context.Background(), "obj", dest)
+	if result.Success {
+		t.Fatal("expected first download to fail")
+	}
+	if _, err := os.Stat(dest); !os.IsNotExist(err) {
+		t.Fatal("destination must not exist before the download completes")
+	}
+	if files := checkpointFiles(t, checkpointDir); len(files) != 1 {
+		t.Fatalf("expected a checkpoint, found %v", files)
+	}
+
+	fake.inject = nil
+	result = newTestDownloader(client, checkpointDir).Download(context.Background(), "obj", dest)
+	if !result.Success {
+		t.Fatalf("resume failed: %v", result.Error)
+	}
+	for _, rng := range []string{"0-1023", "1024-2047", "3072-4095", "4096-4999"} {
+		if fake.count("GetObject:"+rng) != 1 {
+			t.Fatalf("range %s downloaded %d times, expected once", rng, fake.count("GetObject:"+rng))
+		}
+	}
+	if got, _ := os.ReadFile(dest); !bytes.Equal(got, content) {
+		t.Fatal("resumed file does not match the object")
+	}
+}
+
+func TestDownloadRestartsWhenObjectChanged(t *testing.T) {
+	fake, client := newFakeS3(t)
+	fake.put(testBucket, "obj", randomContent(t, 3000), fake.now())
+	fake.failRange("2048-2999", 1000, 500)
+	dest := filepath.Join(t.TempDir(), "obj")
+	checkpointDir := t.TempDir()
+
+	newTestDownloader(client, checkpointDir).Download(context.Background(), "obj", dest)
+
+	// Nova versão com o mesmo tamanho: os ranges já baixados não servem mais
+	changed := randomContent(t, 3000)
+	fake.inject = nil
+	fake.put(testBucket, "obj", changed, fake.now())
+
+	result := newTestDownloader(client, checkpointDir).Download(context.Background(), "obj", dest)
+	if !result.Success {
+		t.Fatalf("download failed: %v", result.Error)
+	}
+	if fake.count("GetObject:0-1023") != 2 {
+		t.Fatalf("expected a full restart, calls: %v", fake.calls)
+	}
+	if got, _ := os.ReadFile(dest); !bytes.Equal(got, changed) {
+		t.Fatal("downloaded file does not match the new version")
+	}
+}
+
+func TestDownloadRejectsChecksumMismatch(t *testing.T) {
+	fake, client := newFakeS3(t)
+	fake.put(testBucket, "obj", randomContent(t, 2000), fake.now())
+	fake.objects[testBucket+"/obj"].etag = quotedMD5([]byte("something else"))
+	dest := filepath.Join(t.TempDir(), "obj")
+	checkpointDir := t.TempDir()
+
+	result := newTestDownloader(client, checkpointDir).Download(context.Background(), "obj", dest)
+
+	if result.Success {
+		t.Fatal("expected checksum verification to fail")
+	}
+	for _, path := range []string{dest, dest + ".part"} {
+		if _, err := os.Stat(path); !os.IsNotExist(err) {
+			t.Fatalf("%s should not exist after a checksum mismatch", path)
+		}
+	}
+	if files := checkpointFiles(t, checkpointDir); len(files) != 0 {
+		t.Fatalf("checkpoint should be discarded, found %v", files)
+	}
+}
+
+func TestDownloadSSEKMSObjectChecksOnlyTheSize(t *testing.T) {
+	fake, client := newFakeS3(t)
+	content := randomContent(t, 2000)
+	fake.put(testBucket, "kms", content, fake.now())
+	// Com SSE-KMS o ETag tem o formato de um MD5, mas não é o MD5 do conteúdo
+	fake.objects[testBucket+"/kms"].etag = quotedMD5([]byte("not the content"))
+	fake.objects[testBucket+"/kms"].sse = "aws:kms"
+	dest := filepath.Join(t.TempDir(), "kms")
+
+	result := newTestDownloader(client, t.TempDir()).Download(context.Background(), "kms", dest)
+
+	if !result.Success {
+		t.Fatalf("download failed: %v", result.Error)
+	}
+	if got, _ := os.ReadFile(dest); !bytes.Equal(got, content) {
+		t.Fatal("downloaded file does not match the object")
+	}
+}
+
+func TestETagIsContentMD5(t *testing.T) {
+	md5 := "9e107d9d372bb6826bd81d3542a419d6"
+	tests := []struct {
+		head ObjectInfo
+		want bool
+	}{
+		{ObjectInfo{ETag: md5}, true},
+		{ObjectInfo{ETag: md5 + "-3"}, true},
+		{ObjectInfo{ETag: md5, Encryption: "AES256"}, true},
+		{ObjectInfo{ETag: md5, Encryption: "aws:kms"}, false},
+		{ObjectInfo{ETag: md5 + "-3", Encryption: "SSE-C"}, false},
+		{ObjectInfo{ETag: "not-an-md5"}, false},
+	}
+	for _, tt := range tests {
+		if got := etagIsContentMD5(tt.head); got != tt.want {
+			t.Errorf("etagIsContentMD5(%+v) = %v, want %v", tt.head, got, tt.want)
+		}
+	}
+}
+
+func TestDownloadEmptyObject(t *testing.T) {
+	fake, client := newFakeS3(t)
+	fake.put(testBucket, "empty", nil, fake.now())
+	dest := filepath.Join(t.TempDir(), "empty")
+
+	result := newTestDownloader(client, t.TempDir()).Download(context.Background(), "empty", dest)
+
+	if !result.Success {
+		t.Fatalf("download failed: %v", result.Error)
+	}
+	if info, err := os.Stat(dest); err != nil || info.Size() != 0 {
+		t.Fatalf("expected an empty file, got %v %v", info, err)
+	}
+}
+
+func TestDownloadDecodesCompressedAndEncryptedObjects(t *testing.T) {
+	keys := newTestKeys(t)
+	text := bytes.Repeat([]byte("linha de log repetida\n"), 300)
+	cases := map[string]struct {
+		content []byte
+		opts    []TransferOption
+	}{
+		"simple gzip+encrypted":    {randomContent(t, 500), []TransferOption{WithCompression(CompressionGzip), WithEncryption(keys)}},
+		"multipart zstd+encrypted": {randomContent(t, 5000), []TransferOption{WithCompression(CompressionZstd), WithEncryption(keys)}},
+		"multipart gzip only":      {text, []TransferOption{WithCompression(CompressionGzip)}},
+	}
+
+	for name, tc := range cases {
+		fake, client := newFakeS3(t)
+		path := filepath.Join(t.TempDir(), "file.bin")
+		os.WriteFile(path, tc.content, 0o644)
+
+		results := newTestWorker(client, t.TempDir(), tc.opts...).
+			UploadItems(context.Background(), []UploadItem{{Path: path, Key: "obj"}})
+		if !results[0].Success {
+			t.Fatalf("%s: upload failed: %v", name, results[0].Error)
+		}
+		if stored, _ := fake.object(testBucket, "obj"); bytes.Equal(stored, tc.content) {
+			t.Fatalf("%s: object was stored untransformed", name)
+		}
+
+		dest := filepath.Join(t.TempDir(), "obj")
+		result := newTestDownloader(client, t.TempDir(), tc.opts...).Download(context.Background(), "obj", dest)
+		if !result.Success {
+			t.Fatalf("%s: download failed: %v", name, result.Error)
+		}
+		if got, _ := os.ReadFile(dest); !bytes.Equal(got, tc.content) || result.Size != int64(len(tc.content)) {
+			t.Fatalf("%s: decoded file does not match the original", name)
+		}
+		if _, err := os.Stat(dest + ".part"); !os.IsNotExist(err) {
+			t.Fatalf("%s: temporary file should be removed", name)
+		}
+	}
+}
+
+func TestDownloadEncryptedObjectNeedsTheKey(t *testing.T) {
+	fake, client := newFakeS3(t)
+	path, _ := writeTestFile(t, 3000)
+	newTestWorker(client, t.TempDir(), WithEncryption(newTestKeys(t))).
+		UploadItems(context.Background(), []UploadItem{{Path: path, Key: "secret"}})
+
+	dest := filepath.Join(t.TempDir(), "secret")
+	for name, opts := range map[string][]TransferOption{
+		"no key":    nil,
+		"other key": {WithEncryption(newTestKeys(t))},
+	} {
+		result := newTestDownloader(client, t.TempDir(), opts...).Download(context.Background(), "secret", dest)
+		if result.Success {
+			t.Fatalf("%s: expected download to fail", name)
+		}
+		if _, err := os.Stat(dest + ".part"); !os.IsNotExist(err) {
+			t.Fatalf("%s: nothing should be downloaded without a usable key", name)
+		}
+	}
+	for _, call := range fake.calls {
+		if strings.HasPrefix(call, "GetObject") {
+			t.Fatalf("unexpected %s without a usable key", call)
+		}
+	}
+}
@@ -0,0 +1,476 @@
+package main
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3"
+)
+
+// ==============================================================================
+// FAKE S3 PARA TESTES
+// ==============================================================================
+
+// fakeS3 implementa o pedaço da API do S3 usado pelo UploadWorker, guardando tudo em
+// memória, e permite injetar falhas por operação para exercitar retry e retomada
+type fakeS3 struct {
+	mu       sync.Mutex
+	objects  map[string]*fakeObject // "bucket/key" -> objeto
+	uploads  map[string]*fakeUpload
+	nextID   int
+	calls    []string // Operações recebidas, na ordem ("UploadPart:2", "PutObject", ...)
+	maxParts int      // Tamanho da página do ListParts
+	maxKeys  int      // Tamanho da página do ListObjectsV2
+
+	// inject devolve um status HTTP para falhar a requisição, ou 0 para seguir normalmente
+	inject func(op string, r *http.Request) int
+}
+
+type fakeObject struct {
+	data      []byte
+	etag      string
+	modified  time.Time
+	partSizes []int       // Só para objetos multipart
+	metadata  http.Header // Cabeçalhos x-amz-meta-*
+	sse       string      // x-amz-server-side-encryption devolvido no HeadObject
+}
+
+type fakeUpload struct {
+	bucket, key string
+	parts       map[int64][]byte
+	metadata    http.Header
+}
+
+func newFakeS3(t *testing.T) (*fakeS3, *s3.S3) {
+	t.Helper()
+	fake := &fakeS3{
+		objects:  make(map[string]*fakeObject),
+		uploads:  make(map[string]*fakeUpload),
+		maxParts: 1000,
+		maxKeys:  1000,
+	}
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+
+	sess := session.Must(session.NewSession(&aws.Config{
+		Endpoint:         aws.String(server.URL),
+		Region:           aws.String(region),
+		Credentials:      credentials.NewStaticCredentials("test", "test", ""),
+		S3ForcePathStyle: aws.Bool(true),
+		MaxRetries:       aws.Int(0),
+	}))
+	return fake, s3.New(sess)
+}
+
+// failTimes faz as próximas n chamadas de op (e, se part > 0, só daquela parte) falharem com status
+func (f *fakeS3) failTimes(op string, part int64, n, status int) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.inject = func(gotOp string, r *http.Request) int {
+		if gotOp != op || n == 0 {
+			return 0
+		}
+		if part > 0 && r.URL.Query().Get("partNumber") != strconv.FormatInt(part, 10) {
+			return 0
+		}
+		n--
+		return status
+	}
+}
+
+func (f *fakeS3) object(bucket, key string) ([]byte, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	obj, ok := f.objects[bucket+"/"+strings.TrimPrefix(key, "/")]
+	if !ok {
+		return nil, false
+	}
+	return obj.data, true
+}
+
+// put grava um objeto direto no fake, como se tivesse sido enviado em modified
+func (f *fakeS3) put(bucket, key string, data []byte, modified time.Time) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.objects[bucket+"/"+key] = &fakeObject{data: data, etag: quotedMD5(data), modified: modified}
+}
+
+func (f *fakeS3) now() time.Time {
+	return time.Now().UTC().Truncate(time.Second)
+}
+
+func (f *fakeS3) keys(bucket string) []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var keys []string
+	for k := range f.objects {
+		if b, key, _ := strings.Cut(k, "/"); b == bucket {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func (f *fakeS3) count(call string) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	n := 0
+	for _, c := range f.calls {
+		if c == call {
+			n++
+		}
+	}
+	return n
+}
+
+func (f *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	bucket, key, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/"), "/")
+	key = strings.TrimPrefix(key, "/")
+	query := r.URL.Query()
+	op := operation(r.Method, key, query)
+
+	body, _ := io.ReadAll(r.Body)
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	call := op
+	switch op {
+	case "UploadPart":
+		call += ":" + query.Get("partNumber")
+	case "GetObject":
+		if rng := r.Header.Get("Range"); rng != "" {
+			call += ":" + strings.TrimPrefix(rng, "bytes=")
+		}
+	}
+	f.calls = append(f.calls, call)
+
+	if f.inject != nil {
+		if status := f.inject(op, r); status != 0 {
+			writeError(w, status, "InternalError", "injected failure")
+			return
+		}
+	}
+
+	switch op {
+	case "PutObject":
+		obj := &fakeObject{data: body, etag: quotedMD5(body), modified: time.Now().UTC(), metadata: userMetadata(r)}
+		f.objects[bucket+"/"+key] = obj
+		w.Header().Set("ETag", obj.etag)
+
+	case "HeadObject":
+		obj, ok := f.objects[bucket+"/"+key]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		length := len(obj.data)
+		if n, _ := strconv.Atoi(query.Get("partNumber")); n > 0 && len(obj.partSizes) > 0 {
+			length = obj.partSizes[n-1]
+			w.Header().Set("x-amz-mp-parts-count", strconv.Itoa(len(obj.partSizes)))
+		}
+		copyHeader(w.Header(), obj.metadata)
+		w.Header().Set("Content-Length", strconv.Itoa(length))
+		w.Header().Set("ETag", obj.etag)
+		w.Header().Set("Last-Modified", obj.modified.Format(http.TimeFormat))
+		if obj.sse != "" {
+			w.Header().Set("x-amz-server-side-encryption", obj.sse)
+		}
+
+	case "GetObject":
+		obj, ok := f.objects[bucket+"/"+key]
+		if !ok {
+			writeError(w, http.StatusNotFound, "NoSuchKey", "key does not exist")
+			return
+		}
+		if match := r.Header.Get("If-Match"); match != "" && match != obj.etag {
+			writeError(w, http.StatusPreconditionFailed, "PreconditionFailed", "etag does not match")
+			return
+		}
+		data := obj.data
+		copyHeader(w.Header(), obj.metadata)
+		w.Header().Set("ETag", obj.etag)
+		if rng := r.Header.Get("Range"); rng != "" {
+			// "bytes=início-fim" ou "bytes=início-" (até o fim do objeto)
+			from, to, _ := strings.Cut(strings.TrimPrefix(rng, "bytes="), "-")
+			start, err := strconv.Atoi(from)
+			end := len(data) - 1
+			if to != "" && err == nil {
+				end, err = strconv.Atoi(to)
+			}
+			if err != nil || start > end || start >= len(data) {
+				writeError(w, http.StatusRequestedRangeNotSatisfiable, "InvalidRange", rng)
+				return
+			}
+			end = min(end, len(data)-1)
+			w.Header().Set("Content-Range", fmt.Sprintf("bytes %d-%d/%d", start, end, len(data)))
+			w.Header().Set("Content-Length", strconv.Itoa(end-start+1))
+			w.WriteHeader(http.StatusPartialContent)
+			data = data[start : end+1]
+		}
+		w.Write(data)
+
+	case "ListObjectsV2":
+		prefix := query.Get("prefix")
+		after := query.Get("continuation-token")
+
+		type content struct {
+			Key          string
+			LastModified string
+			ETag         string
+			Size         int
+		}
+		result := struct {
+			XMLName               xml.Name `xml:"ListBucketResult"`
+			Name                  string
+			Prefix                string
+			KeyCount              int
+			IsTruncated           bool
+			NextContinuationToken string `xml:",omitempty"`
+			Contents              []content
+		}{Name: bucket, Prefix: prefix}
+
+		var keys []string
+		for k := range f.objects {
+			if b, objKey, _ := strings.Cut(k, "/"); b == bucket && strings.HasPrefix(objKey, prefix) && objKey > after {
+				keys = append(keys, objKey)
+			}
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			if len(result.Contents) == f.maxKeys {
+				result.IsTruncated = true
+				result.NextContinuationToken = result.Contents[len(result.Contents)-1].Key
+				break
+			}
+			obj := f.objects[bucket+"/"+k]
+			result.Contents = append(result.Contents, content{k, obj.modified.Format(time.RFC3339), obj.etag, len(obj.data)})
+		}
+		result.KeyCount = len(result.Contents)
+		writeXML(w, result)
+
+	case "DeleteObjects":
+		var req struct {
+			Object []struct{ Key string }
+		}
+		if err := xml.Unmarshal(body, &req); err != nil {
+			writeError(w, http.StatusBadRequest, "MalformedXML", err.Error())
+			return
+		}
+		type deleted struct{ Key string }
+		result := struct {
+			XMLName xml.Name `xml:"DeleteResult"`
+			Deleted []deleted
+		}{}
+		for _, obj := range req.Object {
+			delete(f.objects, bucket+"/"+obj.Key)
+			result.Deleted = append(result.Deleted, deleted{obj.Key})
+		}
+		writeXML(w, result)
+
+	case "CreateMultipartUpload":
+		f.nextID++
+		id := fmt.Sprintf("upload-%d", f.nextID)
+		f.uploads[id] = &fakeUpload{bucket: bucket, key: key, parts: make(map[int64][]byte), metadata: userMetadata(r)}
+		writeXML(w, struct {
+			XMLName  xml.Name `xml:"InitiateMultipartUploadResult"`
+			Bucket   string
+			Key      string
+			UploadId string
+		}{Bucket: bucket, Key: key, UploadId: id})
+
+	case "UploadPart":
+		upload, ok := f.uploads[query.Get("uploadId")]
+		if !ok {
+			writeError(w, http.StatusNotFound, "NoSuchUpload", "upload does not exist")
+			return
+		}
+		n, _ := strconv.ParseInt(query.Get("partNumber"), 10, 64)
+		upload.parts[n] = body
+		w.Header().Set("ETag", quotedMD5(body))
+
+	case "ListParts":
+		upload, ok := f.uploads[query.Get("uploadId")]
+		if !ok {
+			writeError(w, http.StatusNotFound, "NoSuchUpload", "upload does not exist")
+			return
+		}
+		marker, _ := strconv.ParseInt(query.Get("part-number-marker"), 10, 64)
+
+		type part struct {
+			PartNumber int64
+			ETag       string
+			Size       int
+		}
+		result := struct {
+			XMLName              xml.Name `xml:"ListPartsResult"`
+			Bucket               string
+			Key                  string
+			UploadId             string
+			NextPartNumberMarker int64
+			IsTruncated          bool
+			Part                 []part
+		}{Bucket: bucket, Key: key, UploadId: query.Get("uploadId")}
+
+		for _, n := range sortedParts(upload.parts) {
+			if n <= marker {
+				continue
+			}
+			if len(result.Part) == f.maxParts {
+				result.IsTruncated = true
+				break
+			}
+			result.Part = append(result.Part, part{n, quotedMD5(upload.parts[n]), len(upload.parts[n])})
+			result.NextPartNumberMarker = n
+		}
+		writeXML(w, result)
+
+	case "CompleteMultipartUpload":
+		id := query.Get("uploadId")
+		upload, ok := f.uploads[id]
+		if !ok {
+			writeError(w, http.StatusNotFound, "NoSuchUpload", "upload does not exist")
+			return
+		}
+		var req struct {
+			Part []struct {
+				PartNumber int64
+				ETag       string
+			}
+		}
+		if err := xml.Unmarshal(body, &req); err != nil {
+			writeError(w, http.StatusBadRequest, "MalformedXML", err.Error())
+			return
+		}
+		var data []byte
+		var partSizes []int
+		for _, p := range req.Part {
+			part, ok := upload.parts[p.PartNumber]
+			if !ok || quotedMD5(part) != p.ETag {
+				writeError(w, http.StatusBadRequest, "InvalidPart", fmt.Sprintf("part %d is invalid", p.PartNumber))
+				return
+			}
+			data = append(data, part...)
+			partSizes = append(partSizes, len(part))
+		}
+		obj := &fakeObject{data: data, etag: multipartETagOf(upload.parts, req.Part), modified: time.Now().UTC(), partSizes: partSizes, metadata: upload.metadata}
+		f.objects[upload.bucket+"/"+upload.key] = obj
+		delete(f.uploads, id)
+		writeXML(w, struct {
+			XMLName xml.Name `xml:"CompleteMultipartUploadResult"`
+			Bucket  string
+			Key     string
+			ETag    string
+		}{Bucket: bucket, Key: key, ETag: obj.etag})
+
+	case "AbortMultipartUpload":
+		delete(f.uploads, query.Get("uploadId"))
+		w.WriteHeader(http.StatusNoContent)
+
+	default:
+		writeError(w, http.StatusNotImplemented, "NotImplemented", op+" is not supported by the fake")
+	}
+}
+
+func operation(method, key string, query map[string][]string) string {
+	_, uploads := query["uploads"]
+	_, uploadID := query["uploadId"]
+	switch {
+	case method == http.MethodPost && uploads:
+		return "CreateMultipartUpload"
+	case method == http.MethodPost && uploadID:
+		return "CompleteMultipartUpload"
+	case method == http.MethodPost && has(query, "delete"):
+		return "DeleteObjects"
+	case method == http.MethodGet && has(query, "list-type"):
+		return "ListObjectsV2"
+	case method == http.MethodPut && uploadID:
+		return "UploadPart"
+	case method == http.MethodGet && uploadID:
+		return "ListParts"
+	case method == http.MethodDelete && uploadID:
+		return "AbortMultipartUpload"
+	case method == http.MethodPut:
+		return "PutObject"
+	case method == http.MethodHead:
+		return "HeadObject"
+	case method == http.MethodGet && key != "":
+		return "GetObject"
+	default:
+		return method
+	}
+}
+
+func has(query map[string][]string, name string) bool {
+	_, ok := query[name]
+	return ok
+}
+
+// multipartETagOf segue a regra do S3: MD5 da concatenação dos MD5 das partes, mais "-N"
+func multipartETagOf(parts map[int64][]byte, order []struct {
+	PartNumber int64
+	ETag       string
+}) string {
+	digests := md5.New()
+	for _, p := range order {
+		sum := md5.Sum(parts[p.PartNumber])
+		digests.Write(sum[:])
+	}
+	return fmt.Sprintf(`"%x-%d"`, digests.Sum(nil), len(order))
+}
+
+func sortedParts(parts map[int64][]byte) []int64 {
+	numbers := make([]int64, 0, len(parts))
+	for n := range parts {
+		numbers = append(numbers, n)
+	}
+	sort.Slice(numbers, func(i, j int) bool { return numbers[i] < numbers[j] })
+	return numbers
+}
+
+func userMetadata(r *http.Request) http.Header {
+	meta := make(http.Header)
+	for name, values := range r.Header {
+		if strings.HasPrefix(strings.ToLower(name), "x-amz-meta-") {
+			meta[name] = values
+		}
+	}
+	return meta
+}
+
+func copyHeader(dst, src http.Header) {
+	for name, values := range src {
+		dst[name] = values
+	}
+}
+
+func quotedMD5(data []byte) string {
+	sum := md5.Sum(data)
+	return `"` + hex.EncodeToString(sum[:]) + `"`
+}
+
+func writeXML(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/xml")
+	xml.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, code, message string) {
+	w.Header().Set("Content-Type", "application/xml")
+	w.WriteHeader(status)
+	fmt.Fprintf(w, "<Error><Code>%s</Code><Message>%s</Message></Error>", code, message)
+}
@@ -2,6 +2,9 @@ module github.com/ElizCarvalho/FC_PosGolang/14_S3
 
 go 1.23
 
-require github.com/aws/aws-sdk-go v1.54.19
+require (
+	github.com/aws/aws-sdk-go v1.54.19
+	github.com/klauspost/compress v1.18.0
+)
 
 require github.com/jmespath/go-jmespath v0.4.0 // indirect
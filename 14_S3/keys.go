@@ -0,0 +1,108 @@
+package main
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// ==============================================================================
+// CHAVES (ENVELOPE ENCRYPTION)
+// ==============================================================================
+
+// dataKeySize é o tamanho das chaves AES-256, tanto a mestra quanto as de dados
+const dataKeySize = 32
+
+// KeyProvider guarda a chave mestra. Cada objeto é cifrado com uma chave de dados
+// aleatória, e só a versão embrulhada (cifrada pela chave mestra) vai para o metadata.
+// Trocar o keyfile por um KMS é só implementar esta interface.
+type KeyProvider interface {
+	// KeyID identifica a chave mestra sem revelá-la
+	KeyID() string
+	WrapKey(dataKey []byte) ([]byte, error)
+	UnwrapKey(keyID string, wrapped []byte) ([]byte, error)
+}
+
+// KeyfileProvider usa uma chave mestra guardada num arquivo local (64 caracteres hex)
+type KeyfileProvider struct {
+	id   string
+	aead cipher.AEAD
+}
+
+func NewKeyfileProvider(path string) (*KeyfileProvider, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	key, err := hex.DecodeString(strings.TrimSpace(string(data)))
+	if err != nil || len(key) != dataKeySize {
+		return nil, fmt.Errorf("keyfile %s must contain a %d-byte key in hex", path, dataKeySize)
+	}
+
+	aead, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	sum := sha256.Sum256(key)
+	return &KeyfileProvider{id: "keyfile:" + hex.EncodeToString(sum[:8]), aead: aead}, nil
+}
+
+// GenerateKeyfile cria um keyfile novo com permissão só para o dono. Não sobrescreve:
+// perder a chave antiga é perder todos os objetos cifrados com ela.
+func GenerateKeyfile(path string) error {
+	key := make([]byte, dataKeySize)
+	if _, err := rand.Read(key); err != nil {
+		return err
+	}
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+	if err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintln(file, hex.EncodeToString(key)); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+func (p *KeyfileProvider) KeyID() string {
+	return p.id
+}
+
+// WrapKey devolve nonce + chave de dados cifrada
+func (p *KeyfileProvider) WrapKey(dataKey []byte) ([]byte, error) {
+	nonce := make([]byte, p.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return p.aead.Seal(nonce, nonce, dataKey, []byte(p.id)), nil
+}
+
+func (p *KeyfileProvider) UnwrapKey(keyID string, wrapped []byte) ([]byte, error) {
+	if keyID != p.id {
+		return nil, fmt.Errorf("object was encrypted with key %s, but the keyfile is %s", keyID, p.id)
+	}
+	if len(wrapped) < p.aead.NonceSize() {
+		return nil, errors.New("wrapped key is too short")
+	}
+	nonce, sealed := wrapped[:p.aead.NonceSize()], wrapped[p.aead.NonceSize():]
+	key, err := p.aead.Open(nil, nonce, sealed, []byte(p.id))
+	if err != nil {
+		return nil, fmt.Errorf("unwrap data key: %w", err)
+	}
+	return key, nil
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
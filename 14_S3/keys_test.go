@@ -0,0 +1,80 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// newTestKeys cria um keyfile temporário e devolve o provider dele
+func newTestKeys(t *testing.T) *KeyfileProvider {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "master.key")
+	if err := GenerateKeyfile(path); err != nil {
+		t.Fatal(err)
+	}
+	keys, err := NewKeyfileProvider(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return keys
+}
+
+func TestKeyfileProviderWrapsAndUnwraps(t *testing.T) {
+	keys := newTestKeys(t)
+	dataKey := bytes.Repeat([]byte{7}, dataKeySize)
+
+	wrapped, err := keys.WrapKey(dataKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Contains(wrapped, dataKey) {
+		t.Fatal("wrapped key should not contain the data key")
+	}
+	got, err := keys.UnwrapKey(keys.KeyID(), wrapped)
+	if err != nil || !bytes.Equal(got, dataKey) {
+		t.Fatalf("unwrap returned %x, %v", got, err)
+	}
+
+	wrapped[len(wrapped)-1] ^= 1
+	if _, err := keys.UnwrapKey(keys.KeyID(), wrapped); err == nil {
+		t.Fatal("expected tampered wrapped key to fail")
+	}
+}
+
+func TestKeyfileProviderRejectsOtherKeys(t *testing.T) {
+	keys, other := newTestKeys(t), newTestKeys(t)
+	if keys.KeyID() == other.KeyID() {
+		t.Fatal("different keyfiles should have different ids")
+	}
+
+	wrapped, err := keys.WrapKey(make([]byte, dataKeySize))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := other.UnwrapKey(keys.KeyID(), wrapped); err == nil || !strings.Contains(err.Error(), keys.KeyID()) {
+		t.Fatalf("expected key id mismatch, got %v", err)
+	}
+}
+
+func TestKeyfileValidation(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "master.key")
+	if err := GenerateKeyfile(path); err != nil {
+		t.Fatal(err)
+	}
+	if err := GenerateKeyfile(path); err == nil {
+		t.Fatal("GenerateKeyfile must not overwrite an existing key")
+	}
+	if info, _ := os.Stat(path); info.Mode().Perm() != 0o600 {
+		t.Fatalf("keyfile should be private, got %v", info.Mode().Perm())
+	}
+
+	invalid := filepath.Join(dir, "short.key")
+	os.WriteFile(invalid, []byte("abcd\n"), 0o600)
+	if _, err := NewKeyfileProvider(invalid); err == nil {
+		t.Fatal("expected short key to be rejected")
+	}
+}
@@ -2,13 +2,14 @@ package main
 
 import (
 	"bytes"
+	"context"
+	"flag"
 	"fmt"
 	"io"
 	"log"
 	"os"
-	"path/filepath"
-	"sync"
-	"testing"
+	"os/signal"
+	"strings"
 	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
@@ -30,32 +31,11 @@ const (
 
 	// Configurações de performance
 	maxConcurrentUploads = 10              // Máximo de uploads simultâneos
+	maxConcurrentParts   = 5               // Partes baixadas em paralelo por arquivo
 	maxRetries           = 3               // Tentativas de retry
 	chunkSize            = 5 * 1024 * 1024 // 5MB por chunk
 )
 
-// ==============================================================================
-// TIPOS E STRUCTS
-// ==============================================================================
-
-// Resultado do upload
-type UploadResult struct {
-	Key     string
-	Success bool
-	Error   error
-	Size    int64
-	Time    time.Duration
-}
-
-// Worker para uploads concorrentes
-type UploadWorker struct {
-	client  *s3.S3
-	bucket  string
-	sem     chan struct{} // Semáforo para controlar concorrência
-	results chan UploadResult
-	wg      sync.WaitGroup
-}
-
 // ==============================================================================
 // CONFIGURAÇÃO DO CLIENTE S3
 // ==============================================================================
@@ -66,6 +46,7 @@ func setupS3Client() *s3.S3 {
 		Region:           aws.String(region),
 		Credentials:      credentials.NewStaticCredentials(accessKeyID, secretAccessKey, ""),
 		S3ForcePathStyle: aws.Bool(true), // Necessário para MinIO
+		MaxRetries:       aws.Int(0),     // O UploadWorker faz o retry com backoff; evita retry em cima de retry
 	}))
 	return s3.New(sess)
 }
@@ -191,218 +172,6 @@ func deleteObject(client *s3.S3, bucket, key string) error {
 	return nil
 }
 
-// ==============================================================================
-// UPLOAD OTIMIZADO (CONCORRENTE)
-// ==============================================================================
-
-func NewUploadWorker(client *s3.S3, bucket string) *UploadWorker {
-	return &UploadWorker{
-		client:  client,
-		bucket:  bucket,
-		sem:     make(chan struct{}, maxConcurrentUploads),
-		results: make(chan UploadResult, 100),
-	}
-}
-
-func (w *UploadWorker) UploadMultipleFiles(filePaths []string) []UploadResult {
-	fmt.Printf("🚀 Iniciando upload de %d arquivos com %d workers...\n",
-		len(filePaths), maxConcurrentUploads)
-
-	start := time.Now()
-
-	// Inicia workers
-	for _, filePath := range filePaths {
-		w.wg.Add(1)
-		go w.uploadFileAsync(filePath)
-	}
-
-	// Coleta resultados
-	go func() {
-		w.wg.Wait()
-		close(w.results)
-	}()
-
-	var results []UploadResult
-	for result := range w.results {
-		results = append(results, result)
-	}
-
-	totalTime := time.Since(start)
-	fmt.Printf("⏱️  Upload concluído em %v\n", totalTime)
-
-	// Estatísticas
-	successCount := 0
-	totalSize := int64(0)
-	for _, result := range results {
-		if result.Success {
-			successCount++
-			totalSize += result.Size
-		}
-	}
-
-	fmt.Printf("📊 Estatísticas:\n")
-	fmt.Printf("   ✅ Sucessos: %d/%d\n", successCount, len(filePaths))
-	fmt.Printf("   📦 Tamanho total: %.2f MB\n", float64(totalSize)/(1024*1024))
-	fmt.Printf("   ⚡ Velocidade: %.2f MB/s\n",
-		float64(totalSize)/(1024*1024)/totalTime.Seconds())
-
-	return results
-}
-
-func (w *UploadWorker) uploadFileAsync(filePath string) {
-	defer w.wg.Done()
-
-	// Controla concorrência
-	w.sem <- struct{}{}
-	defer func() { <-w.sem }()
-
-	start := time.Now()
-
-	// Tenta upload com retry
-	var lastErr error
-	for attempt := 0; attempt < maxRetries; attempt++ {
-		if attempt > 0 {
-			time.Sleep(time.Duration(attempt) * time.Second) // Backoff
-		}
-
-		size, err := w.uploadFileWithRetry(filePath)
-		if err == nil {
-			w.results <- UploadResult{
-				Key:     filePath,
-				Success: true,
-				Size:    size,
-				Time:    time.Since(start),
-			}
-			return
-		}
-		lastErr = err
-	}
-
-	// Falhou após todas as tentativas
-	w.results <- UploadResult{
-		Key:     filePath,
-		Success: false,
-		Error:   lastErr,
-		Time:    time.Since(start),
-	}
-}
-
-func (w *UploadWorker) uploadFileWithRetry(filePath string) (int64, error) {
-	file, err := os.Open(filePath)
-	if err != nil {
-		return 0, err
-	}
-	defer file.Close()
-
-	fileInfo, err := file.Stat()
-	if err != nil {
-		return 0, err
-	}
-
-	// Para arquivos grandes, usa multipart upload
-	if fileInfo.Size() > chunkSize {
-		return w.multipartUpload(file, filePath, fileInfo.Size())
-	}
-
-	// Upload simples para arquivos pequenos
-	_, err = w.client.PutObject(&s3.PutObjectInput{
-		Bucket:        aws.String(w.bucket),
-		Key:           aws.String(filePath),
-		Body:          file,
-		ContentLength: aws.Int64(fileInfo.Size()),
-		ContentType:   aws.String(getContentType(filePath)),
-	})
-
-	return fileInfo.Size(), err
-}
-
-// Multipart upload para arquivos grandes
-func (w *UploadWorker) multipartUpload(file *os.File, key string, size int64) (int64, error) {
-	// Inicia multipart upload
-	createResp, err := w.client.CreateMultipartUpload(&s3.CreateMultipartUploadInput{
-		Bucket:      aws.String(w.bucket),
-		Key:         aws.String(key),
-		ContentType: aws.String(getContentType(key)),
-	})
-	if err != nil {
-		return 0, err
-	}
-
-	var parts []*s3.CompletedPart
-	partNumber := int64(1)
-	buffer := make([]byte, chunkSize)
-
-	for {
-		n, err := file.Read(buffer)
-		if err == io.EOF {
-			break
-		}
-		if err != nil {
-			// Aborta upload em caso de erro
-			w.client.AbortMultipartUpload(&s3.AbortMultipartUploadInput{
-				Bucket:   aws.String(w.bucket),
-				Key:      aws.String(key),
-				UploadId: createResp.UploadId,
-			})
-			return 0, err
-		}
-
-		// Upload da parte
-		uploadResp, err := w.client.UploadPart(&s3.UploadPartInput{
-			Bucket:     aws.String(w.bucket),
-			Key:        aws.String(key),
-			PartNumber: aws.Int64(partNumber),
-			UploadId:   createResp.UploadId,
-			Body:       bytes.NewReader(buffer[:n]),
-		})
-		if err != nil {
-			w.client.AbortMultipartUpload(&s3.AbortMultipartUploadInput{
-				Bucket:   aws.String(w.bucket),
-				Key:      aws.String(key),
-				UploadId: createResp.UploadId,
-			})
-			return 0, err
-		}
-
-		parts = append(parts, &s3.CompletedPart{
-			ETag:       uploadResp.ETag,
-			PartNumber: aws.Int64(partNumber),
-		})
-
-		partNumber++
-	}
-
-	// Completa o upload
-	_, err = w.client.CompleteMultipartUpload(&s3.CompleteMultipartUploadInput{
-		Bucket:   aws.String(w.bucket),
-		Key:      aws.String(key),
-		UploadId: createResp.UploadId,
-		MultipartUpload: &s3.CompletedMultipartUpload{
-			Parts: parts,
-		},
-	})
-
-	return size, err
-}
-
-func getContentType(filename string) string {
-	ext := filepath.Ext(filename)
-	switch ext {
-	case ".txt":
-		return "text/plain"
-	case ".json":
-		return "application/json"
-	case ".jpg", ".jpeg":
-		return "image/jpeg"
-	case ".png":
-		return "image/png"
-	case ".pdf":
-		return "application/pdf"
-	default:
-		return "application/octet-stream"
-	}
-}
-
 // ==============================================================================
 // UTILITÁRIOS
 // ==============================================================================
@@ -507,8 +276,8 @@ func testConcurrentUpload(files []string) time.Duration {
 
 	start := time.Now()
 
-	worker := NewUploadWorker(client, bucket)
-	worker.UploadMultipleFiles(files)
+	worker := NewUploadWorker(NewS3Storage(client, bucket))
+	worker.UploadMultipleFiles(context.Background(), files)
 
 	return time.Since(start)
 }
@@ -559,6 +328,164 @@ func runPerformanceDemo() {
 	fmt.Println("   • Monitore uso de memória")
 }
 
+// ==============================================================================
+// SYNC (LINHA DE COMANDO)
+// ==============================================================================
+
+// globList permite repetir -include/-exclude na linha de comando
+type globList []string
+
+func (g *globList) String() string     { return strings.Join(*g, ",") }
+func (g *globList) Set(v string) error { *g = append(*g, v); return nil }
+
+func runSync(args []string) error {
+	flags := flag.NewFlagSet("sync", flag.ExitOnError)
+	bucket := flags.String("bucket", bucketName, "bucket de destino")
+	var opts SyncOptions
+	var include, exclude globList
+	flags.StringVar(&opts.Prefix, "prefix", "", "prefixo das keys no bucket")
+	flags.BoolVar(&opts.Delete, "delete", false, "remove do bucket os objetos que não existem localmente")
+	flags.BoolVar(&opts.DryRun, "dry-run", false, "mostra o que seria feito sem alterar nada")
+	flags.BoolVar(&opts.Checksum, "checksum", false, "compara o MD5 mesmo quando o arquivo local não é mais novo")
+	flags.Var(&include, "include", "glob de arquivos a incluir (pode repetir)")
+	flags.Var(&exclude, "exclude", "glob de arquivos a excluir (pode repetir)")
+	concurrency := flags.Int("concurrency", maxConcurrentUploads, "arquivos enviados em paralelo")
+	bwlimit := flags.Int64("bwlimit", 0, "limite de banda em KB/s somando todos os uploads (0 = sem limite)")
+	quiet := flags.Bool("quiet", false, "não mostra a barra de progresso")
+	compress := flags.String("compress", "", "comprime antes de enviar: gzip ou zstd")
+	keyfile := flags.String("keyfile", "", "cifra no cliente com a chave deste arquivo (crie com: go run . keygen)")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "Uso: go run . sync [opções] <diretório>")
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+
+	if flags.NArg() != 1 {
+		flags.Usage()
+		return fmt.Errorf("expected exactly one directory, got %d", flags.NArg())
+	}
+
+	opts.Include, opts.Exclude = include, exclude
+
+	compression, err := ParseCompression(*compress)
+	if err != nil {
+		return err
+	}
+
+	transferOpts := []TransferOption{
+		WithConcurrency(*concurrency),
+		WithBandwidthLimit(*bwlimit * 1024),
+		WithCompression(compression),
+	}
+	if !*quiet {
+		transferOpts = append(transferOpts, WithProgress(NewProgressRenderer(os.Stderr)))
+	}
+	if transferOpts, err = withKeyfile(transferOpts, *keyfile); err != nil {
+		return err
+	}
+
+	// Ctrl+C cancela os uploads; os multipart ficam no checkpoint para a próxima execução
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	store, err := NewStorage(StorageConfigFromEnv(*bucket))
+	if err != nil {
+		return err
+	}
+	worker := NewUploadWorker(store, transferOpts...)
+	report, err := worker.Sync(ctx, flags.Arg(0), opts)
+	if report == nil {
+		return err
+	}
+
+	if opts.DryRun {
+		fmt.Println("🔍 Dry run: nada será alterado")
+	}
+	for _, action := range report.Uploads {
+		fmt.Printf("   ⬆️  %s (%s)\n", action.Key, action.Reason)
+	}
+	for _, action := range report.Deletes {
+		fmt.Printf("   🗑️  %s (%s)\n", action.Key, action.Reason)
+	}
+	fmt.Printf("📊 %d para enviar, %d para remover, %d sem alteração\n",
+		len(report.Uploads), len(report.Deletes), report.Unchanged)
+	return err
+}
+
+// ==============================================================================
+// DOWNLOAD (LINHA DE COMANDO)
+// ==============================================================================
+
+func runDownload(args []string) error {
+	flags := flag.NewFlagSet("download", flag.ExitOnError)
+	bucket := flags.String("bucket", bucketName, "bucket de origem")
+	concurrency := flags.Int("concurrency", maxConcurrentParts, "ranges baixados em paralelo")
+	bwlimit := flags.Int64("bwlimit", 0, "limite de banda em KB/s (0 = sem limite)")
+	quiet := flags.Bool("quiet", false, "não mostra a barra de progresso")
+	keyfile := flags.String("keyfile", "", "chave para decifrar objetos enviados com -keyfile")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "Uso: go run . download [opções] <key> <destino>")
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+
+	if flags.NArg() != 2 {
+		flags.Usage()
+		return fmt.Errorf("expected a key and a destination, got %d arguments", flags.NArg())
+	}
+
+	transferOpts := []TransferOption{WithConcurrency(*concurrency), WithBandwidthLimit(*bwlimit * 1024)}
+	if !*quiet {
+		transferOpts = append(transferOpts, WithProgress(NewProgressRenderer(os.Stderr)))
+	}
+	transferOpts, err := withKeyfile(transferOpts, *keyfile)
+	if err != nil {
+		return err
+	}
+
+	// Ctrl+C interrompe o download; os ranges já gravados ficam no checkpoint
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	store, err := NewStorage(StorageConfigFromEnv(*bucket))
+	if err != nil {
+		return err
+	}
+	worker := NewDownloadWorker(store, transferOpts...)
+	result := worker.Download(ctx, flags.Arg(0), flags.Arg(1))
+	if !result.Success {
+		return result.Error
+	}
+
+	fmt.Printf("✅ %s → %s (%.2f MB em %v, %.2f MB/s)\n", result.Key, result.Path,
+		float64(result.Size)/(1024*1024), result.Time.Round(time.Millisecond),
+		float64(result.Size)/(1024*1024)/result.Time.Seconds())
+	return nil
+}
+
+// withKeyfile liga a criptografia no cliente quando um keyfile foi informado
+func withKeyfile(opts []TransferOption, path string) ([]TransferOption, error) {
+	if path == "" {
+		return opts, nil
+	}
+	keys, err := NewKeyfileProvider(path)
+	if err != nil {
+		return nil, err
+	}
+	return append(opts, WithEncryption(keys)), nil
+}
+
+func runKeygen(args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("usage: go run . keygen <keyfile>")
+	}
+	if err := GenerateKeyfile(args[0]); err != nil {
+		return err
+	}
+	fmt.Printf("🔑 Chave criada em %s (guarde uma cópia: sem ela os objetos cifrados não podem ser lidos)\n", args[0])
+	return nil
+}
+
 // ==============================================================================
 // FUNÇÃO PRINCIPAL
 // ==============================================================================
@@ -570,11 +497,29 @@ func main() {
 		case "demo":
 			runPerformanceDemo()
 			return
+		case "sync":
+			if err := runSync(os.Args[2:]); err != nil {
+				log.Fatalf("❌ Erro no sync: %v", err)
+			}
+			return
+		case "download":
+			if err := runDownload(os.Args[2:]); err != nil {
+				log.Fatalf("❌ Erro no download: %v", err)
+			}
+			return
+		case "keygen":
+			if err := runKeygen(os.Args[2:]); err != nil {
+				log.Fatalf("❌ Erro ao gerar a chave: %v", err)
+			}
+			return
 		case "help":
 			fmt.Println("🚀 S3 Study - Comandos disponíveis:")
-			fmt.Println("   go run main.go        - Exemplos básicos do S3")
-			fmt.Println("   go run main.go demo   - Demo de performance")
-			fmt.Println("   go run main.go help   - Esta ajuda")
+			fmt.Println("   go run .             - Exemplos básicos do S3")
+			fmt.Println("   go run . demo        - Demo de performance")
+			fmt.Println("   go run . sync <dir>  - Espelha um diretório no bucket (-h para opções)")
+			fmt.Println("   go run . download <key> <destino> - Download em partes paralelas, com retomada")
+			fmt.Println("   go run . keygen <arquivo> - Cria uma chave para -keyfile (criptografia no cliente)")
+			fmt.Println("   go run . help        - Esta ajuda")
 			return
 		}
 	}
@@ -643,145 +588,5 @@ func main() {
 	fmt.Println("   Usuário: minioadmin")
 	fmt.Println("   Senha: minioadmin")
 	fmt.Println("\n🚀 Para ver o demo de performance:")
-	fmt.Println("   go run main.go demo")
-}
-
-// ==============================================================================
-// TESTES (BENCHMARKS)
-// ==============================================================================
-
-// Benchmark do upload sequencial (implementação original)
-func BenchmarkSequentialUpload(b *testing.B) {
-	client := setupS3Client()
-	bucket := "benchmark-bucket"
-
-	// Cria bucket se não existir
-	client.CreateBucket(&s3.CreateBucketInput{
-		Bucket: aws.String(bucket),
-	})
-
-	// Cria arquivos de teste
-	files := createTestFiles(10)
-	defer cleanupTestFiles(files)
-
-	b.ResetTimer()
-
-	for i := 0; i < b.N; i++ {
-		// Upload sequencial (como na implementação original)
-		for _, filePath := range files {
-			file, _ := os.Open(filePath)
-			fileInfo, _ := file.Stat()
-
-			client.PutObject(&s3.PutObjectInput{
-				Bucket:        aws.String(bucket),
-				Key:           aws.String(filePath),
-				Body:          file,
-				ContentLength: aws.Int64(fileInfo.Size()),
-			})
-			file.Close()
-		}
-	}
-}
-
-// Benchmark do upload concorrente (implementação otimizada)
-func BenchmarkConcurrentUpload(b *testing.B) {
-	client := setupS3Client()
-	bucket := "benchmark-bucket-concurrent"
-
-	// Cria bucket se não existir
-	client.CreateBucket(&s3.CreateBucketInput{
-		Bucket: aws.String(bucket),
-	})
-
-	// Cria arquivos de teste
-	files := createTestFiles(10)
-	defer cleanupTestFiles(files)
-
-	b.ResetTimer()
-
-	for i := 0; i < b.N; i++ {
-		worker := NewUploadWorker(client, bucket)
-		worker.UploadMultipleFiles(files)
-	}
-}
-
-// Teste de performance com diferentes números de arquivos
-func TestPerformanceComparison(t *testing.T) {
-	client := setupS3Client()
-	bucket := "performance-test"
-
-	client.CreateBucket(&s3.CreateBucketInput{
-		Bucket: aws.String(bucket),
-	})
-
-	testCases := []struct {
-		name  string
-		count int
-	}{
-		{"10 arquivos", 10},
-		{"50 arquivos", 50},
-		{"100 arquivos", 100},
-	}
-
-	for _, tc := range testCases {
-		t.Run(tc.name, func(t *testing.T) {
-			files := createTestFiles(tc.count)
-			defer cleanupTestFiles(files)
-
-			// Teste sequencial
-			start := time.Now()
-			for _, filePath := range files {
-				file, _ := os.Open(filePath)
-				fileInfo, _ := file.Stat()
-				client.PutObject(&s3.PutObjectInput{
-					Bucket:        aws.String(bucket),
-					Key:           aws.String(filePath),
-					Body:          file,
-					ContentLength: aws.Int64(fileInfo.Size()),
-				})
-				file.Close()
-			}
-			sequentialTime := time.Since(start)
-
-			// Teste concorrente
-			start = time.Now()
-			worker := NewUploadWorker(client, bucket)
-			worker.UploadMultipleFiles(files)
-			concurrentTime := time.Since(start)
-
-			t.Logf("Sequencial: %v", sequentialTime)
-			t.Logf("Concorrente: %v", concurrentTime)
-			t.Logf("Melhoria: %.1fx", float64(sequentialTime)/float64(concurrentTime))
-		})
-	}
-}
-
-// Teste de memória com arquivos grandes
-func TestMemoryUsage(t *testing.T) {
-	client := setupS3Client()
-	bucket := "memory-test"
-
-	client.CreateBucket(&s3.CreateBucketInput{
-		Bucket: aws.String(bucket),
-	})
-
-	// Cria arquivo grande (10MB)
-	largeFile := "large-file.txt"
-	content := make([]byte, 10*1024*1024) // 10MB
-	for i := range content {
-		content[i] = byte(i % 256)
-	}
-
-	os.WriteFile(largeFile, content, 0644)
-	defer os.Remove(largeFile)
-
-	// Testa upload com multipart
-	worker := NewUploadWorker(client, bucket)
-	results := worker.UploadMultipleFiles([]string{largeFile})
-
-	if len(results) != 1 || !results[0].Success {
-		t.Fatalf("Upload falhou: %v", results[0].Error)
-	}
-
-	t.Logf("Arquivo grande (%d MB) enviado com sucesso", len(content)/(1024*1024))
+	fmt.Println("   go run . demo")
 }
@@ -0,0 +1,84 @@
+package main
+
+// ==============================================================================
+// CONFIGURAÇÃO DOS WORKERS
+// ==============================================================================
+
+// transferConfig reúne o que UploadWorker e DownloadWorker têm em comum
+type transferConfig struct {
+	concurrency   int // Uploads: arquivos em paralelo. Downloads: partes em paralelo por arquivo
+	retry         RetryPolicy
+	partSize      int64
+	checkpointDir string
+	limiter       *RateLimiter
+	progress      ProgressFunc
+	compression   Compression
+	keys          KeyProvider // Com provider, os objetos são cifrados antes do envio
+
+	sem *dynamicSemaphore // Criado a partir de concurrency; ajustável com SetConcurrency
+}
+
+// TransferOption ajusta o comportamento de um UploadWorker ou DownloadWorker
+type TransferOption func(*transferConfig)
+
+// WithConcurrency define quantas transferências rodam ao mesmo tempo
+func WithConcurrency(n int) TransferOption {
+	return func(c *transferConfig) { c.concurrency = max(n, 1) }
+}
+
+// WithRetryPolicy troca a política de retry usada para objetos e partes
+func WithRetryPolicy(policy RetryPolicy) TransferOption {
+	return func(c *transferConfig) { c.retry = policy }
+}
+
+// WithCheckpointDir define onde ficam os checkpoints de uploads e downloads em partes
+func WithCheckpointDir(dir string) TransferOption {
+	return func(c *transferConfig) { c.checkpointDir = dir }
+}
+
+// WithBandwidthLimit limita a banda somada de todas as transferências do worker, em bytes/s
+func WithBandwidthLimit(bytesPerSecond int64) TransferOption {
+	return func(c *transferConfig) { c.limiter.SetRate(bytesPerSecond) }
+}
+
+// WithRateLimiter compartilha um limitador entre workers, para que o limite valha para todos juntos
+func WithRateLimiter(limiter *RateLimiter) TransferOption {
+	return func(c *transferConfig) { c.limiter = limiter }
+}
+
+// WithCompression comprime os objetos antes do envio (o download descomprime sozinho)
+func WithCompression(compression Compression) TransferOption {
+	return func(c *transferConfig) { c.compression = compression }
+}
+
+// WithEncryption cifra os objetos no cliente com AES-GCM; as chaves de dados são
+// protegidas pelo provider e também é ele quem as abre no download
+func WithEncryption(keys KeyProvider) TransferOption {
+	return func(c *transferConfig) { c.keys = keys }
+}
+
+func newTransferConfig(concurrency int, opts []TransferOption) transferConfig {
+	c := transferConfig{
+		concurrency:   concurrency,
+		retry:         defaultRetryPolicy,
+		partSize:      chunkSize,
+		checkpointDir: defaultCheckpointDir,
+		limiter:       NewRateLimiter(0),
+	}
+	for _, opt := range opts {
+		opt(&c)
+	}
+	c.sem = newDynamicSemaphore(c.concurrency)
+	return c
+}
+
+// SetConcurrency muda a concorrência com transferências em andamento. Ao reduzir,
+// as que já começaram terminam; as próximas esperam abrir vaga.
+func (c *transferConfig) SetConcurrency(n int) {
+	c.sem.SetLimit(n)
+}
+
+// SetBandwidthLimit muda o limite de banda em tempo de execução (0 remove o limite)
+func (c *transferConfig) SetBandwidthLimit(bytesPerSecond int64) {
+	c.limiter.SetRate(bytesPerSecond)
+}
@@ -0,0 +1,409 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// ==============================================================================
+// PIPELINE DE COMPRESSÃO E CRIPTOGRAFIA
+// ==============================================================================
+
+// Compression é o algoritmo aplicado antes da criptografia (dado cifrado não comprime)
+type Compression string
+
+const (
+	CompressionNone Compression = ""
+	CompressionGzip Compression = "gzip"
+	CompressionZstd Compression = "zstd"
+)
+
+func ParseCompression(name string) (Compression, error) {
+	switch name {
+	case "", "none":
+		return CompressionNone, nil
+	case "gzip":
+		return CompressionGzip, nil
+	case "zstd":
+		return CompressionZstd, nil
+	}
+	return CompressionNone, fmt.Errorf("unsupported compression %q (use gzip or zstd)", name)
+}
+
+const (
+	encryptionAlgorithm = "AES-256-GCM"
+	// segmentSize é o tamanho de cada bloco cifrado. O GCM autentica a mensagem inteira,
+	// então o stream é cortado em segmentos para não precisar do objeto todo em memória.
+	segmentSize = 64 * 1024
+)
+
+// Chaves do metadata do objeto (x-amz-meta-*), na forma canônica que o SDK devolve
+const (
+	metaCompression = "Cse-Compression"
+	metaAlgorithm   = "Cse-Algorithm"
+	metaKeyID       = "Cse-Key-Id"
+	metaWrappedKey  = "Cse-Wrapped-Key"
+	metaNonce       = "Cse-Nonce"
+	metaSegmentSize = "Cse-Segment-Size"
+	metaPlainSize   = "Cse-Plain-Size"
+)
+
+var errCorrupted = errors.New("encrypted data is corrupted, truncated or was tampered with")
+
+// objectEncoding descreve as transformações de um objeto. Vai no metadata e, nos
+// multipart, no checkpoint: a retomada precisa gerar exatamente os mesmos bytes.
+type objectEncoding struct {
+	Compression Compression `json:"compression,omitempty"`
+	Algorithm   string      `json:"algorithm,omitempty"`
+	KeyID       string      `json:"key_id,omitempty"`
+	WrappedKey  []byte      `json:"wrapped_key,omitempty"` // A chave de dados nunca é gravada aberta
+	Nonce       []byte      `json:"nonce,omitempty"`
+	SegmentSize int         `json:"segment_size,omitempty"`
+	PlainSize   int64       `json:"plain_size"` // Tamanho original; o objeto no bucket tem outro
+}
+
+func (c *transferConfig) encodes() bool {
+	return c.compression != CompressionNone || c.keys != nil
+}
+
+// newEncoding sorteia a chave de dados e o nonce de um objeto novo (nil se não há transformação)
+func (c *transferConfig) newEncoding(plainSize int64) (*objectEncoding, error) {
+	if !c.encodes() {
+		return nil, nil
+	}
+	enc := &objectEncoding{Compression: c.compression, PlainSize: plainSize}
+	if c.keys == nil {
+		return enc, nil
+	}
+
+	dataKey := make([]byte, dataKeySize)
+	nonce := make([]byte, 12)
+	if _, err := rand.Read(dataKey); err != nil {
+		return nil, err
+	}
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	wrapped, err := c.keys.WrapKey(dataKey)
+	if err != nil {
+		return nil, fmt.Errorf("wrap data key: %w", err)
+	}
+
+	enc.Algorithm = encryptionAlgorithm
+	enc.KeyID = c.keys.KeyID()
+	enc.WrappedKey = wrapped
+	enc.Nonce = nonce
+	enc.SegmentSize = segmentSize
+	return enc, nil
+}
+
+// encodingMatches diz se enc foi gerado com a configuração atual do worker
+func (c *transferConfig) encodingMatches(enc *objectEncoding) bool {
+	if enc == nil {
+		return !c.encodes()
+	}
+	if enc.Compression != c.compression {
+		return false
+	}
+	if c.keys == nil {
+		return enc.Algorithm == ""
+	}
+	return enc.Algorithm == encryptionAlgorithm && enc.KeyID == c.keys.KeyID()
+}
+
+// dataCipher abre a chave de dados de enc com o provider (nil se o objeto não é cifrado)
+func (c *transferConfig) dataCipher(enc *objectEncoding) (cipher.AEAD, error) {
+	if enc.Algorithm == "" {
+		return nil, nil
+	}
+	if c.keys == nil {
+		return nil, fmt.Errorf("object is encrypted with %s, but no key provider is configured", enc.KeyID)
+	}
+	dataKey, err := c.keys.UnwrapKey(enc.KeyID, enc.WrappedKey)
+	if err != nil {
+		return nil, err
+	}
+	return newGCM(dataKey)
+}
+
+// encodeReader devolve src comprimido e cifrado, produzido sob demanda numa goroutine.
+// Fechar o leitor antes do fim interrompe a goroutine.
+func (c *transferConfig) encodeReader(src io.Reader, enc *objectEncoding) (io.ReadCloser, error) {
+	aead, err := c.dataCipher(enc)
+	if err != nil {
+		return nil, err
+	}
+	pr, pw := io.Pipe()
+	go func() {
+		pw.CloseWithError(encodeTo(pw, src, enc, aead))
+	}()
+	return pr, nil
+}
+
+func encodeTo(dst io.Writer, src io.Reader, enc *objectEncoding, aead cipher.AEAD) error {
+	var out io.WriteCloser = nopWriteCloser{dst}
+	if aead != nil {
+		out = newSegmentWriter(dst, aead, enc.Nonce, enc.SegmentSize)
+	}
+
+	var compressor io.WriteCloser
+	switch enc.Compression {
+	case CompressionGzip:
+		compressor = gzip.NewWriter(out)
+	case CompressionZstd:
+		// Uma goroutine só: com a mesma entrada a saída é sempre igual, o que a retomada exige
+		zw, err := zstd.NewWriter(out, zstd.WithEncoderConcurrency(1))
+		if err != nil {
+			return err
+		}
+		compressor = zw
+	default:
+		compressor = nopWriteCloser{out}
+	}
+
+	if _, err := io.Copy(compressor, src); err != nil {
+		return err
+	}
+	if err := compressor.Close(); err != nil {
+		return err
+	}
+	return out.Close()
+}
+
+// decodeReader desfaz encodeReader: decifra (conferindo cada segmento) e descomprime
+func (c *transferConfig) decodeReader(src io.Reader, enc *objectEncoding) (io.ReadCloser, error) {
+	aead, err := c.dataCipher(enc)
+	if err != nil {
+		return nil, err
+	}
+	if aead != nil {
+		src = newSegmentReader(src, aead, enc.Nonce, enc.SegmentSize)
+	}
+
+	switch enc.Compression {
+	case CompressionGzip:
+		return gzip.NewReader(src)
+	case CompressionZstd:
+		zr, err := zstd.NewReader(src, zstd.WithDecoderConcurrency(1))
+		if err != nil {
+			return nil, err
+		}
+		return zr.IOReadCloser(), nil
+	}
+	return io.NopCloser(src), nil
+}
+
+func (e *objectEncoding) metadata() map[string]string {
+	if e == nil {
+		return nil
+	}
+	meta := map[string]string{metaPlainSize: strconv.FormatInt(e.PlainSize, 10)}
+	if e.Compression != CompressionNone {
+		meta[metaCompression] = string(e.Compression)
+	}
+	if e.Algorithm != "" {
+		meta[metaAlgorithm] = e.Algorithm
+		meta[metaKeyID] = e.KeyID
+		meta[metaWrappedKey] = base64.StdEncoding.EncodeToString(e.WrappedKey)
+		meta[metaNonce] = base64.StdEncoding.EncodeToString(e.Nonce)
+		meta[metaSegmentSize] = strconv.Itoa(e.SegmentSize)
+	}
+	return meta
+}
+
+// parseObjectEncoding lê o metadata de um objeto; nil quando ele foi enviado sem transformação
+func parseObjectEncoding(meta map[string]string) (*objectEncoding, error) {
+	get := func(name string) string {
+		for key, value := range meta {
+			if strings.EqualFold(key, name) {
+				return value
+			}
+		}
+		return ""
+	}
+
+	algorithm := get(metaAlgorithm)
+	compression, err := ParseCompression(get(metaCompression))
+	if err != nil {
+		return nil, err
+	}
+	if compression == CompressionNone && algorithm == "" {
+		return nil, nil
+	}
+
+	enc := &objectEncoding{Compression: compression, Algorithm: algorithm}
+	if enc.PlainSize, err = strconv.ParseInt(get(metaPlainSize), 10, 64); err != nil {
+		return nil, fmt.Errorf("invalid %s metadata: %w", metaPlainSize, err)
+	}
+	if algorithm == "" {
+		return enc, nil
+	}
+	if algorithm != encryptionAlgorithm {
+		return nil, fmt.Errorf("unsupported encryption algorithm %q", algorithm)
+	}
+
+	enc.KeyID = get(metaKeyID)
+	if enc.WrappedKey, err = base64.StdEncoding.DecodeString(get(metaWrappedKey)); err != nil {
+		return nil, fmt.Errorf("invalid %s metadata: %w", metaWrappedKey, err)
+	}
+	if enc.Nonce, err = base64.StdEncoding.DecodeString(get(metaNonce)); err != nil || len(enc.Nonce) != 12 {
+		return nil, fmt.Errorf("invalid %s metadata", metaNonce)
+	}
+	if enc.SegmentSize, err = strconv.Atoi(get(metaSegmentSize)); err != nil || enc.SegmentSize <= 0 {
+		return nil, fmt.Errorf("invalid %s metadata", metaSegmentSize)
+	}
+	return enc, nil
+}
+
+// ==============================================================================
+// SEGMENTOS CIFRADOS
+// ==============================================================================
+
+// Cada segmento é selado com o nonce base combinado ao seu número (impede reordenar) e
+// com um AAD que marca o último (impede cortar o stream numa fronteira de segmento).
+var (
+	aadMiddle = []byte{0}
+	aadLast   = []byte{1}
+)
+
+type segmentWriter struct {
+	w       io.Writer
+	aead    cipher.AEAD
+	nonce   []byte
+	buf     []byte
+	sealed  []byte
+	counter uint32
+}
+
+func newSegmentWriter(w io.Writer, aead cipher.AEAD, nonce []byte, size int) *segmentWriter {
+	return &segmentWriter{
+		w:      w,
+		aead:   aead,
+		nonce:  nonce,
+		buf:    make([]byte, 0, size),
+		sealed: make([]byte, 0, size+aead.Overhead()),
+	}
+}
+
+func (s *segmentWriter) Write(p []byte) (int, error) {
+	written := 0
+	for len(p) > 0 {
+		// Só sela um segmento cheio quando chega mais dado: o último precisa da marca de final
+		if len(s.buf) == cap(s.buf) {
+			if err := s.seal(aadMiddle); err != nil {
+				return written, err
+			}
+		}
+		n := copy(s.buf[len(s.buf):cap(s.buf)], p)
+		s.buf = s.buf[:len(s.buf)+n]
+		p = p[n:]
+		written += n
+	}
+	return written, nil
+}
+
+// Close sela o que sobrou (mesmo vazio) como último segmento
+func (s *segmentWriter) Close() error {
+	return s.seal(aadLast)
+}
+
+func (s *segmentWriter) seal(aad []byte) error {
+	if s.counter == math.MaxUint32 {
+		return errors.New("too many encrypted segments")
+	}
+	s.sealed = s.aead.Seal(s.sealed[:0], segmentNonce(s.nonce, s.counter), s.buf, aad)
+	s.counter++
+	s.buf = s.buf[:0]
+	_, err := s.w.Write(s.sealed)
+	return err
+}
+
+type segmentReader struct {
+	r       *bufio.Reader
+	aead    cipher.AEAD
+	nonce   []byte
+	buf     []byte
+	plain   []byte
+	counter uint32
+	done    bool
+}
+
+func newSegmentReader(r io.Reader, aead cipher.AEAD, nonce []byte, size int) *segmentReader {
+	return &segmentReader{
+		r:     bufio.NewReader(r),
+		aead:  aead,
+		nonce: nonce,
+		buf:   make([]byte, size+aead.Overhead()),
+	}
+}
+
+func (s *segmentReader) Read(p []byte) (int, error) {
+	for len(s.plain) == 0 {
+		if s.done {
+			return 0, io.EOF
+		}
+		if err := s.next(); err != nil {
+			return 0, err
+		}
+	}
+	n := copy(p, s.plain)
+	s.plain = s.plain[n:]
+	return n, nil
+}
+
+func (s *segmentReader) next() error {
+	n, err := io.ReadFull(s.r, s.buf)
+	last := false
+	switch {
+	case err == io.EOF || err == io.ErrUnexpectedEOF:
+		last = true
+	case err != nil:
+		return err
+	default:
+		// Segmento cheio: é o último se não vier mais nada depois dele
+		if _, err := s.r.Peek(1); err == io.EOF {
+			last = true
+		} else if err != nil {
+			return err
+		}
+	}
+
+	aad := aadMiddle
+	if last {
+		aad = aadLast
+	}
+	plain, err := s.aead.Open(s.buf[:0], segmentNonce(s.nonce, s.counter), s.buf[:n], aad)
+	if err != nil {
+		return errCorrupted
+	}
+	s.plain = plain
+	s.counter++
+	s.done = last
+	return nil
+}
+
+func segmentNonce(base []byte, counter uint32) []byte {
+	nonce := bytes.Clone(base)
+	tail := nonce[len(nonce)-4:]
+	binary.BigEndian.PutUint32(tail, binary.BigEndian.Uint32(tail)^counter)
+	return nonce
+}
+
+type nopWriteCloser struct {
+	io.Writer
+}
+
+func (nopWriteCloser) Close() error { return nil }
@@ -0,0 +1,195 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+// ==============================================================================
+// PROGRESSO
+// ==============================================================================
+
+// FileProgress é o andamento de um arquivo
+type FileProgress struct {
+	Key         string
+	Size        int64
+	Transferred int64
+	Done        bool
+	Err         error
+}
+
+// TotalProgress é o andamento de todos os arquivos de uma chamada
+type TotalProgress struct {
+	Files       int
+	FilesDone   int
+	Size        int64
+	Transferred int64
+	Elapsed     time.Duration
+}
+
+// ProgressFunc recebe o arquivo que mudou e o total. As chamadas nunca são simultâneas.
+type ProgressFunc func(file FileProgress, total TotalProgress)
+
+// WithProgress registra um callback de progresso
+func WithProgress(fn ProgressFunc) TransferOption {
+	return func(c *transferConfig) { c.progress = fn }
+}
+
+// progressTracker soma o progresso de várias goroutines e repassa ao callback
+type progressTracker struct {
+	mu    sync.Mutex
+	fn    ProgressFunc
+	start time.Time
+	files map[string]*FileProgress
+	total TotalProgress
+}
+
+func newProgressTracker(fn ProgressFunc) *progressTracker {
+	return &progressTracker{fn: fn, start: time.Now(), files: make(map[string]*FileProgress)}
+}
+
+// track registra um arquivo antes de começar, para o total já nascer com o tamanho certo
+func (p *progressTracker) track(key string, size int64) {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.files[key] = &FileProgress{Key: key, Size: size}
+	p.total.Files++
+	p.total.Size += size
+}
+
+func (p *progressTracker) add(key string, delta int64) {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	file := p.files[key]
+	file.Transferred += delta
+	p.total.Transferred += delta
+	p.notify(file)
+}
+
+// reset zera o progresso de um arquivo antes de uma nova tentativa
+func (p *progressTracker) reset(key string) {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	file := p.files[key]
+	p.total.Transferred -= file.Transferred
+	file.Transferred = 0
+}
+
+func (p *progressTracker) finish(key string, err error) {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	file := p.files[key]
+	file.Done, file.Err = true, err
+	p.total.FilesDone++
+	p.notify(file)
+}
+
+// adder devolve a função de progresso de um arquivo para o transferReader
+func (p *progressTracker) adder(key string) func(int64) {
+	if p == nil {
+		return nil
+	}
+	return func(delta int64) { p.add(key, delta) }
+}
+
+func (p *progressTracker) notify(file *FileProgress) {
+	if p.fn == nil {
+		return
+	}
+	p.total.Elapsed = time.Since(p.start)
+	p.fn(*file, p.total)
+}
+
+// ==============================================================================
+// RENDERIZAÇÃO NO TERMINAL
+// ==============================================================================
+
+// progressRenderer desenha uma linha de status que se atualiza no lugar, e imprime
+// uma linha fixa para cada arquivo concluído
+type progressRenderer struct {
+	out      io.Writer
+	interval time.Duration
+	last     time.Time
+}
+
+// NewProgressRenderer devolve um ProgressFunc que escreve em out (normalmente os.Stderr)
+func NewProgressRenderer(out io.Writer) ProgressFunc {
+	r := &progressRenderer{out: out, interval: 200 * time.Millisecond}
+	return r.render
+}
+
+func (r *progressRenderer) render(file FileProgress, total TotalProgress) {
+	finished := total.FilesDone == total.Files
+	if !file.Done && !finished && time.Since(r.last) < r.interval {
+		return
+	}
+	r.last = time.Now()
+
+	// \r volta ao início da linha e \033[K limpa o que sobrou da linha anterior
+	if file.Done {
+		if file.Err != nil {
+			fmt.Fprintf(r.out, "\r\033[K   ❌ %s: %v\n", file.Key, file.Err)
+		} else {
+			fmt.Fprintf(r.out, "\r\033[K   ✅ %s (%s)\n", file.Key, formatBytes(file.Size))
+		}
+	}
+
+	fmt.Fprintf(r.out, "\r\033[K📦 %d/%d arquivos | %s / %s (%.0f%%) | %s/s | ETA %s",
+		total.FilesDone, total.Files,
+		formatBytes(total.Transferred), formatBytes(total.Size), percent(total.Transferred, total.Size),
+		formatBytes(int64(rate(total))), eta(total))
+
+	if finished {
+		fmt.Fprintln(r.out)
+	}
+}
+
+func percent(done, size int64) float64 {
+	if size == 0 {
+		return 100
+	}
+	return float64(done) / float64(size) * 100
+}
+
+func rate(total TotalProgress) float64 {
+	if total.Elapsed <= 0 {
+		return 0
+	}
+	return float64(total.Transferred) / total.Elapsed.Seconds()
+}
+
+func eta(total TotalProgress) string {
+	r := rate(total)
+	if r <= 0 || total.Transferred >= total.Size {
+		return "-"
+	}
+	remaining := time.Duration(float64(total.Size-total.Transferred) / r * float64(time.Second))
+	return remaining.Round(time.Second).String()
+}
+
+func formatBytes(n int64) string {
+	switch {
+	case n >= 1<<30:
+		return fmt.Sprintf("%.2f GB", float64(n)/(1<<30))
+	case n >= 1<<20:
+		return fmt.Sprintf("%.2f MB", float64(n)/(1<<20))
+	case n >= 1<<10:
+		return fmt.Sprintf("%.1f KB", float64(n)/(1<<10))
+	default:
+		return fmt.Sprintf("%d B", n)
+	}
+}
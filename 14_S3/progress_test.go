@@ -0,0 +1,173 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// progressRecorder guarda os eventos e confere que o total nunca passa do tamanho
+type progressRecorder struct {
+	mu       sync.Mutex
+	t        *testing.T
+	last     TotalProgress
+	finished map[string]FileProgress
+}
+
+func newProgressRecorder(t *testing.T) *progressRecorder {
+	return &progressRecorder{t: t, finished: make(map[string]FileProgress)}
+}
+
+func (r *progressRecorder) record(file FileProgress, total TotalProgress) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if total.Transferred > total.Size || file.Transferred > file.Size || file.Transferred < 0 {
+		r.t.Errorf("progress out of bounds: file %+v total %+v", file, total)
+	}
+	r.last = total
+	if file.Done {
+		r.finished[file.Key] = file
+	}
+}
+
+func TestUploadProgressCountsRetriesOnce(t *testing.T) {
+	fake, client := newFakeS3(t)
+	fake.failTimes("UploadPart", 2, 1, 500)
+	big, _ := writeTestFile(t, 3000)
+	small, _ := writeTestFile(t, 500)
+	recorder := newProgressRecorder(t)
+
+	results := newTestWorker(client, t.TempDir(), WithProgress(recorder.record)).
+		UploadMultipleFiles(context.Background(), []string{big, small})
+
+	for _, r := range results {
+		if !r.Success {
+			t.Fatalf("upload failed: %v", r.Error)
+		}
+	}
+	if recorder.last.Size != 3500 || recorder.last.Transferred != 3500 || recorder.last.FilesDone != 2 {
+		t.Fatalf("unexpected final progress: %+v", recorder.last)
+	}
+	if f := recorder.finished[big]; f.Transferred != 3000 || f.Err != nil {
+		t.Fatalf("unexpected file progress: %+v", f)
+	}
+}
+
+func TestUploadBandwidthLimit(t *testing.T) {
+	_, client := newFakeS3(t)
+	path, _ := writeTestFile(t, 3000)
+	start := time.Now()
+
+	// 3000 bytes a 5000 B/s: ~600ms, mesmo com o multipart em 3 partes
+	results := newTestWorker(client, t.TempDir(), WithBandwidthLimit(5000)).
+		UploadMultipleFiles(context.Background(), []string{path})
+
+	if !results[0].Success {
+		t.Fatal(results[0].Error)
+	}
+	if elapsed := time.Since(start); elapsed < 500*time.Millisecond {
+		t.Fatalf("upload was not throttled, took %v", elapsed)
+	}
+}
+
+func TestUploadCancelledBeforeStart(t *testing.T) {
+	fake, client := newFakeS3(t)
+	path, _ := writeTestFile(t, 100)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	results := newTestWorker(client, t.TempDir()).UploadMultipleFiles(ctx, []string{path})
+
+	if results[0].Success || !errors.Is(results[0].Error, context.Canceled) {
+		t.Fatalf("expected context canceled, got %+v", results[0])
+	}
+	if fake.count("PutObject") != 0 {
+		t.Fatal("no request should be sent after cancellation")
+	}
+}
+
+func TestUploadCancelledMidwayResumes(t *testing.T) {
+	fake, client := newFakeS3(t)
+	path, content := writeTestFile(t, 4000)
+	checkpointDir := t.TempDir()
+	ctx, cancel := context.WithCancel(context.Background())
+
+	// Cancela assim que a parte 3 chega ao servidor
+	fake.inject = func(op string, r *http.Request) int {
+		if op == "UploadPart" && r.URL.Query().Get("partNumber") == "3" {
+			cancel()
+			return 500
+		}
+		return 0
+	}
+
+	results := newTestWorker(client, checkpointDir).UploadMultipleFiles(ctx, []string{path})
+	if results[0].Success {
+		t.Fatal("expected cancelled upload to fail")
+	}
+	if results[0].Attempts != 1 {
+		t.Fatalf("cancelled upload should not be retried, got %d attempts", results[0].Attempts)
+	}
+
+	fake.inject = nil
+	results = newTestWorker(client, checkpointDir).UploadMultipleFiles(context.Background(), []string{path})
+	if !results[0].Success {
+		t.Fatalf("resume failed: %v", results[0].Error)
+	}
+	if fake.count("UploadPart:1") != 1 || fake.count("CreateMultipartUpload") != 1 {
+		t.Fatalf("expected resume after cancellation, calls: %v", fake.calls)
+	}
+	if got, _ := fake.object(testBucket, path); !bytes.Equal(got, content) {
+		t.Fatal("stored object does not match the file")
+	}
+}
+
+func TestDownloadProgressIncludesResumedRanges(t *testing.T) {
+	fake, client := newFakeS3(t)
+	fake.put(testBucket, "obj", randomContent(t, 3000), fake.now())
+	fake.failRange("2048-2999", 1000, 500)
+	dest := filepath.Join(t.TempDir(), "obj")
+	checkpointDir := t.TempDir()
+
+	newTestDownloader(client, checkpointDir).Download(context.Background(), "obj", dest)
+
+	fake.inject = nil
+	recorder := newProgressRecorder(t)
+	result := newTestDownloader(client, checkpointDir, WithProgress(recorder.record)).Download(context.Background(), "obj", dest)
+
+	if !result.Success {
+		t.Fatal(result.Error)
+	}
+	if recorder.last.Transferred != 3000 || recorder.last.FilesDone != 1 {
+		t.Fatalf("unexpected final progress: %+v", recorder.last)
+	}
+}
+
+func TestProgressRenderer(t *testing.T) {
+	var out bytes.Buffer
+	render := NewProgressRenderer(&out)
+
+	render(FileProgress{Key: "a.txt", Size: 2048, Transferred: 1024},
+		TotalProgress{Files: 2, Size: 4096, Transferred: 1024, Elapsed: time.Second})
+	render(FileProgress{Key: "a.txt", Size: 2048, Transferred: 2048, Done: true},
+		TotalProgress{Files: 2, FilesDone: 1, Size: 4096, Transferred: 2048, Elapsed: 2 * time.Second})
+	render(FileProgress{Key: "b.txt", Size: 2048, Done: true, Err: os.ErrPermission},
+		TotalProgress{Files: 2, FilesDone: 2, Size: 4096, Transferred: 2048, Elapsed: 2 * time.Second})
+
+	got := out.String()
+	for _, want := range []string{"1/2 arquivos", "✅ a.txt (2.0 KB)", "❌ b.txt: permission denied", "2/2 arquivos", "(50%)"} {
+		if !strings.Contains(got, want) {
+			t.Fatalf("output missing %q:\n%q", want, got)
+		}
+	}
+	if !strings.HasSuffix(got, "\n") {
+		t.Fatal("renderer should end the line when all files are done")
+	}
+}
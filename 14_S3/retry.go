@@ -0,0 +1,90 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math/rand/v2"
+	"net"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
+)
+
+// ==============================================================================
+// RETRY COM BACKOFF EXPONENCIAL E JITTER
+// ==============================================================================
+
+// RetryPolicy define quantas vezes e com que espera uma operação é repetida
+type RetryPolicy struct {
+	MaxAttempts int           // Total de tentativas (1 = sem retry)
+	BaseDelay   time.Duration // Espera antes da 2ª tentativa
+	MaxDelay    time.Duration // Teto da espera entre tentativas
+}
+
+var defaultRetryPolicy = RetryPolicy{
+	MaxAttempts: maxRetries,
+	BaseDelay:   200 * time.Millisecond,
+	MaxDelay:    10 * time.Second,
+}
+
+// Do executa fn até dar certo, até um erro não recuperável, até esgotar as tentativas
+// ou até ctx ser cancelado. Retorna o número de tentativas feitas junto com o último erro.
+func (p RetryPolicy) Do(ctx context.Context, fn func() error) (int, error) {
+	attempts := max(p.MaxAttempts, 1)
+	var err error
+	for attempt := 1; attempt <= attempts; attempt++ {
+		if err = fn(); err == nil || !isRetryable(err) || ctx.Err() != nil {
+			return attempt, err
+		}
+		if attempt < attempts {
+			timer := time.NewTimer(p.backoff(attempt))
+			select {
+			case <-timer.C:
+			case <-ctx.Done():
+				timer.Stop()
+				return attempt, err
+			}
+		}
+	}
+	return attempts, err
+}
+
+// backoff usa "full jitter": espera aleatória entre 0 e BaseDelay*2^(attempt-1),
+// limitada a MaxDelay, para que workers que falharam juntos não tentem juntos de novo
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	delay := p.BaseDelay << (attempt - 1)
+	if delay <= 0 || (p.MaxDelay > 0 && delay > p.MaxDelay) {
+		delay = p.MaxDelay
+	}
+	if delay <= 0 {
+		return 0
+	}
+	return rand.N(delay) + 1
+}
+
+// isRetryable separa falhas temporárias (5xx, throttling, rede) de erros que não
+// mudam com uma nova tentativa (4xx, arquivo local inexistente)
+func isRetryable(err error) bool {
+	// Conexão que cai no meio da leitura do corpo de um GetObject não passa pelo SDK
+	var netErr net.Error
+	if errors.As(err, &netErr) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return true
+	}
+
+	var reqErr awserr.RequestFailure
+	if errors.As(err, &reqErr) {
+		code := reqErr.StatusCode()
+		return code >= 500 || code == 429 || code == 408
+	}
+	var awsErr awserr.Error
+	if errors.As(err, &awsErr) {
+		switch awsErr.Code() {
+		case request.ErrCodeRequestError, request.ErrCodeResponseTimeout, request.ErrCodeSerialization,
+			"SlowDown", "RequestTimeout", "Throttling", "ThrottlingException":
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
+)
+
+func TestRetryPolicyBackoffIsCapped(t *testing.T) {
+	policy := RetryPolicy{MaxAttempts: 10, BaseDelay: 10 * time.Millisecond, MaxDelay: 50 * time.Millisecond}
+
+	for attempt := 1; attempt <= 10; attempt++ {
+		for range 100 {
+			if d := policy.backoff(attempt); d <= 0 || d > policy.MaxDelay {
+				t.Fatalf("attempt %d: backoff %v outside (0, %v]", attempt, d, policy.MaxDelay)
+			}
+		}
+	}
+
+	// Com jitter a espera da 1ª tentativa nunca passa de BaseDelay
+	for range 100 {
+		if d := policy.backoff(1); d > policy.BaseDelay {
+			t.Fatalf("first backoff %v exceeds base delay", d)
+		}
+	}
+}
+
+func TestRetryPolicyStopsOnSuccess(t *testing.T) {
+	policy := RetryPolicy{MaxAttempts: 5, BaseDelay: time.Millisecond}
+	serverErr := awserr.NewRequestFailure(awserr.New("InternalError", "boom", nil), 500, "req")
+
+	calls := 0
+	attempts, err := policy.Do(context.Background(), func() error {
+		calls++
+		if calls < 3 {
+			return serverErr
+		}
+		return nil
+	})
+	if err != nil || attempts != 3 {
+		t.Fatalf("expected success on 3rd attempt, got %d attempts, err %v", attempts, err)
+	}
+
+	attempts, err = policy.Do(context.Background(), func() error { return serverErr })
+	if attempts != 5 || !errors.Is(err, serverErr) {
+		t.Fatalf("expected 5 attempts ending in the last error, got %d, %v", attempts, err)
+	}
+}
+
+func TestIsRetryable(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"5xx", awserr.NewRequestFailure(awserr.New("InternalError", "", nil), 500, ""), true},
+		{"503 slow down", awserr.NewRequestFailure(awserr.New("SlowDown", "", nil), 503, ""), true},
+		{"429", awserr.NewRequestFailure(awserr.New("TooManyRequests", "", nil), 429, ""), true},
+		{"403", awserr.NewRequestFailure(awserr.New("AccessDenied", "", nil), 403, ""), false},
+		{"404", awserr.NewRequestFailure(awserr.New("NoSuchUpload", "", nil), 404, ""), false},
+		{"network", awserr.New("RequestError", "send request failed", errors.New("connection reset")), true},
+		{"body cut", fmt.Errorf("reading part 2: %w", io.ErrUnexpectedEOF), true},
+		{"local", errors.New("open file.txt: no such file or directory"), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isRetryable(tt.err); got != tt.want {
+				t.Fatalf("isRetryable(%v) = %v, want %v", tt.err, got, tt.want)
+			}
+		})
+	}
+}
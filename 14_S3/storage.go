@@ -0,0 +1,126 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// ==============================================================================
+// ABSTRAÇÃO DE OBJECT STORAGE
+// ==============================================================================
+
+// Storage é o que os workers usam de um object storage. Cada instância aponta para um
+// único bucket (ou diretório, ou namespace em memória). ETags circulam sem aspas e seguem
+// a regra do S3 (MD5, ou MD5 dos MD5 das partes + "-N"), em qualquer backend.
+type Storage interface {
+	// Location identifica o destino (s3://bucket, file:///dir, memory://...) em checkpoints e mensagens
+	Location() string
+
+	PutObject(ctx context.Context, key string, body io.ReadSeeker, opts PutOptions) (ObjectInfo, error)
+	GetObject(ctx context.Context, key string, opts GetOptions) (io.ReadCloser, ObjectInfo, error)
+	// HeadObject com partNumber > 0 devolve o tamanho daquela parte (objetos multipart)
+	HeadObject(ctx context.Context, key string, partNumber int64) (ObjectInfo, error)
+	ListObjects(ctx context.Context, prefix string) ([]ObjectInfo, error)
+	DeleteObjects(ctx context.Context, keys []string) (int, error)
+
+	CreateMultipartUpload(ctx context.Context, key string, opts PutOptions) (string, error)
+	UploadPart(ctx context.Context, key, uploadID string, partNumber int64, body io.ReadSeeker) (string, error)
+	ListParts(ctx context.Context, key, uploadID string) ([]PartInfo, error)
+	CompleteMultipartUpload(ctx context.Context, key, uploadID string, parts []PartInfo) (ObjectInfo, error)
+	AbortMultipartUpload(ctx context.Context, key, uploadID string) error
+
+	PresignGetObject(ctx context.Context, key string, expires time.Duration) (string, error)
+}
+
+// ObjectInfo descreve um objeto (ou uma parte, no HeadObject com partNumber)
+type ObjectInfo struct {
+	Key          string
+	Size         int64
+	ETag         string
+	LastModified time.Time
+	ContentType  string
+	Metadata     map[string]string // Metadata do usuário (x-amz-meta-* no S3)
+	PartsCount   int               // Só no HeadObject de uma parte
+	Encryption   string            // SSE do lado do S3: "AES256", "aws:kms", "aws:kms:dsse" ou "SSE-C"
+}
+
+type PutOptions struct {
+	ContentType string
+	Metadata    map[string]string
+}
+
+// GetOptions restringe o GetObject. Com Length 0 o objeto é lido até o fim.
+type GetOptions struct {
+	Offset  int64
+	Length  int64
+	IfMatch string // Falha com ErrPreconditionFailed se o ETag atual for outro
+}
+
+// PartInfo é uma parte de um multipart upload; no Complete, Size é ignorado
+type PartInfo struct {
+	PartNumber int64
+	ETag       string
+	Size       int64
+}
+
+// Erros comuns a todos os backends. O backend S3 embrulha o erro do SDK junto,
+// para que isRetryable continue enxergando o status HTTP.
+var (
+	ErrNotFound           = errors.New("object not found")
+	ErrNoSuchUpload       = errors.New("multipart upload not found")
+	ErrPreconditionFailed = errors.New("object etag does not match")
+	ErrInvalidPart        = errors.New("invalid part")
+	ErrInvalidKey         = errors.New("invalid object key")
+)
+
+// ==============================================================================
+// ESCOLHA DO BACKEND POR CONFIGURAÇÃO
+// ==============================================================================
+
+const (
+	BackendS3     = "s3"
+	BackendLocal  = "local"
+	BackendMemory = "memory"
+
+	defaultStorageDir = "storage-data"
+)
+
+// StorageConfig escolhe o backend. Com "local", cada bucket vira um subdiretório de Dir.
+type StorageConfig struct {
+	Backend string
+	Bucket  string
+	Dir     string
+}
+
+// StorageConfigFromEnv lê STORAGE_BACKEND (s3, local ou memory; padrão s3) e STORAGE_DIR
+func StorageConfigFromEnv(bucket string) StorageConfig {
+	cfg := StorageConfig{
+		Backend: os.Getenv("STORAGE_BACKEND"),
+		Bucket:  bucket,
+		Dir:     os.Getenv("STORAGE_DIR"),
+	}
+	if cfg.Backend == "" {
+		cfg.Backend = BackendS3
+	}
+	if cfg.Dir == "" {
+		cfg.Dir = defaultStorageDir
+	}
+	return cfg
+}
+
+func NewStorage(cfg StorageConfig) (Storage, error) {
+	switch cfg.Backend {
+	case BackendS3:
+		return NewS3Storage(setupS3Client(), cfg.Bucket), nil
+	case BackendLocal:
+		return NewLocalStorage(filepath.Join(cfg.Dir, cfg.Bucket))
+	case BackendMemory:
+		return NewMemoryStorage(), nil
+	}
+	return nil, fmt.Errorf("unknown storage backend %q (use s3, local or memory)", cfg.Backend)
+}
@@ -0,0 +1,458 @@
+package main
+
+import (
+	"context"
+	"crypto/md5"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ==============================================================================
+// BACKEND EM DIRETÓRIO LOCAL
+// ==============================================================================
+
+// localStateDir guarda metadata, uploads em andamento e temporários, fora do espaço de keys
+const localStateDir = ".storage"
+
+// LocalStorage grava cada objeto como um arquivo em root/<key>. ETag, content type e
+// metadata ficam em root/.storage/meta/<key>.json. Uma "/" no início da key é ignorada.
+// Diferente do S3, uma key não pode ser ao mesmo tempo objeto e "pasta" ("a" e "a/b").
+type LocalStorage struct {
+	root string
+	mu   sync.RWMutex // Troca de arquivo e metadata acontece junta sob o lock de escrita
+}
+
+type localMeta struct {
+	ETag        string            `json:"etag"`
+	ContentType string            `json:"content_type,omitempty"`
+	Metadata    map[string]string `json:"metadata,omitempty"`
+	PartSizes   []int64           `json:"part_sizes,omitempty"`
+}
+
+type localUpload struct {
+	Key         string            `json:"key"`
+	ContentType string            `json:"content_type,omitempty"`
+	Metadata    map[string]string `json:"metadata,omitempty"`
+}
+
+func NewLocalStorage(root string) (*LocalStorage, error) {
+	root, err := filepath.Abs(root)
+	if err != nil {
+		return nil, err
+	}
+	for _, dir := range []string{"meta", "uploads", "tmp"} {
+		if err := os.MkdirAll(filepath.Join(root, localStateDir, dir), 0o755); err != nil {
+			return nil, err
+		}
+	}
+	return &LocalStorage{root: root}, nil
+}
+
+func (l *LocalStorage) Location() string {
+	return "file://" + filepath.ToSlash(l.root)
+}
+
+func (l *LocalStorage) PutObject(ctx context.Context, key string, body io.ReadSeeker, opts PutOptions) (ObjectInfo, error) {
+	rel, err := localKey(key)
+	if err != nil {
+		return ObjectInfo{}, err
+	}
+	tmp, etag, err := l.writeTemp(body)
+	if err != nil {
+		return ObjectInfo{}, err
+	}
+	defer os.Remove(tmp)
+
+	meta := localMeta{ETag: etag, ContentType: opts.ContentType, Metadata: opts.Metadata}
+	if err := l.commit(rel, tmp, meta); err != nil {
+		return ObjectInfo{}, err
+	}
+	return l.HeadObject(ctx, key, 0)
+}
+
+func (l *LocalStorage) GetObject(ctx context.Context, key string, opts GetOptions) (io.ReadCloser, ObjectInfo, error) {
+	rel, err := localKey(key)
+	if err != nil {
+		return nil, ObjectInfo{}, err
+	}
+
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	info, meta, err := l.stat(rel)
+	if err != nil {
+		return nil, ObjectInfo{}, err
+	}
+	if opts.IfMatch != "" && opts.IfMatch != info.ETag {
+		return nil, ObjectInfo{}, fmt.Errorf("%s: %w", key, ErrPreconditionFailed)
+	}
+
+	// O arquivo aberto continua válido mesmo se outro PutObject trocar o objeto depois
+	file, err := os.Open(l.objectPath(rel))
+	if err != nil {
+		return nil, ObjectInfo{}, err
+	}
+	length := info.Size - opts.Offset
+	if opts.Offset != 0 || opts.Length != 0 {
+		if opts.Offset < 0 || opts.Offset >= info.Size {
+			file.Close()
+			return nil, ObjectInfo{}, fmt.Errorf("range starting at %d is outside a %d-byte object", opts.Offset, info.Size)
+		}
+		if opts.Length > 0 {
+			length = min(opts.Length, length)
+		}
+	}
+
+	info.Size = length
+	info.Metadata, info.ContentType = meta.Metadata, meta.ContentType
+	reader := io.NewSectionReader(file, opts.Offset, length)
+	return struct {
+		io.Reader
+		io.Closer
+	}{reader, file}, info, nil
+}
+
+func (l *LocalStorage) HeadObject(ctx context.Context, key string, partNumber int64) (ObjectInfo, error) {
+	rel, err := localKey(key)
+	if err != nil {
+		return ObjectInfo{}, err
+	}
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	info, meta, err := l.stat(rel)
+	if err != nil {
+		return ObjectInfo{}, err
+	}
+	return partInfo(info, meta.PartSizes, partNumber)
+}
+
+func (l *LocalStorage) ListObjects(ctx context.Context, prefix string) ([]ObjectInfo, error) {
+	prefix = strings.TrimPrefix(prefix, "/")
+	state := filepath.Join(l.root, localStateDir)
+
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	var objects []ObjectInfo
+	err := filepath.WalkDir(l.root, func(filePath string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if filePath == state {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		rel, err := filepath.Rel(l.root, filePath)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		if !d.Type().IsRegular() || !strings.HasPrefix(rel, prefix) {
+			return nil
+		}
+		info, _, err := l.stat(rel)
+		if err != nil {
+			return err
+		}
+		info.Metadata, info.ContentType = nil, "" // Como no ListObjectsV2
+		objects = append(objects, info)
+		return nil
+	})
+	// O WalkDir ordena por diretório ("a/b" antes de "a-b"); o S3 ordena pela key inteira
+	slices.SortFunc(objects, func(a, b ObjectInfo) int { return strings.Compare(a.Key, b.Key) })
+	return objects, err
+}
+
+// DeleteObjects segue o S3: apagar uma key que não existe não é erro
+func (l *LocalStorage) DeleteObjects(ctx context.Context, keys []string) (int, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	deleted := 0
+	for _, key := range keys {
+		rel, err := localKey(key)
+		if err != nil {
+			return deleted, err
+		}
+		if err := removeIfExists(l.objectPath(rel)); err != nil {
+			return deleted, err
+		}
+		if err := removeIfExists(l.metaPath(rel)); err != nil {
+			return deleted, err
+		}
+		l.removeEmptyDirs(filepath.Dir(l.objectPath(rel)))
+		deleted++
+	}
+	return deleted, nil
+}
+
+func (l *LocalStorage) CreateMultipartUpload(ctx context.Context, key string, opts PutOptions) (string, error) {
+	if _, err := localKey(key); err != nil {
+		return "", err
+	}
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return "", err
+	}
+	uploadID := hex.EncodeToString(id)
+	upload := localUpload{Key: key, ContentType: opts.ContentType, Metadata: opts.Metadata}
+	return uploadID, saveJSON(filepath.Join(l.uploadDir(uploadID), "upload.json"), upload)
+}
+
+func (l *LocalStorage) UploadPart(ctx context.Context, key, uploadID string, partNumber int64, body io.ReadSeeker) (string, error) {
+	if _, err := l.upload(key, uploadID); err != nil {
+		return "", err
+	}
+	tmp, etag, err := l.writeTemp(body)
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(tmp)
+	return etag, os.Rename(tmp, filepath.Join(l.uploadDir(uploadID), strconv.FormatInt(partNumber, 10)))
+}
+
+func (l *LocalStorage) ListParts(ctx context.Context, key, uploadID string) ([]PartInfo, error) {
+	if _, err := l.upload(key, uploadID); err != nil {
+		return nil, err
+	}
+	entries, err := os.ReadDir(l.uploadDir(uploadID))
+	if err != nil {
+		return nil, err
+	}
+
+	var parts []PartInfo
+	for _, entry := range entries {
+		n, err := strconv.ParseInt(entry.Name(), 10, 64)
+		if err != nil {
+			continue // upload.json
+		}
+		etag, size, err := fileETag(filepath.Join(l.uploadDir(uploadID), entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+		parts = append(parts, PartInfo{PartNumber: n, ETag: etag, Size: size})
+	}
+	slices.SortFunc(parts, func(a, b PartInfo) int { return int(a.PartNumber - b.PartNumber) })
+	return parts, nil
+}
+
+func (l *LocalStorage) CompleteMultipartUpload(ctx context.Context, key, uploadID string, parts []PartInfo) (ObjectInfo, error) {
+	upload, err := l.upload(key, uploadID)
+	if err != nil {
+		return ObjectInfo{}, err
+	}
+	rel, err := localKey(key)
+	if err != nil {
+		return ObjectInfo{}, err
+	}
+
+	out, err := os.CreateTemp(filepath.Join(l.root, localStateDir, "tmp"), "complete-*")
+	if err != nil {
+		return ObjectInfo{}, err
+	}
+	defer os.Remove(out.Name())
+	defer out.Close()
+
+	sizes := make([]int64, 0, len(parts))
+	for _, part := range parts {
+		size, err := appendPart(out, filepath.Join(l.uploadDir(uploadID), strconv.FormatInt(part.PartNumber, 10)), part.ETag)
+		if err != nil {
+			return ObjectInfo{}, fmt.Errorf("part %d of %s: %w", part.PartNumber, key, err)
+		}
+		sizes = append(sizes, size)
+	}
+	if err := out.Close(); err != nil {
+		return ObjectInfo{}, err
+	}
+
+	etag, err := combinedETag(parts)
+	if err != nil {
+		return ObjectInfo{}, err
+	}
+	meta := localMeta{ETag: etag, ContentType: upload.ContentType, Metadata: upload.Metadata, PartSizes: sizes}
+	if err := l.commit(rel, out.Name(), meta); err != nil {
+		return ObjectInfo{}, err
+	}
+	if err := os.RemoveAll(l.uploadDir(uploadID)); err != nil {
+		return ObjectInfo{}, err
+	}
+	return l.HeadObject(ctx, key, 0)
+}
+
+func (l *LocalStorage) AbortMultipartUpload(ctx context.Context, key, uploadID string) error {
+	if _, err := l.upload(key, uploadID); err != nil {
+		return err
+	}
+	return os.RemoveAll(l.uploadDir(uploadID))
+}
+
+// PresignGetObject devolve uma URL file://; não há assinatura nem expiração no disco local
+func (l *LocalStorage) PresignGetObject(ctx context.Context, key string, expires time.Duration) (string, error) {
+	if _, err := l.HeadObject(ctx, key, 0); err != nil {
+		return "", err
+	}
+	rel, _ := localKey(key)
+	return "file://" + filepath.ToSlash(l.objectPath(rel)), nil
+}
+
+// stat junta o arquivo e o metadata. Arquivos colocados à mão no diretório (sem metadata)
+// ganham o ETag calculado na hora.
+func (l *LocalStorage) stat(rel string) (ObjectInfo, localMeta, error) {
+	var meta localMeta
+	fileInfo, err := os.Stat(l.objectPath(rel))
+	if errors.Is(err, os.ErrNotExist) || (err == nil && !fileInfo.Mode().IsRegular()) {
+		return ObjectInfo{}, meta, fmt.Errorf("%s: %w", rel, ErrNotFound)
+	}
+	if err != nil {
+		return ObjectInfo{}, meta, err
+	}
+
+	data, err := os.ReadFile(l.metaPath(rel))
+	switch {
+	case err == nil:
+		if err := json.Unmarshal(data, &meta); err != nil {
+			return ObjectInfo{}, meta, err
+		}
+	case errors.Is(err, os.ErrNotExist):
+		if meta.ETag, _, err = fileETag(l.objectPath(rel)); err != nil {
+			return ObjectInfo{}, meta, err
+		}
+	default:
+		return ObjectInfo{}, meta, err
+	}
+
+	return ObjectInfo{
+		Key:          rel,
+		Size:         fileInfo.Size(),
+		ETag:         meta.ETag,
+		LastModified: fileInfo.ModTime().UTC(),
+		ContentType:  meta.ContentType,
+		Metadata:     meta.Metadata,
+	}, meta, nil
+}
+
+// commit coloca o arquivo temporário no lugar do objeto, junto com o metadata
+func (l *LocalStorage) commit(rel, tmp string, meta localMeta) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	target := l.objectPath(rel)
+	if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, target); err != nil {
+		return err
+	}
+	return saveJSON(l.metaPath(rel), meta)
+}
+
+// writeTemp grava body num temporário calculando o MD5 no caminho
+func (l *LocalStorage) writeTemp(body io.Reader) (string, string, error) {
+	file, err := os.CreateTemp(filepath.Join(l.root, localStateDir, "tmp"), "put-*")
+	if err != nil {
+		return "", "", err
+	}
+	hash := md5.New()
+	_, err = io.Copy(io.MultiWriter(file, hash), body)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(file.Name())
+		return "", "", err
+	}
+	return file.Name(), hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// removeEmptyDirs apaga os diretórios que ficaram vazios, subindo até a raiz: no S3
+// não existem pastas, e uma pasta vazia impediria gravar um objeto com o mesmo nome
+func (l *LocalStorage) removeEmptyDirs(dir string) {
+	for dir != l.root && strings.HasPrefix(dir, l.root) {
+		if os.Remove(dir) != nil {
+			return
+		}
+		dir = filepath.Dir(dir)
+	}
+}
+
+func (l *LocalStorage) upload(key, uploadID string) (*localUpload, error) {
+	var upload localUpload
+	data, err := os.ReadFile(filepath.Join(l.uploadDir(uploadID), "upload.json"))
+	if err == nil {
+		err = json.Unmarshal(data, &upload)
+	}
+	if err != nil || upload.Key != key || strings.ContainsAny(uploadID, `/\.`) {
+		return nil, fmt.Errorf("%s: %w", uploadID, ErrNoSuchUpload)
+	}
+	return &upload, nil
+}
+
+func (l *LocalStorage) objectPath(rel string) string {
+	return filepath.Join(l.root, filepath.FromSlash(rel))
+}
+
+func (l *LocalStorage) metaPath(rel string) string {
+	return filepath.Join(l.root, localStateDir, "meta", filepath.FromSlash(rel)+".json")
+}
+
+func (l *LocalStorage) uploadDir(uploadID string) string {
+	return filepath.Join(l.root, localStateDir, "uploads", uploadID)
+}
+
+// localKey transforma a key num caminho relativo seguro: nada de "..", segmentos vazios
+// ou acesso ao diretório de estado
+func localKey(key string) (string, error) {
+	rel := strings.TrimPrefix(key, "/")
+	if rel == "" || path.Clean(rel) != rel || rel == ".." || strings.HasPrefix(rel, "../") ||
+		rel == localStateDir || strings.HasPrefix(rel, localStateDir+"/") {
+		return "", fmt.Errorf("%q: %w", key, ErrInvalidKey)
+	}
+	return rel, nil
+}
+
+// appendPart copia uma parte para out conferindo o ETag informado no Complete
+func appendPart(out io.Writer, partPath, etag string) (int64, error) {
+	file, err := os.Open(partPath)
+	if errors.Is(err, os.ErrNotExist) {
+		return 0, ErrInvalidPart
+	}
+	if err != nil {
+		return 0, err
+	}
+	defer file.Close()
+
+	hash := md5.New()
+	size, err := io.Copy(io.MultiWriter(out, hash), file)
+	if err != nil {
+		return 0, err
+	}
+	if hex.EncodeToString(hash.Sum(nil)) != etag {
+		return 0, ErrInvalidPart
+	}
+	return size, nil
+}
+
+func fileETag(filePath string) (string, int64, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return "", 0, err
+	}
+	defer file.Close()
+	hash := md5.New()
+	size, err := io.Copy(hash, file)
+	if err != nil {
+		return "", 0, err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), size, nil
+}
@@ -0,0 +1,264 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ==============================================================================
+// BACKEND EM MEMÓRIA
+// ==============================================================================
+
+// MemoryStorage guarda tudo num map: útil em testes e benchmarks, sem serviço externo
+type MemoryStorage struct {
+	mu      sync.Mutex
+	objects map[string]*memoryObject
+	uploads map[string]*memoryUpload
+	nextID  int
+}
+
+type memoryObject struct {
+	info      ObjectInfo
+	data      []byte
+	partSizes []int64 // Só para objetos multipart
+}
+
+type memoryUpload struct {
+	key   string
+	opts  PutOptions
+	parts map[int64][]byte
+}
+
+func NewMemoryStorage() *MemoryStorage {
+	return &MemoryStorage{
+		objects: make(map[string]*memoryObject),
+		uploads: make(map[string]*memoryUpload),
+	}
+}
+
+func (m *MemoryStorage) Location() string {
+	return fmt.Sprintf("memory://%p", m)
+}
+
+func (m *MemoryStorage) PutObject(ctx context.Context, key string, body io.ReadSeeker, opts PutOptions) (ObjectInfo, error) {
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return ObjectInfo{}, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	obj := &memoryObject{info: newObjectInfo(key, data, partETag(data), opts), data: data}
+	m.objects[key] = obj
+	return obj.info, nil
+}
+
+func (m *MemoryStorage) GetObject(ctx context.Context, key string, opts GetOptions) (io.ReadCloser, ObjectInfo, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	obj, ok := m.objects[key]
+	if !ok {
+		return nil, ObjectInfo{}, fmt.Errorf("%s: %w", key, ErrNotFound)
+	}
+	if opts.IfMatch != "" && opts.IfMatch != obj.info.ETag {
+		return nil, ObjectInfo{}, fmt.Errorf("%s: %w", key, ErrPreconditionFailed)
+	}
+	data, err := byteRange(obj.data, opts)
+	if err != nil {
+		return nil, ObjectInfo{}, err
+	}
+
+	// Os dados nunca são alterados depois de gravados: dá para ler sem cópia
+	info := obj.info
+	info.Size = int64(len(data))
+	return io.NopCloser(bytes.NewReader(data)), info, nil
+}
+
+func (m *MemoryStorage) HeadObject(ctx context.Context, key string, partNumber int64) (ObjectInfo, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	obj, ok := m.objects[key]
+	if !ok {
+		return ObjectInfo{}, fmt.Errorf("%s: %w", key, ErrNotFound)
+	}
+	return partInfo(obj.info, obj.partSizes, partNumber)
+}
+
+func (m *MemoryStorage) ListObjects(ctx context.Context, prefix string) ([]ObjectInfo, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var objects []ObjectInfo
+	for _, key := range slices.Sorted(maps.Keys(m.objects)) {
+		if strings.HasPrefix(key, prefix) {
+			objects = append(objects, m.objects[key].info)
+		}
+	}
+	return objects, nil
+}
+
+// DeleteObjects segue o S3: apagar uma key que não existe não é erro
+func (m *MemoryStorage) DeleteObjects(ctx context.Context, keys []string) (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, key := range keys {
+		delete(m.objects, key)
+	}
+	return len(keys), nil
+}
+
+func (m *MemoryStorage) CreateMultipartUpload(ctx context.Context, key string, opts PutOptions) (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.nextID++
+	id := fmt.Sprintf("memory-upload-%d", m.nextID)
+	m.uploads[id] = &memoryUpload{key: key, opts: opts, parts: make(map[int64][]byte)}
+	return id, nil
+}
+
+func (m *MemoryStorage) UploadPart(ctx context.Context, key, uploadID string, partNumber int64, body io.ReadSeeker) (string, error) {
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return "", err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	upload, err := m.upload(key, uploadID)
+	if err != nil {
+		return "", err
+	}
+	upload.parts[partNumber] = data
+	return partETag(data), nil
+}
+
+func (m *MemoryStorage) ListParts(ctx context.Context, key, uploadID string) ([]PartInfo, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	upload, err := m.upload(key, uploadID)
+	if err != nil {
+		return nil, err
+	}
+	var parts []PartInfo
+	for _, n := range slices.Sorted(maps.Keys(upload.parts)) {
+		data := upload.parts[n]
+		parts = append(parts, PartInfo{PartNumber: n, ETag: partETag(data), Size: int64(len(data))})
+	}
+	return parts, nil
+}
+
+func (m *MemoryStorage) CompleteMultipartUpload(ctx context.Context, key, uploadID string, parts []PartInfo) (ObjectInfo, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	upload, err := m.upload(key, uploadID)
+	if err != nil {
+		return ObjectInfo{}, err
+	}
+
+	var data []byte
+	var sizes []int64
+	for _, part := range parts {
+		chunk, ok := upload.parts[part.PartNumber]
+		if !ok || partETag(chunk) != part.ETag {
+			return ObjectInfo{}, fmt.Errorf("part %d of %s: %w", part.PartNumber, key, ErrInvalidPart)
+		}
+		data = append(data, chunk...)
+		sizes = append(sizes, int64(len(chunk)))
+	}
+
+	etag, err := combinedETag(parts)
+	if err != nil {
+		return ObjectInfo{}, err
+	}
+	obj := &memoryObject{info: newObjectInfo(key, data, etag, upload.opts), data: data, partSizes: sizes}
+	m.objects[key] = obj
+	delete(m.uploads, uploadID)
+	return obj.info, nil
+}
+
+func (m *MemoryStorage) AbortMultipartUpload(ctx context.Context, key, uploadID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, err := m.upload(key, uploadID); err != nil {
+		return err
+	}
+	delete(m.uploads, uploadID)
+	return nil
+}
+
+// PresignGetObject não existe em memória: não há servidor para atender a URL
+func (m *MemoryStorage) PresignGetObject(ctx context.Context, key string, expires time.Duration) (string, error) {
+	return "", fmt.Errorf("presign %s: %w", key, errors.ErrUnsupported)
+}
+
+func (m *MemoryStorage) upload(key, uploadID string) (*memoryUpload, error) {
+	upload, ok := m.uploads[uploadID]
+	if !ok || upload.key != key {
+		return nil, fmt.Errorf("%s: %w", uploadID, ErrNoSuchUpload)
+	}
+	return upload, nil
+}
+
+// ==============================================================================
+// REGRAS DO S3 COMPARTILHADAS PELOS BACKENDS LOCAIS
+// ==============================================================================
+
+func newObjectInfo(key string, data []byte, etag string, opts PutOptions) ObjectInfo {
+	return ObjectInfo{
+		Key:          key,
+		Size:         int64(len(data)),
+		ETag:         etag,
+		LastModified: time.Now().UTC(),
+		ContentType:  opts.ContentType,
+		Metadata:     maps.Clone(opts.Metadata),
+	}
+}
+
+// combinedETag é o ETag de um objeto multipart: MD5 da concatenação dos MD5 das partes + "-N"
+func combinedETag(parts []PartInfo) (string, error) {
+	digests := make([]byte, 0, len(parts)*16)
+	for _, part := range parts {
+		sum, err := hex.DecodeString(part.ETag)
+		if err != nil {
+			return "", fmt.Errorf("part %d: %w", part.PartNumber, ErrInvalidPart)
+		}
+		digests = append(digests, sum...)
+	}
+	return fmt.Sprintf("%s-%d", partETag(digests), len(parts)), nil
+}
+
+// partInfo devolve o objeto inteiro ou, para partNumber > 0 num objeto multipart, o tamanho da parte
+func partInfo(info ObjectInfo, partSizes []int64, partNumber int64) (ObjectInfo, error) {
+	if partNumber <= 0 || len(partSizes) == 0 {
+		return info, nil
+	}
+	if partNumber > int64(len(partSizes)) {
+		return ObjectInfo{}, fmt.Errorf("part %d of %s: %w", partNumber, info.Key, ErrInvalidPart)
+	}
+	info.Size = partSizes[partNumber-1]
+	info.PartsCount = len(partSizes)
+	return info, nil
+}
+
+// byteRange aplica Offset/Length de GetOptions como o S3 aplica o header Range
+func byteRange(data []byte, opts GetOptions) ([]byte, error) {
+	if opts.Offset == 0 && opts.Length == 0 {
+		return data, nil
+	}
+	if opts.Offset < 0 || opts.Offset >= int64(len(data)) {
+		return nil, fmt.Errorf("range starting at %d is outside a %d-byte object", opts.Offset, len(data))
+	}
+	end := int64(len(data))
+	if opts.Length > 0 {
+		end = min(opts.Offset+opts.Length, end)
+	}
+	return data[opts.Offset:end], nil
+}
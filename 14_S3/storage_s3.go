@@ -0,0 +1,299 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/s3"
+)
+
+// ==============================================================================
+// BACKEND S3 (AWS OU MINIO)
+// ==============================================================================
+
+// S3Storage guarda os objetos num bucket do S3. O retry fica com os workers:
+// o cliente deve ser criado com MaxRetries 0, como em setupS3Client.
+type S3Storage struct {
+	client *s3.S3
+	bucket string
+}
+
+func NewS3Storage(client *s3.S3, bucket string) *S3Storage {
+	return &S3Storage{client: client, bucket: bucket}
+}
+
+func (s *S3Storage) Location() string {
+	return "s3://" + s.bucket
+}
+
+func (s *S3Storage) PutObject(ctx context.Context, key string, body io.ReadSeeker, opts PutOptions) (ObjectInfo, error) {
+	resp, err := s.client.PutObjectWithContext(ctx, &s3.PutObjectInput{
+		Bucket:      aws.String(s.bucket),
+		Key:         aws.String(key),
+		Body:        body,
+		ContentType: contentType(opts.ContentType),
+		Metadata:    aws.StringMap(opts.Metadata),
+	}, sendPhase(body)...)
+	if err != nil {
+		return ObjectInfo{}, s3Error(err)
+	}
+	return ObjectInfo{Key: key, ETag: normalizeETag(aws.StringValue(resp.ETag))}, nil
+}
+
+func (s *S3Storage) GetObject(ctx context.Context, key string, opts GetOptions) (io.ReadCloser, ObjectInfo, error) {
+	input := &s3.GetObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(key),
+	}
+	switch {
+	case opts.Length > 0:
+		input.Range = aws.String(fmt.Sprintf("bytes=%d-%d", opts.Offset, opts.Offset+opts.Length-1))
+	case opts.Offset > 0:
+		input.Range = aws.String(fmt.Sprintf("bytes=%d-", opts.Offset))
+	}
+	if opts.IfMatch != "" {
+		input.IfMatch = aws.String(`"` + opts.IfMatch + `"`)
+	}
+
+	resp, err := s.client.GetObjectWithContext(ctx, input)
+	if err != nil {
+		return nil, ObjectInfo{}, s3Error(err)
+	}
+	return resp.Body, ObjectInfo{
+		Key:          key,
+		Size:         aws.Int64Value(resp.ContentLength),
+		ETag:         normalizeETag(aws.StringValue(resp.ETag)),
+		LastModified: aws.TimeValue(resp.LastModified),
+		ContentType:  aws.StringValue(resp.ContentType),
+		Metadata:     aws.StringValueMap(resp.Metadata),
+	}, nil
+}
+
+func (s *S3Storage) HeadObject(ctx context.Context, key string, partNumber int64) (ObjectInfo, error) {
+	input := &s3.HeadObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(key),
+	}
+	if partNumber > 0 {
+		input.PartNumber = aws.Int64(partNumber)
+	}
+
+	head, err := s.client.HeadObjectWithContext(ctx, input)
+	if err != nil {
+		return ObjectInfo{}, s3Error(err)
+	}
+	encryption := aws.StringValue(head.ServerSideEncryption)
+	if head.SSECustomerAlgorithm != nil {
+		encryption = "SSE-C"
+	}
+	return ObjectInfo{
+		Key:          key,
+		Size:         aws.Int64Value(head.ContentLength),
+		ETag:         normalizeETag(aws.StringValue(head.ETag)),
+		LastModified: aws.TimeValue(head.LastModified),
+		ContentType:  aws.StringValue(head.ContentType),
+		Metadata:     aws.StringValueMap(head.Metadata),
+		PartsCount:   int(aws.Int64Value(head.PartsCount)),
+		Encryption:   encryption,
+	}, nil
+}
+
+// ListObjects percorre todas as páginas do ListObjectsV2 sob o prefixo
+func (s *S3Storage) ListObjects(ctx context.Context, prefix string) ([]ObjectInfo, error) {
+	var objects []ObjectInfo
+	input := &s3.ListObjectsV2Input{
+		Bucket: aws.String(s.bucket),
+		Prefix: aws.String(prefix),
+	}
+
+	for {
+		page, err := s.client.ListObjectsV2WithContext(ctx, input)
+		if err != nil {
+			return nil, s3Error(err)
+		}
+		for _, obj := range page.Contents {
+			objects = append(objects, ObjectInfo{
+				Key:          aws.StringValue(obj.Key),
+				Size:         aws.Int64Value(obj.Size),
+				ETag:         normalizeETag(aws.StringValue(obj.ETag)),
+				LastModified: aws.TimeValue(obj.LastModified),
+			})
+		}
+		if !aws.BoolValue(page.IsTruncated) {
+			return objects, nil
+		}
+		input.ContinuationToken = page.NextContinuationToken
+	}
+}
+
+// DeleteObjects remove até deleteBatchSize keys numa chamada
+func (s *S3Storage) DeleteObjects(ctx context.Context, keys []string) (int, error) {
+	objects := make([]*s3.ObjectIdentifier, len(keys))
+	for i, key := range keys {
+		objects[i] = &s3.ObjectIdentifier{Key: aws.String(key)}
+	}
+
+	resp, err := s.client.DeleteObjectsWithContext(ctx, &s3.DeleteObjectsInput{
+		Bucket: aws.String(s.bucket),
+		Delete: &s3.Delete{Objects: objects, Quiet: aws.Bool(false)},
+	})
+	if err != nil {
+		return 0, s3Error(err)
+	}
+	if len(resp.Errors) > 0 {
+		first := resp.Errors[0]
+		return len(resp.Deleted), fmt.Errorf("failed to delete %d objects, first %s: %s",
+			len(resp.Errors), aws.StringValue(first.Key), aws.StringValue(first.Message))
+	}
+	return len(resp.Deleted), nil
+}
+
+func (s *S3Storage) CreateMultipartUpload(ctx context.Context, key string, opts PutOptions) (string, error) {
+	resp, err := s.client.CreateMultipartUploadWithContext(ctx, &s3.CreateMultipartUploadInput{
+		Bucket:      aws.String(s.bucket),
+		Key:         aws.String(key),
+		ContentType: contentType(opts.ContentType),
+		Metadata:    aws.StringMap(opts.Metadata),
+	})
+	if err != nil {
+		return "", s3Error(err)
+	}
+	return aws.StringValue(resp.UploadId), nil
+}
+
+func (s *S3Storage) UploadPart(ctx context.Context, key, uploadID string, partNumber int64, body io.ReadSeeker) (string, error) {
+	resp, err := s.client.UploadPartWithContext(ctx, &s3.UploadPartInput{
+		Bucket:     aws.String(s.bucket),
+		Key:        aws.String(key),
+		PartNumber: aws.Int64(partNumber),
+		UploadId:   aws.String(uploadID),
+		Body:       body,
+	}, sendPhase(body)...)
+	if err != nil {
+		return "", s3Error(err)
+	}
+	return normalizeETag(aws.StringValue(resp.ETag)), nil
+}
+
+// ListParts percorre todas as páginas do ListParts de um upload
+func (s *S3Storage) ListParts(ctx context.Context, key, uploadID string) ([]PartInfo, error) {
+	var parts []PartInfo
+	input := &s3.ListPartsInput{
+		Bucket:   aws.String(s.bucket),
+		Key:      aws.String(key),
+		UploadId: aws.String(uploadID),
+	}
+
+	for {
+		page, err := s.client.ListPartsWithContext(ctx, input)
+		if err != nil {
+			return nil, s3Error(err)
+		}
+		for _, part := range page.Parts {
+			parts = append(parts, PartInfo{
+				PartNumber: aws.Int64Value(part.PartNumber),
+				ETag:       normalizeETag(aws.StringValue(part.ETag)),
+				Size:       aws.Int64Value(part.Size),
+			})
+		}
+		if !aws.BoolValue(page.IsTruncated) {
+			return parts, nil
+		}
+		input.PartNumberMarker = page.NextPartNumberMarker
+	}
+}
+
+func (s *S3Storage) CompleteMultipartUpload(ctx context.Context, key, uploadID string, parts []PartInfo) (ObjectInfo, error) {
+	completed := make([]*s3.CompletedPart, len(parts))
+	for i, part := range parts {
+		completed[i] = &s3.CompletedPart{
+			ETag:       aws.String(`"` + part.ETag + `"`),
+			PartNumber: aws.Int64(part.PartNumber),
+		}
+	}
+
+	resp, err := s.client.CompleteMultipartUploadWithContext(ctx, &s3.CompleteMultipartUploadInput{
+		Bucket:          aws.String(s.bucket),
+		Key:             aws.String(key),
+		UploadId:        aws.String(uploadID),
+		MultipartUpload: &s3.CompletedMultipartUpload{Parts: completed},
+	})
+	if err != nil {
+		return ObjectInfo{}, s3Error(err)
+	}
+	return ObjectInfo{Key: key, ETag: normalizeETag(aws.StringValue(resp.ETag))}, nil
+}
+
+func (s *S3Storage) AbortMultipartUpload(ctx context.Context, key, uploadID string) error {
+	_, err := s.client.AbortMultipartUploadWithContext(ctx, &s3.AbortMultipartUploadInput{
+		Bucket:   aws.String(s.bucket),
+		Key:      aws.String(key),
+		UploadId: aws.String(uploadID),
+	})
+	return s3Error(err)
+}
+
+func (s *S3Storage) PresignGetObject(ctx context.Context, key string, expires time.Duration) (string, error) {
+	req, _ := s.client.GetObjectRequest(&s3.GetObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(key),
+	})
+	req.SetContext(ctx)
+	return req.Presign(expires)
+}
+
+// s3Error acrescenta o erro comum do Storage sem perder o do SDK (usado por isRetryable)
+func s3Error(err error) error {
+	if err == nil {
+		return nil
+	}
+	var awsErr awserr.Error
+	if errors.As(err, &awsErr) {
+		switch awsErr.Code() {
+		case s3.ErrCodeNoSuchUpload:
+			return fmt.Errorf("%w: %w", ErrNoSuchUpload, err)
+		case s3.ErrCodeNoSuchKey, "NotFound":
+			return fmt.Errorf("%w: %w", ErrNotFound, err)
+		case "PreconditionFailed":
+			return fmt.Errorf("%w: %w", ErrPreconditionFailed, err)
+		case "InvalidPart", "InvalidPartOrder":
+			return fmt.Errorf("%w: %w", ErrInvalidPart, err)
+		}
+	}
+	var reqErr awserr.RequestFailure
+	if errors.As(err, &reqErr) {
+		switch reqErr.StatusCode() {
+		case 404:
+			return fmt.Errorf("%w: %w", ErrNotFound, err)
+		case 412:
+			return fmt.Errorf("%w: %w", ErrPreconditionFailed, err)
+		}
+	}
+	return err
+}
+
+// sendPhase adia a contagem de um transferReader até o envio: antes disso o SDK lê o
+// corpo inteiro para calcular MD5/SHA256, e essa leitura não é tráfego de rede
+func sendPhase(body io.Reader) []request.Option {
+	t, ok := body.(*transferReader)
+	if !ok {
+		return nil
+	}
+	t.sending = false
+	return []request.Option{func(r *request.Request) {
+		r.Handlers.Send.PushFront(func(*request.Request) { t.sending = true })
+	}}
+}
+
+func contentType(value string) *string {
+	if value == "" {
+		return nil
+	}
+	return aws.String(value)
+}
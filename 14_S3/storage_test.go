@@ -0,0 +1,361 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+// testBackends roda o mesmo teste em todos os backends; o S3 usa o fake em memória
+var testBackends = []struct {
+	name string
+	open func(t *testing.T) Storage
+}{
+	{"memory", func(t *testing.T) Storage { return NewMemoryStorage() }},
+	{"local", func(t *testing.T) Storage {
+		store, err := NewLocalStorage(t.TempDir())
+		if err != nil {
+			t.Fatal(err)
+		}
+		return store
+	}},
+	{"s3", func(t *testing.T) Storage {
+		_, client := newFakeS3(t)
+		return NewS3Storage(client, testBucket)
+	}},
+}
+
+func forEachBackend(t *testing.T, test func(t *testing.T, store Storage)) {
+	for _, backend := range testBackends {
+		t.Run(backend.name, func(t *testing.T) { test(t, backend.open(t)) })
+	}
+}
+
+func readObject(t *testing.T, store Storage, key string, opts GetOptions) ([]byte, ObjectInfo) {
+	t.Helper()
+	body, info, err := store.GetObject(context.Background(), key, opts)
+	if err != nil {
+		t.Fatalf("get %s: %v", key, err)
+	}
+	defer body.Close()
+	data, err := io.ReadAll(body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data, info
+}
+
+func TestStoragePutGetHead(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, store Storage) {
+		ctx := context.Background()
+		data := []byte("0123456789abcdefghij")
+
+		put, err := store.PutObject(ctx, "docs/file.txt", bytes.NewReader(data), PutOptions{
+			ContentType: "text/plain",
+			Metadata:    map[string]string{"Origin": "test"},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if put.ETag != partETag(data) {
+			t.Fatalf("expected ETag %s, got %s", partETag(data), put.ETag)
+		}
+
+		got, info := readObject(t, store, "docs/file.txt", GetOptions{})
+		if !bytes.Equal(got, data) || info.ETag != put.ETag || info.Metadata["Origin"] != "test" {
+			t.Fatalf("unexpected object %q %+v", got, info)
+		}
+		if got, _ := readObject(t, store, "docs/file.txt", GetOptions{Offset: 5, Length: 4}); string(got) != "5678" {
+			t.Fatalf("expected range 5678, got %q", got)
+		}
+		if got, _ := readObject(t, store, "docs/file.txt", GetOptions{Offset: 16}); string(got) != "ghij" {
+			t.Fatalf("expected tail ghij, got %q", got)
+		}
+		if got, _ := readObject(t, store, "docs/file.txt", GetOptions{IfMatch: put.ETag}); !bytes.Equal(got, data) {
+			t.Fatal("matching If-Match should return the object")
+		}
+		if _, _, err := store.GetObject(ctx, "docs/file.txt", GetOptions{IfMatch: partETag([]byte("other"))}); !errors.Is(err, ErrPreconditionFailed) {
+			t.Fatalf("expected ErrPreconditionFailed, got %v", err)
+		}
+
+		head, err := store.HeadObject(ctx, "docs/file.txt", 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if head.Size != int64(len(data)) || head.ETag != put.ETag || head.Metadata["Origin"] != "test" || head.LastModified.IsZero() {
+			t.Fatalf("unexpected head %+v", head)
+		}
+
+		if _, err := store.HeadObject(ctx, "docs/missing.txt", 0); !errors.Is(err, ErrNotFound) {
+			t.Fatalf("expected ErrNotFound from head, got %v", err)
+		}
+		if _, _, err := store.GetObject(ctx, "docs/missing.txt", GetOptions{}); !errors.Is(err, ErrNotFound) {
+			t.Fatalf("expected ErrNotFound from get, got %v", err)
+		}
+	})
+}
+
+func TestStorageListAndDelete(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, store Storage) {
+		ctx := context.Background()
+		for _, key := range []string{"a/2.txt", "a/1.txt", "a-b.txt", "b/1.txt"} {
+			if _, err := store.PutObject(ctx, key, strings.NewReader(key), PutOptions{}); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		list := func(prefix string) []string {
+			t.Helper()
+			objects, err := store.ListObjects(ctx, prefix)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var keys []string
+			for _, obj := range objects {
+				if obj.Size != int64(len(obj.Key)) || obj.ETag != partETag([]byte(obj.Key)) {
+					t.Fatalf("unexpected listing entry %+v", obj)
+				}
+				keys = append(keys, obj.Key)
+			}
+			return keys
+		}
+
+		if keys := list(""); !slices.Equal(keys, []string{"a-b.txt", "a/1.txt", "a/2.txt", "b/1.txt"}) {
+			t.Fatalf("unexpected listing %v", keys)
+		}
+		if keys := list("a/"); !slices.Equal(keys, []string{"a/1.txt", "a/2.txt"}) {
+			t.Fatalf("unexpected listing under a/: %v", keys)
+		}
+
+		// Como no S3, uma key inexistente conta como apagada
+		deleted, err := store.DeleteObjects(ctx, []string{"a/1.txt", "a/2.txt", "a/missing.txt"})
+		if err != nil || deleted != 3 {
+			t.Fatalf("expected 3 deleted, got %d, %v", deleted, err)
+		}
+		if keys := list(""); !slices.Equal(keys, []string{"a-b.txt", "b/1.txt"}) {
+			t.Fatalf("unexpected listing after delete %v", keys)
+		}
+
+		// Sem objetos sob "a/", a key "a" pode virar um objeto
+		if _, err := store.PutObject(ctx, "a", strings.NewReader("a"), PutOptions{}); err != nil {
+			t.Fatal(err)
+		}
+	})
+}
+
+func TestStorageMultipart(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, store Storage) {
+		ctx := context.Background()
+		data := bytes.Repeat([]byte("0123456789"), 250) // Partes de 1000, 1000 e 500 bytes
+		chunks := [][]byte{data[:1000], data[1000:2000], data[2000:]}
+
+		uploadID, err := store.CreateMultipartUpload(ctx, "big.bin", PutOptions{Metadata: map[string]string{"Origin": "multipart"}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		// Fora de ordem: quem define a ordem é o Complete
+		var parts []PartInfo
+		for _, n := range []int64{3, 1, 2} {
+			etag, err := store.UploadPart(ctx, "big.bin", uploadID, n, bytes.NewReader(chunks[n-1]))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if etag != partETag(chunks[n-1]) {
+				t.Fatalf("part %d: expected ETag %s, got %s", n, partETag(chunks[n-1]), etag)
+			}
+		}
+
+		listed, err := store.ListParts(ctx, "big.bin", uploadID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i, part := range listed {
+			if part.PartNumber != int64(i+1) || part.Size != int64(len(chunks[i])) || part.ETag != partETag(chunks[i]) {
+				t.Fatalf("unexpected part %+v", part)
+			}
+			parts = append(parts, PartInfo{PartNumber: part.PartNumber, ETag: part.ETag})
+		}
+		if len(parts) != 3 {
+			t.Fatalf("expected 3 parts, got %d", len(parts))
+		}
+
+		if _, err := store.CompleteMultipartUpload(ctx, "big.bin", uploadID, []PartInfo{{PartNumber: 1, ETag: partETag([]byte("x"))}}); !errors.Is(err, ErrInvalidPart) {
+			t.Fatalf("expected ErrInvalidPart for a wrong ETag, got %v", err)
+		}
+		if _, err := store.CompleteMultipartUpload(ctx, "big.bin", uploadID, parts); err != nil {
+			t.Fatal(err)
+		}
+
+		got, info := readObject(t, store, "big.bin", GetOptions{})
+		expected, _ := multipartETag(bytes.NewReader(data), 1000)
+		if !bytes.Equal(got, data) || info.ETag != expected || info.Metadata["Origin"] != "multipart" {
+			t.Fatalf("unexpected multipart object (%d bytes) %+v, expected ETag %s", len(got), info, expected)
+		}
+		head, err := store.HeadObject(ctx, "big.bin", 3)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if head.Size != 500 || head.PartsCount != 3 {
+			t.Fatalf("expected part 3 with 500 bytes of 3 parts, got %+v", head)
+		}
+
+		if _, err := store.ListParts(ctx, "big.bin", uploadID); !errors.Is(err, ErrNoSuchUpload) {
+			t.Fatalf("completed upload should be gone, got %v", err)
+		}
+
+		aborted, err := store.CreateMultipartUpload(ctx, "other.bin", PutOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := store.AbortMultipartUpload(ctx, "other.bin", aborted); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := store.UploadPart(ctx, "other.bin", aborted, 1, bytes.NewReader(data)); !errors.Is(err, ErrNoSuchUpload) {
+			t.Fatalf("expected ErrNoSuchUpload after abort, got %v", err)
+		}
+	})
+}
+
+func TestStoragePresign(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, store Storage) {
+		ctx := context.Background()
+		if _, err := store.PutObject(ctx, "shared.txt", strings.NewReader("hi"), PutOptions{}); err != nil {
+			t.Fatal(err)
+		}
+		url, err := store.PresignGetObject(ctx, "shared.txt", 15*time.Minute)
+		if errors.Is(err, errors.ErrUnsupported) {
+			return // Memória: não há URL para oferecer
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(url, "shared.txt") {
+			t.Fatalf("presigned URL should point to the object, got %s", url)
+		}
+	})
+}
+
+func TestLocalStorageKeysAndForeignFiles(t *testing.T) {
+	dir := t.TempDir()
+	store, err := NewLocalStorage(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+
+	for _, key := range []string{"", "../escape", "a/../../escape", "a//b", "a/./b", ".storage/meta/x", "dir/"} {
+		if _, err := store.PutObject(ctx, key, strings.NewReader("x"), PutOptions{}); !errors.Is(err, ErrInvalidKey) {
+			t.Fatalf("key %q: expected ErrInvalidKey, got %v", key, err)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(filepath.Dir(dir), "escape")); err == nil {
+		t.Fatal("object escaped the storage directory")
+	}
+
+	// Arquivo colocado direto no diretório, sem metadata: aparece com o ETag calculado
+	if err := os.MkdirAll(filepath.Join(dir, "manual"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "manual", "file.txt"), []byte("by hand"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	objects, err := store.ListObjects(ctx, "/manual/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(objects) != 1 || objects[0].Key != "manual/file.txt" || objects[0].ETag != partETag([]byte("by hand")) {
+		t.Fatalf("unexpected listing %+v", objects)
+	}
+}
+
+func TestNewStorageFromConfig(t *testing.T) {
+	t.Setenv("STORAGE_BACKEND", BackendLocal)
+	t.Setenv("STORAGE_DIR", t.TempDir())
+
+	cfg := StorageConfigFromEnv("my-bucket")
+	store, err := NewStorage(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "file://" + filepath.ToSlash(filepath.Join(cfg.Dir, "my-bucket")); store.Location() != want {
+		t.Fatalf("expected location %s, got %s", want, store.Location())
+	}
+
+	if store, err := NewStorage(StorageConfig{Backend: BackendMemory}); err != nil || !strings.HasPrefix(store.Location(), "memory://") {
+		t.Fatalf("expected a memory backend, got %v, %v", store, err)
+	}
+	if _, err := NewStorage(StorageConfig{Backend: "ftp"}); err == nil {
+		t.Fatal("unknown backends should fail")
+	}
+}
+
+// Os workers não dependem do S3: sync e download em partes funcionam em qualquer backend
+func TestWorkersOnEveryBackend(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, store Storage) {
+		ctx := context.Background()
+		dir := t.TempDir()
+		files := map[string][]byte{
+			"small.txt":      []byte("pequeno"),
+			"nested/big.bin": bytes.Repeat([]byte("multipart "), 500),
+		}
+		for rel, data := range files {
+			if err := os.MkdirAll(filepath.Dir(filepath.Join(dir, rel)), 0o755); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(filepath.Join(dir, rel), data, 0o644); err != nil {
+				t.Fatal(err)
+			}
+			// O S3 guarda a data em segundos: um arquivo de agora pareceria mais novo que o objeto
+			past := time.Now().Add(-time.Hour)
+			if err := os.Chtimes(filepath.Join(dir, rel), past, past); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		opts := []TransferOption{WithRetryPolicy(fastRetry), WithCheckpointDir(t.TempDir()), WithCompression(CompressionGzip), WithEncryption(newTestKeys(t))}
+		newWorker := func() *UploadWorker {
+			w := NewUploadWorker(store, opts...)
+			w.partSize = 1024
+			return w
+		}
+
+		report, err := newWorker().Sync(ctx, dir, SyncOptions{Prefix: "backup"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(report.Uploads) != 2 {
+			t.Fatalf("expected 2 uploads, got %+v", report.Uploads)
+		}
+		report, err = newWorker().Sync(ctx, dir, SyncOptions{Prefix: "backup"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(report.Uploads) != 0 || report.Unchanged != 2 {
+			t.Fatalf("second sync should change nothing, got %+v", report)
+		}
+
+		downloader := NewDownloadWorker(store, opts...)
+		downloader.partSize = 256
+		for rel, data := range files {
+			dest := filepath.Join(t.TempDir(), "out")
+			result := downloader.Download(ctx, "backup/"+rel, dest)
+			if !result.Success {
+				t.Fatalf("download %s: %v", rel, result.Error)
+			}
+			got, err := os.ReadFile(dest)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(got, data) {
+				t.Fatalf("%s: content mismatch after round trip", rel)
+			}
+		}
+	})
+}
@@ -0,0 +1,385 @@
+package main
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
+
+// ==============================================================================
+// SYNC DE DIRETÓRIO
+// ==============================================================================
+
+// deleteBatchSize é o máximo de keys aceito por uma chamada DeleteObjects
+const deleteBatchSize = 1000
+
+// SyncOptions controla o que o Sync compara e altera
+type SyncOptions struct {
+	Prefix   string   // Prefixo das keys no bucket ("backup/2024" -> "backup/2024/arquivo.txt")
+	Delete   bool     // Remove do bucket o que não existe mais localmente
+	DryRun   bool     // Só monta o plano, sem enviar nem apagar nada
+	Checksum bool     // Compara o MD5 mesmo quando o arquivo local não é mais novo que o remoto
+	Include  []string // Se preenchido, só entram caminhos que casem com algum glob
+	Exclude  []string // Caminhos que casem com algum glob ficam de fora
+}
+
+// SyncAction é uma operação planejada pelo Sync
+type SyncAction struct {
+	Key    string
+	Path   string // Vazio para remoções
+	Reason string
+}
+
+// SyncReport traz o plano e, fora do dry run, o resultado da execução
+type SyncReport struct {
+	Uploads   []SyncAction
+	Deletes   []SyncAction
+	Unchanged int
+	Results   []UploadResult
+	Deleted   int
+}
+
+type remoteObject struct {
+	Size         int64
+	ETag         string
+	LastModified time.Time
+	Encoding     *objectEncoding // Só preenchido quando o worker comprime/cifra (exige HeadObject)
+}
+
+// Sync espelha o diretório dir no storage do worker, sob opts.Prefix.
+// Um arquivo é enviado quando não existe no bucket, quando o tamanho difere ou quando
+// é mais novo que o objeto e o conteúdo (ETag/MD5) mudou.
+func (w *UploadWorker) Sync(ctx context.Context, dir string, opts SyncOptions) (*SyncReport, error) {
+	if err := validateGlobs(slices.Concat(opts.Include, opts.Exclude)); err != nil {
+		return nil, err
+	}
+	prefix := normalizePrefix(opts.Prefix)
+
+	local, err := w.listLocal(dir, opts)
+	if err != nil {
+		return nil, err
+	}
+	remote, err := w.listRemote(ctx, prefix)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &SyncReport{}
+	for rel, filePath := range local {
+		key := prefix + rel
+		obj, exists := remote[key]
+		if exists && w.encodes() {
+			if obj, err = w.remoteEncoding(ctx, key, obj); err != nil {
+				return nil, err
+			}
+		}
+		reason, err := w.needsUpload(filePath, obj, exists, opts.Checksum)
+		if err != nil {
+			return nil, err
+		}
+		if reason == "" {
+			report.Unchanged++
+			continue
+		}
+		report.Uploads = append(report.Uploads, SyncAction{Key: key, Path: filePath, Reason: reason})
+	}
+
+	if opts.Delete {
+		for key := range remote {
+			rel := strings.TrimPrefix(key, prefix)
+			// Objetos fora dos filtros não são do sync: não apaga o que foi excluído
+			if _, ok := local[rel]; !ok && opts.matches(rel) {
+				report.Deletes = append(report.Deletes, SyncAction{Key: key, Reason: "not found locally"})
+			}
+		}
+	}
+
+	sortActions(report.Uploads)
+	sortActions(report.Deletes)
+
+	if opts.DryRun {
+		return report, nil
+	}
+
+	if len(report.Uploads) > 0 {
+		items := make([]UploadItem, len(report.Uploads))
+		for i, action := range report.Uploads {
+			items[i] = UploadItem{Path: action.Path, Key: action.Key}
+		}
+		report.Results = w.UploadItems(ctx, items)
+	}
+
+	deleted, deleteErr := w.deleteKeys(ctx, report.Deletes)
+	report.Deleted = deleted
+
+	failed := 0
+	for _, result := range report.Results {
+		if !result.Success {
+			failed++
+		}
+	}
+	if failed > 0 {
+		deleteErr = errors.Join(fmt.Errorf("%d of %d uploads failed", failed, len(report.Results)), deleteErr)
+	}
+	return report, deleteErr
+}
+
+// listLocal devolve caminho relativo (com "/") -> caminho no disco dos arquivos que passam nos filtros
+func (w *UploadWorker) listLocal(dir string, opts SyncOptions) (map[string]string, error) {
+	// Os checkpoints do próprio worker não devem ir para o bucket
+	checkpointDir, _ := filepath.Abs(w.checkpointDir)
+
+	files := make(map[string]string)
+	err := filepath.WalkDir(dir, func(filePath string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if abs, _ := filepath.Abs(filePath); abs == checkpointDir {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+
+		rel, err := filepath.Rel(dir, filePath)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		if opts.matches(rel) {
+			files[rel] = filePath
+		}
+		return nil
+	})
+	return files, err
+}
+
+// listRemote lista os objetos sob o prefixo, indexados pela key
+func (w *UploadWorker) listRemote(ctx context.Context, prefix string) (map[string]remoteObject, error) {
+	var list []ObjectInfo
+	_, err := w.retry.Do(ctx, func() error {
+		var err error
+		list, err = w.store.ListObjects(ctx, prefix)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	objects := make(map[string]remoteObject, len(list))
+	for _, obj := range list {
+		if strings.HasSuffix(obj.Key, "/") {
+			continue // "pasta" criada pelo console
+		}
+		objects[obj.Key] = remoteObject{
+			Size:         obj.Size,
+			ETag:         obj.ETag,
+			LastModified: obj.LastModified,
+		}
+	}
+	return objects, nil
+}
+
+// remoteEncoding lê o metadata de um objeto para comparar pelo tamanho original quando ele
+// foi comprimido/cifrado. A listagem não traz metadata: custa um HeadObject por arquivo.
+func (w *UploadWorker) remoteEncoding(ctx context.Context, key string, obj remoteObject) (remoteObject, error) {
+	var head ObjectInfo
+	_, err := w.retry.Do(ctx, func() error {
+		var err error
+		head, err = w.store.HeadObject(ctx, key, 0)
+		return err
+	})
+	if err != nil {
+		return obj, err
+	}
+
+	enc, err := parseObjectEncoding(head.Metadata)
+	if err != nil {
+		return obj, fmt.Errorf("%s: %w", key, err)
+	}
+	if enc != nil {
+		obj.Size = enc.PlainSize
+		obj.Encoding = enc
+	}
+	return obj, nil
+}
+
+// needsUpload devolve o motivo do envio, ou "" se o objeto remoto já está igual
+func (w *UploadWorker) needsUpload(filePath string, remote remoteObject, exists, checksum bool) (string, error) {
+	info, err := os.Stat(filePath)
+	if err != nil {
+		return "", err
+	}
+
+	switch {
+	case !exists:
+		return "new file", nil
+	case !w.encodingMatches(remote.Encoding):
+		return "encoding changed", nil
+	case remote.Size != info.Size():
+		return "size changed", nil
+	case !checksum && !info.ModTime().After(remote.LastModified):
+		return "", nil
+	case remote.Encoding != nil:
+		// O ETag é do conteúdo transformado (com chave aleatória): resta a data
+		if info.ModTime().After(remote.LastModified) {
+			return "newer than remote", nil
+		}
+		return "", nil
+	}
+
+	// Mesmo tamanho e arquivo mais novo (ou --checksum): decide pelo conteúdo
+	etag, err := localETag(filePath, info.Size(), w.partSize)
+	if err != nil {
+		return "", err
+	}
+	if etag == remote.ETag {
+		return "", nil
+	}
+	if !comparableETag(remote.ETag, info.Size(), w.partSize) {
+		// Objeto enviado com outro tamanho de parte ou criptografia: o ETag não é o MD5
+		if info.ModTime().After(remote.LastModified) {
+			return "newer than remote", nil
+		}
+		return "", nil
+	}
+	return "content changed", nil
+}
+
+// localETag calcula o ETag que o S3 daria ao arquivo se ele fosse enviado por este worker:
+// MD5 simples até partSize, ou MD5 dos MD5 das partes mais "-N" no multipart
+func localETag(filePath string, size, partSize int64) (string, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	if size <= partSize {
+		hash := md5.New()
+		if _, err := io.Copy(hash, file); err != nil {
+			return "", err
+		}
+		return hex.EncodeToString(hash.Sum(nil)), nil
+	}
+	return multipartETag(file, partSize)
+}
+
+// multipartETag segue a regra do S3 para objetos multipart: MD5 da concatenação dos MD5
+// de cada parte, seguido de "-" e do número de partes
+func multipartETag(r io.Reader, partSize int64) (string, error) {
+	digests := md5.New()
+	parts := 0
+	for {
+		hash := md5.New()
+		n, err := io.CopyN(hash, r, partSize)
+		if n > 0 {
+			digests.Write(hash.Sum(nil))
+			parts++
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return "", err
+		}
+	}
+	return fmt.Sprintf("%s-%d", hex.EncodeToString(digests.Sum(nil)), parts), nil
+}
+
+// comparableETag diz se o ETag remoto tem o formato que localETag produziria
+func comparableETag(etag string, size, partSize int64) bool {
+	_, parts, multipart := strings.Cut(etag, "-")
+	if size <= partSize {
+		return !multipart
+	}
+	return multipart && parts == fmt.Sprint((size+partSize-1)/partSize)
+}
+
+func (w *UploadWorker) deleteKeys(ctx context.Context, actions []SyncAction) (int, error) {
+	deleted := 0
+	for start := 0; start < len(actions); start += deleteBatchSize {
+		batch := actions[start:min(start+deleteBatchSize, len(actions))]
+
+		keys := make([]string, len(batch))
+		for i, action := range batch {
+			keys[i] = action.Key
+		}
+
+		var n int
+		_, err := w.retry.Do(ctx, func() error {
+			var err error
+			n, err = w.store.DeleteObjects(ctx, keys)
+			return err
+		})
+		deleted += n
+		if err != nil {
+			return deleted, err
+		}
+	}
+	return deleted, nil
+}
+
+// matches aplica include e exclude a um caminho relativo.
+// Um glob sem "/" também é testado contra o nome do arquivo ("*.log" pega "a/b/x.log"),
+// e um glob terminado em "/" pega o diretório inteiro ("tmp/").
+func (o SyncOptions) matches(rel string) bool {
+	if len(o.Include) > 0 && !matchAny(o.Include, rel) {
+		return false
+	}
+	return !matchAny(o.Exclude, rel)
+}
+
+func matchAny(patterns []string, rel string) bool {
+	for _, pattern := range patterns {
+		if strings.HasSuffix(pattern, "/") {
+			if strings.HasPrefix(rel, pattern) {
+				return true
+			}
+			continue
+		}
+		if ok, _ := path.Match(pattern, rel); ok {
+			return true
+		}
+		if !strings.Contains(pattern, "/") {
+			if ok, _ := path.Match(pattern, path.Base(rel)); ok {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func validateGlobs(patterns []string) error {
+	for _, pattern := range patterns {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid glob %q: %w", pattern, err)
+		}
+	}
+	return nil
+}
+
+func normalizePrefix(prefix string) string {
+	prefix = strings.Trim(prefix, "/")
+	if prefix == "" {
+		return ""
+	}
+	return prefix + "/"
+}
+
+func sortActions(actions []SyncAction) {
+	slices.SortFunc(actions, func(a, b SyncAction) int { return strings.Compare(a.Key, b.Key) })
+}
@@ -0,0 +1,261 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+// writeTree cria os arquivos em dir com a data de modificação indicada
+func writeTree(t *testing.T, dir string, files map[string]string, modified time.Time) {
+	t.Helper()
+	for rel, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(rel))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chtimes(path, modified, modified); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func actionKeys(actions []SyncAction) []string {
+	keys := make([]string, len(actions))
+	for i, action := range actions {
+		keys[i] = action.Key
+	}
+	return keys
+}
+
+func TestSyncUploadsOnlyNewAndChangedFiles(t *testing.T) {
+	fake, client := newFakeS3(t)
+	dir := t.TempDir()
+	past := time.Now().Add(-time.Hour).Truncate(time.Second)
+
+	writeTree(t, dir, map[string]string{
+		"a.txt":     "same",
+		"sub/b.txt": "bigger now",
+		"c.txt":     "new",
+	}, past)
+	fake.put(testBucket, "backup/a.txt", []byte("same"), past.Add(time.Minute))
+	fake.put(testBucket, "backup/sub/b.txt", []byte("small"), past.Add(time.Minute))
+	fake.put(testBucket, "backup/orphan.txt", []byte("old"), past)
+
+	report, err := newTestWorker(client, t.TempDir()).Sync(context.Background(), dir, SyncOptions{Prefix: "/backup/"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := actionKeys(report.Uploads); !slices.Equal(got, []string{"backup/c.txt", "backup/sub/b.txt"}) {
+		t.Fatalf("unexpected uploads: %v", got)
+	}
+	if report.Unchanged != 1 || len(report.Deletes) != 0 {
+		t.Fatalf("expected 1 unchanged and no deletes, got %d and %v", report.Unchanged, report.Deletes)
+	}
+	if got, _ := fake.object(testBucket, "backup/sub/b.txt"); string(got) != "bigger now" {
+		t.Fatalf("changed file not uploaded, remote has %q", got)
+	}
+	if _, ok := fake.object(testBucket, "backup/orphan.txt"); !ok {
+		t.Fatal("orphan must be kept without Delete")
+	}
+}
+
+func TestSyncComparesContentWhenLocalIsNewer(t *testing.T) {
+	fake, client := newFakeS3(t)
+	dir := t.TempDir()
+	past := time.Now().Add(-time.Hour).Truncate(time.Second)
+
+	// Mesmo tamanho e mais novos localmente: só o que mudou de conteúdo sobe
+	writeTree(t, dir, map[string]string{"touched.txt": "aaaa", "edited.txt": "bbbb"}, past.Add(time.Minute))
+	fake.put(testBucket, "touched.txt", []byte("aaaa"), past)
+	fake.put(testBucket, "edited.txt", []byte("cccc"), past)
+
+	report, err := newTestWorker(client, t.TempDir()).Sync(context.Background(), dir, SyncOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Uploads) != 1 || report.Uploads[0].Key != "edited.txt" || report.Uploads[0].Reason != "content changed" {
+		t.Fatalf("unexpected uploads: %+v", report.Uploads)
+	}
+	if report.Unchanged != 1 {
+		t.Fatalf("expected touched file to be unchanged, got %d", report.Unchanged)
+	}
+}
+
+func TestSyncChecksumCatchesOlderLocalChanges(t *testing.T) {
+	fake, client := newFakeS3(t)
+	dir := t.TempDir()
+	past := time.Now().Add(-time.Hour).Truncate(time.Second)
+
+	// Arquivo restaurado de backup: mais velho que o objeto, mesmo tamanho, conteúdo diferente
+	writeTree(t, dir, map[string]string{"restored.txt": "v1"}, past)
+	fake.put(testBucket, "restored.txt", []byte("v2"), past.Add(time.Minute))
+
+	report, err := newTestWorker(client, t.TempDir()).Sync(context.Background(), dir, SyncOptions{DryRun: true})
+	if err != nil || len(report.Uploads) != 0 {
+		t.Fatalf("without checksum the older file should be skipped: %v %+v", err, report.Uploads)
+	}
+
+	report, err = newTestWorker(client, t.TempDir()).Sync(context.Background(), dir, SyncOptions{Checksum: true})
+	if err != nil || len(report.Uploads) != 1 {
+		t.Fatalf("checksum should detect the change: %v %+v", err, report.Uploads)
+	}
+	if got, _ := fake.object(testBucket, "restored.txt"); string(got) != "v1" {
+		t.Fatalf("remote has %q", got)
+	}
+}
+
+func TestSyncRecognizesMultipartETag(t *testing.T) {
+	fake, client := newFakeS3(t)
+	dir := t.TempDir()
+	path, _ := writeTestFile(t, 3000)
+	data, _ := os.ReadFile(path)
+	writeTree(t, dir, map[string]string{"big.bin": string(data)}, time.Now().Add(-time.Hour))
+
+	if _, err := newTestWorker(client, t.TempDir()).Sync(context.Background(), dir, SyncOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if fake.count("CompleteMultipartUpload") != 1 {
+		t.Fatalf("expected a multipart upload, calls: %v", fake.calls)
+	}
+
+	// Toca o arquivo: fica mais novo, mas o ETag multipart calculado localmente bate
+	future := time.Now().Add(time.Hour)
+	os.Chtimes(filepath.Join(dir, "big.bin"), future, future)
+
+	report, err := newTestWorker(client, t.TempDir()).Sync(context.Background(), dir, SyncOptions{Checksum: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Uploads) != 0 || report.Unchanged != 1 {
+		t.Fatalf("multipart object should be unchanged, got %+v", report.Uploads)
+	}
+}
+
+func TestSyncComparesEncodedObjectsByOriginalSize(t *testing.T) {
+	_, client := newFakeS3(t)
+	dir := t.TempDir()
+	past := time.Now().Add(-time.Hour)
+	writeTree(t, dir, map[string]string{"a.txt": "conteúdo cifrado", "big.txt": strings.Repeat("x", 3000)}, past)
+	opts := []TransferOption{WithCompression(CompressionGzip), WithEncryption(newTestKeys(t))}
+
+	if _, err := newTestWorker(client, t.TempDir(), opts...).Sync(context.Background(), dir, SyncOptions{}); err != nil {
+		t.Fatal(err)
+	}
+
+	// O objeto no bucket tem outro tamanho, mas o metadata guarda o original
+	report, err := newTestWorker(client, t.TempDir(), opts...).Sync(context.Background(), dir, SyncOptions{Checksum: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Uploads) != 0 || report.Unchanged != 2 {
+		t.Fatalf("encoded objects should be unchanged, got %+v", report.Uploads)
+	}
+
+	report, err = newTestWorker(client, t.TempDir(), WithCompression(CompressionZstd)).Sync(context.Background(), dir, SyncOptions{DryRun: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Uploads) != 2 || report.Uploads[0].Reason != "encoding changed" {
+		t.Fatalf("changing the encoding should re-upload, got %+v", report.Uploads)
+	}
+}
+
+func TestSyncDeleteRespectsFilters(t *testing.T) {
+	fake, client := newFakeS3(t)
+	fake.maxKeys = 2 // Força o ListObjectsV2 a paginar
+	dir := t.TempDir()
+	past := time.Now().Add(-time.Hour).Truncate(time.Second)
+
+	writeTree(t, dir, map[string]string{"keep.txt": "k", "debug.log": "local log", "tmp/cache.txt": "c"}, past)
+	fake.put(testBucket, "keep.txt", []byte("k"), past.Add(time.Minute))
+	fake.put(testBucket, "orphan.txt", []byte("o"), past)
+	fake.put(testBucket, "server.log", []byte("remote log"), past)
+	fake.put(testBucket, "tmp/old.txt", []byte("t"), past)
+
+	report, err := newTestWorker(client, t.TempDir()).Sync(context.Background(), dir, SyncOptions{
+		Delete:  true,
+		Exclude: []string{"*.log", "tmp/"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(report.Uploads) != 0 {
+		t.Fatalf("excluded files must not be uploaded: %+v", report.Uploads)
+	}
+	if got := actionKeys(report.Deletes); !slices.Equal(got, []string{"orphan.txt"}) || report.Deleted != 1 {
+		t.Fatalf("unexpected deletes: %v (deleted %d)", got, report.Deleted)
+	}
+	if got := fake.keys(testBucket); !slices.Equal(got, []string{"keep.txt", "server.log", "tmp/old.txt"}) {
+		t.Fatalf("unexpected remote keys: %v", got)
+	}
+}
+
+func TestSyncDryRunChangesNothing(t *testing.T) {
+	fake, client := newFakeS3(t)
+	dir := t.TempDir()
+	writeTree(t, dir, map[string]string{"new.txt": "n"}, time.Now())
+	fake.put(testBucket, "orphan.txt", []byte("o"), time.Now())
+
+	report, err := newTestWorker(client, t.TempDir()).Sync(context.Background(), dir, SyncOptions{Delete: true, DryRun: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Uploads) != 1 || len(report.Deletes) != 1 {
+		t.Fatalf("dry run should still plan the changes: %+v", report)
+	}
+	if fake.count("PutObject") != 0 || fake.count("DeleteObjects") != 0 {
+		t.Fatalf("dry run must not modify the bucket, calls: %v", fake.calls)
+	}
+}
+
+func TestSyncSkipsCheckpointDir(t *testing.T) {
+	_, client := newFakeS3(t)
+	dir := t.TempDir()
+	writeTree(t, dir, map[string]string{"a.txt": "a", ".upload-checkpoints/x.json": "{}"}, time.Now())
+
+	report, err := newTestWorker(client, filepath.Join(dir, ".upload-checkpoints")).Sync(context.Background(), dir, SyncOptions{DryRun: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := actionKeys(report.Uploads); !slices.Equal(got, []string{"a.txt"}) {
+		t.Fatalf("unexpected uploads: %v", got)
+	}
+}
+
+func TestSyncMatches(t *testing.T) {
+	tests := []struct {
+		name string
+		opts SyncOptions
+		rel  string
+		want bool
+	}{
+		{"no filters", SyncOptions{}, "a/b.txt", true},
+		{"include by name", SyncOptions{Include: []string{"*.txt"}}, "a/b.txt", true},
+		{"include miss", SyncOptions{Include: []string{"*.txt"}}, "a/b.go", false},
+		{"include by path", SyncOptions{Include: []string{"docs/*.md"}}, "docs/readme.md", true},
+		{"path glob is anchored", SyncOptions{Include: []string{"docs/*.md"}}, "src/docs/readme.md", false},
+		{"exclude wins", SyncOptions{Include: []string{"*.txt"}, Exclude: []string{"secret*"}}, "secret.txt", false},
+		{"exclude dir", SyncOptions{Exclude: []string{"node_modules/"}}, "node_modules/x/y.js", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.opts.matches(tt.rel); got != tt.want {
+				t.Fatalf("matches(%q) = %v, want %v", tt.rel, got, tt.want)
+			}
+		})
+	}
+
+	if _, err := NewUploadWorker(NewMemoryStorage()).Sync(context.Background(), t.TempDir(), SyncOptions{Exclude: []string{"["}}); err == nil {
+		t.Fatal("expected invalid glob error")
+	}
+}
@@ -0,0 +1,205 @@
+package main
+
+import (
+	"context"
+	"io"
+	"sync"
+	"time"
+)
+
+// ==============================================================================
+// LIMITE DE BANDA E CONCORRÊNCIA
+// ==============================================================================
+
+// readChunk limita cada leitura para que o limitador de banda tenha granularidade fina
+const readChunk = 32 * 1024
+
+// RateLimiter é um token bucket em bytes por segundo, compartilhado por todas as
+// goroutines que transferem dados. Com taxa 0 não limita nada.
+type RateLimiter struct {
+	mu     sync.Mutex
+	rate   float64 // bytes/s
+	tokens float64
+	last   time.Time
+}
+
+func NewRateLimiter(bytesPerSecond int64) *RateLimiter {
+	return &RateLimiter{rate: float64(bytesPerSecond), last: time.Now()}
+}
+
+// SetRate muda o limite em tempo de execução (0 remove o limite)
+func (l *RateLimiter) SetRate(bytesPerSecond int64) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.refill(time.Now())
+	l.rate = float64(bytesPerSecond)
+	l.tokens = min(l.tokens, l.rate)
+}
+
+// WaitN reserva n bytes e espera até que eles caibam no limite.
+// O saldo pode ficar negativo: quem reserva depois espera a dívida ser paga.
+func (l *RateLimiter) WaitN(ctx context.Context, n int) error {
+	if l == nil {
+		return nil
+	}
+
+	l.mu.Lock()
+	if l.rate <= 0 {
+		l.mu.Unlock()
+		return nil
+	}
+	now := time.Now()
+	l.refill(now)
+	l.tokens -= float64(n)
+	wait := time.Duration(-l.tokens / l.rate * float64(time.Second))
+	l.mu.Unlock()
+
+	if wait <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// refill soma os tokens acumulados desde a última chamada, até o limite de 1s de rajada
+func (l *RateLimiter) refill(now time.Time) {
+	l.tokens = min(l.tokens+now.Sub(l.last).Seconds()*l.rate, l.rate)
+	l.last = now
+}
+
+// dynamicSemaphore é um semáforo cujo limite pode mudar com transferências em andamento.
+// Ao reduzir, quem já está rodando termina normalmente; só novas entradas esperam.
+type dynamicSemaphore struct {
+	mu     sync.Mutex
+	limit  int
+	active int
+	wake   chan struct{} // Fechado (e trocado) sempre que uma vaga pode ter surgido
+}
+
+func newDynamicSemaphore(limit int) *dynamicSemaphore {
+	return &dynamicSemaphore{limit: max(limit, 1), wake: make(chan struct{})}
+}
+
+func (s *dynamicSemaphore) Acquire(ctx context.Context) error {
+	// Com o contexto já cancelado não entra, mesmo havendo vaga
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	for {
+		s.mu.Lock()
+		if s.active < s.limit {
+			s.active++
+			s.mu.Unlock()
+			return nil
+		}
+		wake := s.wake
+		s.mu.Unlock()
+
+		select {
+		case <-wake:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+func (s *dynamicSemaphore) Release() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.active--
+	s.broadcast()
+}
+
+func (s *dynamicSemaphore) SetLimit(limit int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.limit = max(limit, 1)
+	s.broadcast()
+}
+
+func (s *dynamicSemaphore) Limit() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.limit
+}
+
+func (s *dynamicSemaphore) broadcast() {
+	close(s.wake)
+	s.wake = make(chan struct{})
+}
+
+// ==============================================================================
+// LEITOR COM LIMITE DE BANDA E PROGRESSO
+// ==============================================================================
+
+// transferReader passa os bytes de um corpo de requisição (ou resposta) pelo limitador
+// e avisa o progresso. Cada leitura conta, exceto quando o backend desliga sending até o envio
+// começar (o SDK do S3 lê o corpo uma vez para calcular MD5/SHA256; veja sendPhase).
+type transferReader struct {
+	ctx      context.Context
+	r        io.Reader
+	limiter  *RateLimiter
+	progress func(delta int64)
+	sending  bool
+	pos      int64
+	reported int64 // Soma do que já foi avisado, para desfazer se a tentativa falhar
+}
+
+func newTransferReader(ctx context.Context, r io.Reader, limiter *RateLimiter, progress func(int64)) *transferReader {
+	return &transferReader{ctx: ctx, r: r, limiter: limiter, progress: progress, sending: true}
+}
+
+func (t *transferReader) Read(p []byte) (int, error) {
+	if !t.sending {
+		n, err := t.r.Read(p)
+		t.pos += int64(n)
+		return n, err
+	}
+
+	if len(p) > readChunk {
+		p = p[:readChunk]
+	}
+	if err := t.limiter.WaitN(t.ctx, len(p)); err != nil {
+		return 0, err
+	}
+	n, err := t.r.Read(p)
+	t.pos += int64(n)
+	if n > 0 {
+		t.report(int64(n))
+	}
+	return n, err
+}
+
+// Seek é usado pelo SDK para voltar ao início depois do hash ou antes de reenviar.
+// Durante o envio, voltar atrás desconta o progresso já reportado.
+func (t *transferReader) Seek(offset int64, whence int) (int64, error) {
+	pos, err := t.r.(io.Seeker).Seek(offset, whence)
+	if err != nil {
+		return pos, err
+	}
+	if t.sending && pos != t.pos {
+		t.report(pos - t.pos)
+	}
+	t.pos = pos
+	return pos, nil
+}
+
+// undo desconta tudo o que esta tentativa avisou; a próxima tentativa conta de novo
+func (t *transferReader) undo() {
+	if t.reported != 0 {
+		t.report(-t.reported)
+	}
+}
+
+func (t *transferReader) report(delta int64) {
+	t.reported += delta
+	if t.progress != nil {
+		t.progress(delta)
+	}
+}
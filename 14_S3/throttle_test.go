@@ -0,0 +1,87 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestRateLimiterSharedAcrossGoroutines(t *testing.T) {
+	limiter := NewRateLimiter(200_000) // 200KB/s
+	start := time.Now()
+
+	var wg sync.WaitGroup
+	for range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range 5 {
+				limiter.WaitN(context.Background(), 5_000)
+			}
+		}()
+	}
+	wg.Wait()
+
+	// 100KB a 200KB/s: pelo menos meio segundo no total, não por goroutine
+	if elapsed := time.Since(start); elapsed < 450*time.Millisecond || elapsed > 2*time.Second {
+		t.Fatalf("expected ~500ms for 100KB at 200KB/s, took %v", elapsed)
+	}
+}
+
+func TestRateLimiterUnlimitedAndCancel(t *testing.T) {
+	limiter := NewRateLimiter(0)
+	start := time.Now()
+	for range 100 {
+		limiter.WaitN(context.Background(), 1<<20)
+	}
+	if time.Since(start) > 50*time.Millisecond {
+		t.Fatal("rate 0 should not limit")
+	}
+
+	limiter.SetRate(1000)
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := limiter.WaitN(ctx, 1_000_000); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected deadline exceeded, got %v", err)
+	}
+}
+
+func TestDynamicSemaphoreResize(t *testing.T) {
+	sem := newDynamicSemaphore(1)
+	ctx := context.Background()
+	sem.Acquire(ctx)
+
+	acquired := make(chan struct{})
+	go func() {
+		sem.Acquire(ctx)
+		close(acquired)
+	}()
+
+	select {
+	case <-acquired:
+		t.Fatal("second acquire should wait while the limit is 1")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	sem.SetLimit(2)
+	select {
+	case <-acquired:
+	case <-time.After(time.Second):
+		t.Fatal("raising the limit should wake the waiter")
+	}
+
+	// Reduzir não interrompe quem já entrou, só barra os próximos
+	sem.SetLimit(1)
+	sem.Release()
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	if err := sem.Acquire(cancelled); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected canceled acquire with 1 active and limit 1, got %v", err)
+	}
+	sem.Release()
+	if err := sem.Acquire(ctx); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,424 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ==============================================================================
+// UPLOAD OTIMIZADO (CONCORRENTE)
+// ==============================================================================
+
+// Resultado do upload
+type UploadResult struct {
+	Key      string
+	Success  bool
+	Error    error
+	Size     int64
+	Time     time.Duration
+	Attempts int // Tentativas do objeto inteiro (as das partes ficam dentro de cada uma)
+}
+
+// UploadItem liga um arquivo local à key de destino no bucket
+type UploadItem struct {
+	Path string
+	Key  string
+}
+
+// Worker para uploads concorrentes
+// O semáforo (transferConfig.sem) controla a concorrência e pode ser ajustado com SetConcurrency
+type UploadWorker struct {
+	transferConfig
+	store   Storage
+	results chan UploadResult
+	wg      sync.WaitGroup
+}
+
+func NewUploadWorker(store Storage, opts ...TransferOption) *UploadWorker {
+	return &UploadWorker{
+		transferConfig: newTransferConfig(maxConcurrentUploads, opts),
+		store:          store,
+		results:        make(chan UploadResult, 100),
+	}
+}
+
+// UploadMultipleFiles envia cada arquivo usando o próprio caminho como key
+func (w *UploadWorker) UploadMultipleFiles(ctx context.Context, filePaths []string) []UploadResult {
+	items := make([]UploadItem, len(filePaths))
+	for i, filePath := range filePaths {
+		items[i] = UploadItem{Path: filePath, Key: filePath}
+	}
+	return w.UploadItems(ctx, items)
+}
+
+// UploadItems envia os arquivos para as keys indicadas. Cancelar ctx interrompe os
+// uploads em andamento (os multipart ficam no checkpoint) e os que ainda não começaram.
+func (w *UploadWorker) UploadItems(ctx context.Context, items []UploadItem) []UploadResult {
+	fmt.Printf("🚀 Iniciando upload de %d arquivos com %d workers...\n",
+		len(items), w.sem.Limit())
+
+	start := time.Now()
+
+	var tracker *progressTracker
+	if w.progress != nil {
+		tracker = newProgressTracker(w.progress)
+		for _, item := range items {
+			var size int64
+			if info, err := os.Stat(item.Path); err == nil {
+				size = info.Size()
+			}
+			tracker.track(item.Key, size)
+		}
+	}
+
+	// Inicia workers
+	for _, item := range items {
+		w.wg.Add(1)
+		go w.uploadFileAsync(ctx, item, tracker)
+	}
+
+	// Coleta resultados
+	go func() {
+		w.wg.Wait()
+		close(w.results)
+	}()
+
+	var results []UploadResult
+	for result := range w.results {
+		results = append(results, result)
+	}
+
+	totalTime := time.Since(start)
+	fmt.Printf("⏱️  Upload concluído em %v\n", totalTime)
+
+	// Estatísticas
+	successCount := 0
+	totalSize := int64(0)
+	for _, result := range results {
+		if result.Success {
+			successCount++
+			totalSize += result.Size
+		}
+	}
+
+	fmt.Printf("📊 Estatísticas:\n")
+	fmt.Printf("   ✅ Sucessos: %d/%d\n", successCount, len(items))
+	fmt.Printf("   📦 Tamanho total: %.2f MB\n", float64(totalSize)/(1024*1024))
+	fmt.Printf("   ⚡ Velocidade: %.2f MB/s\n",
+		float64(totalSize)/(1024*1024)/totalTime.Seconds())
+
+	return results
+}
+
+func (w *UploadWorker) uploadFileAsync(ctx context.Context, item UploadItem, tracker *progressTracker) {
+	defer w.wg.Done()
+
+	start := time.Now()
+
+	// Controla concorrência
+	if err := w.sem.Acquire(ctx); err != nil {
+		tracker.finish(item.Key, err)
+		w.results <- UploadResult{Key: item.Key, Error: err, Time: time.Since(start)}
+		return
+	}
+	defer w.sem.Release()
+
+	// Retry do objeto inteiro: num multipart, a nova tentativa retoma pelo checkpoint
+	var size int64
+	attempts, err := w.retry.Do(ctx, func() error {
+		// Cada tentativa conta o progresso do zero (partes retomadas são somadas de novo)
+		tracker.reset(item.Key)
+		var err error
+		size, err = w.uploadFileWithRetry(ctx, item, tracker)
+		return err
+	})
+	tracker.finish(item.Key, err)
+
+	w.results <- UploadResult{
+		Key:      item.Key,
+		Success:  err == nil,
+		Error:    err,
+		Size:     size,
+		Time:     time.Since(start),
+		Attempts: attempts,
+	}
+}
+
+func (w *UploadWorker) uploadFileWithRetry(ctx context.Context, item UploadItem, tracker *progressTracker) (int64, error) {
+	file, err := os.Open(item.Path)
+	if err != nil {
+		return 0, err
+	}
+	defer file.Close()
+
+	fileInfo, err := file.Stat()
+	if err != nil {
+		return 0, err
+	}
+
+	// Para arquivos grandes, usa multipart upload
+	if fileInfo.Size() > w.partSize {
+		return w.multipartUpload(ctx, file, item.Key, fileInfo, tracker)
+	}
+
+	// Upload simples para arquivos pequenos
+	var data io.ReadSeeker = file
+	var metadata map[string]string
+	progress := tracker.adder(item.Key)
+	if w.encodes() {
+		// O resultado cabe numa parte: transforma em memória para ter um corpo com tamanho conhecido
+		enc, err := w.newEncoding(fileInfo.Size())
+		if err != nil {
+			return 0, err
+		}
+		encoded, err := w.encodeAll(ctx, file, enc, progress)
+		if err != nil {
+			return 0, err
+		}
+		data, metadata, progress = bytes.NewReader(encoded), enc.metadata(), nil
+	}
+
+	body := newTransferReader(ctx, data, w.limiter, progress)
+	_, err = w.store.PutObject(ctx, item.Key, body, PutOptions{
+		ContentType: getContentType(item.Key),
+		Metadata:    metadata,
+	})
+
+	return fileInfo.Size(), err
+}
+
+// encodeAll passa o arquivo inteiro pelo pipeline. Com transformação, o progresso conta
+// os bytes lidos do arquivo: o tamanho enviado não é o do arquivo.
+func (w *UploadWorker) encodeAll(ctx context.Context, file io.Reader, enc *objectEncoding, progress func(int64)) ([]byte, error) {
+	plain := newTransferReader(ctx, file, nil, progress)
+	encoded, err := w.encodeReader(plain, enc)
+	if err != nil {
+		return nil, err
+	}
+	defer encoded.Close()
+	return io.ReadAll(encoded)
+}
+
+// Multipart upload para arquivos grandes.
+// Cada parte concluída vai para o checkpoint; se o upload cair no meio, a próxima
+// execução retoma o mesmo UploadId e só envia as partes que faltam.
+// Com compressão/criptografia as partes saem do pipeline em sequência; como a chave e o
+// nonce ficam no checkpoint, a retomada gera os mesmos bytes e as partes conferem.
+func (w *UploadWorker) multipartUpload(ctx context.Context, file *os.File, key string, info os.FileInfo, tracker *progressTracker) (int64, error) {
+	cp, err := w.openCheckpoint(ctx, key, info)
+	if err != nil {
+		return 0, err
+	}
+
+	var src io.Reader = file
+	partProgress := tracker.adder(key)
+	if cp.Encoding != nil {
+		plain := newTransferReader(ctx, file, nil, partProgress)
+		encoded, err := w.encodeReader(plain, cp.Encoding)
+		if err != nil {
+			return 0, err
+		}
+		defer encoded.Close()
+		src, partProgress = encoded, nil
+	}
+
+	buffer := make([]byte, w.partSize)
+	var parts []PartInfo
+	var stored int64
+
+	for partNumber := int64(1); ; partNumber++ {
+		if err := ctx.Err(); err != nil {
+			return 0, err
+		}
+
+		n, readErr := io.ReadFull(src, buffer)
+		if readErr == io.EOF {
+			break
+		}
+		if readErr != nil && readErr != io.ErrUnexpectedEOF {
+			return 0, readErr
+		}
+		chunk := buffer[:n]
+
+		// Parte já aceita pelo storage com o mesmo conteúdo: não precisa reenviar
+		done, ok := cp.Parts[partNumber]
+		if ok && done.ETag == partETag(chunk) {
+			if partProgress != nil {
+				partProgress(int64(len(chunk)))
+			}
+		} else {
+			etag, err := w.uploadPart(ctx, key, cp.UploadID, partNumber, chunk, partProgress)
+			if err != nil {
+				// Não aborta: o UploadId e as partes já enviadas continuam no checkpoint
+				return 0, fmt.Errorf("part %d of %s: %w", partNumber, key, err)
+			}
+			cp.Parts[partNumber] = completedPart{ETag: etag, Size: int64(len(chunk))}
+			if err := cp.save(); err != nil {
+				return 0, err
+			}
+		}
+
+		parts = append(parts, PartInfo{PartNumber: partNumber, ETag: cp.Parts[partNumber].ETag})
+		stored += int64(len(chunk))
+
+		if readErr == io.ErrUnexpectedEOF {
+			break
+		}
+	}
+
+	if err := w.completeMultipartUpload(ctx, key, cp.UploadID, parts, stored); err != nil {
+		return 0, err
+	}
+
+	return info.Size(), cp.remove()
+}
+
+func (w *UploadWorker) uploadPart(ctx context.Context, key, uploadID string, partNumber int64, chunk []byte, progress func(int64)) (string, error) {
+	var etag string
+	_, err := w.retry.Do(ctx, func() error {
+		body := newTransferReader(ctx, bytes.NewReader(chunk), w.limiter, progress)
+		var err error
+		etag, err = w.store.UploadPart(ctx, key, uploadID, partNumber, body)
+		if err != nil {
+			body.undo()
+		}
+		return err
+	})
+	return etag, err
+}
+
+// openCheckpoint retoma o upload salvo para key ou inicia um novo.
+// A lista de partes vem sempre do ListParts: o storage é quem sabe o que realmente recebeu.
+func (w *UploadWorker) openCheckpoint(ctx context.Context, key string, info os.FileInfo) (*uploadCheckpoint, error) {
+	location := w.store.Location()
+	path := checkpointPath(w.checkpointDir, location, key)
+
+	cp, err := loadCheckpoint(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if cp != nil {
+		if cp.matches(location, key, info, w.partSize) && w.encodingMatches(cp.Encoding) {
+			parts, err := w.listParts(ctx, key, cp.UploadID)
+			if err == nil {
+				cp.Parts = parts
+				return cp, nil
+			}
+			if !errors.Is(err, ErrNoSuchUpload) {
+				return nil, err
+			}
+			log.Printf("⚠️  Upload %s de '%s' não existe mais, recomeçando", cp.UploadID, key)
+		} else {
+			// O arquivo (ou a compressão/chave) mudou desde o checkpoint: as partes antigas não servem
+			w.store.AbortMultipartUpload(ctx, cp.Key, cp.UploadID)
+		}
+	}
+
+	enc, err := w.newEncoding(info.Size())
+	if err != nil {
+		return nil, err
+	}
+
+	var uploadID string
+	_, err = w.retry.Do(ctx, func() error {
+		var err error
+		uploadID, err = w.store.CreateMultipartUpload(ctx, key, PutOptions{
+			ContentType: getContentType(key),
+			Metadata:    enc.metadata(),
+		})
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	cp = &uploadCheckpoint{
+		Location: location,
+		Key:      key,
+		UploadID: uploadID,
+		FileSize: info.Size(),
+		ModTime:  info.ModTime(),
+		PartSize: w.partSize,
+		Parts:    make(map[int64]completedPart),
+		Encoding: enc,
+		path:     path,
+	}
+	return cp, cp.save()
+}
+
+// listParts traz as partes que o storage já recebeu, indexadas pelo número
+func (w *UploadWorker) listParts(ctx context.Context, key, uploadID string) (map[int64]completedPart, error) {
+	var list []PartInfo
+	_, err := w.retry.Do(ctx, func() error {
+		var err error
+		list, err = w.store.ListParts(ctx, key, uploadID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	parts := make(map[int64]completedPart, len(list))
+	for _, part := range list {
+		parts[part.PartNumber] = completedPart{ETag: part.ETag, Size: part.Size}
+	}
+	return parts, nil
+}
+
+func (w *UploadWorker) completeMultipartUpload(ctx context.Context, key, uploadID string, parts []PartInfo, size int64) error {
+	attempt := 0
+	_, err := w.retry.Do(ctx, func() error {
+		attempt++
+		_, err := w.store.CompleteMultipartUpload(ctx, key, uploadID, parts)
+		// Se a resposta de uma tentativa anterior se perdeu, o upload pode já ter sido
+		// concluído: nesse caso o objeto existe com o tamanho esperado
+		if attempt > 1 && errors.Is(err, ErrNoSuchUpload) {
+			head, headErr := w.store.HeadObject(ctx, key, 0)
+			if headErr == nil && head.Size == size {
+				return nil
+			}
+		}
+		return err
+	})
+	return err
+}
+
+// partETag é o ETag que o S3 (e qualquer Storage) devolve para uma parte: o MD5 do conteúdo em hexadecimal
+func partETag(chunk []byte) string {
+	sum := md5.Sum(chunk)
+	return hex.EncodeToString(sum[:])
+}
+
+func normalizeETag(etag string) string {
+	return strings.Trim(etag, `"`)
+}
+
+func getContentType(filename string) string {
+	ext := filepath.Ext(filename)
+	switch ext {
+	case ".txt":
+		return "text/plain"
+	case ".json":
+		return "application/json"
+	case ".jpg", ".jpeg":
+		return "image/jpeg"
+	case ".png":
+		return "image/png"
+	case ".pdf":
+		return "application/pdf"
+	default:
+		return "application/octet-stream"
+	}
+}
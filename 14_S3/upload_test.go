@@ -0,0 +1,249 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"math/rand/v2"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/service/s3"
+)
+
+const testBucket = "test-bucket"
+
+var fastRetry = RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: 5 * time.Millisecond}
+
+// newTestWorker usa partes de 1KB para que os testes de multipart rodem com arquivos pequenos
+func newTestWorker(client *s3.S3, checkpointDir string, opts ...TransferOption) *UploadWorker {
+	opts = append([]TransferOption{WithRetryPolicy(fastRetry), WithCheckpointDir(checkpointDir)}, opts...)
+	w := NewUploadWorker(NewS3Storage(client, testBucket), opts...)
+	w.partSize = 1024
+	return w
+}
+
+func writeTestFile(t *testing.T, size int) (string, []byte) {
+	t.Helper()
+	content := make([]byte, size)
+	for i := range content {
+		content[i] = byte(rand.IntN(256))
+	}
+	path := filepath.Join(t.TempDir(), "file.bin")
+	if err := os.WriteFile(path, content, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path, content
+}
+
+func checkpointFiles(t *testing.T, dir string) []string {
+	t.Helper()
+	files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return files
+}
+
+func TestUploadRetriesTransientErrors(t *testing.T) {
+	fake, client := newFakeS3(t)
+	fake.failTimes("PutObject", 0, 2, 503)
+	path, content := writeTestFile(t, 100)
+
+	results := newTestWorker(client, t.TempDir()).UploadMultipleFiles(context.Background(), []string{path})
+
+	if !results[0].Success {
+		t.Fatalf("upload failed: %v", results[0].Error)
+	}
+	if results[0].Attempts != 3 {
+		t.Fatalf("expected 3 attempts, got %d", results[0].Attempts)
+	}
+	if got, _ := fake.object(testBucket, path); !bytes.Equal(got, content) {
+		t.Fatal("stored object does not match the file")
+	}
+}
+
+func TestUploadDoesNotRetryClientErrors(t *testing.T) {
+	fake, client := newFakeS3(t)
+	fake.failTimes("PutObject", 0, 10, 403)
+	path, _ := writeTestFile(t, 100)
+
+	results := newTestWorker(client, t.TempDir()).UploadMultipleFiles(context.Background(), []string{path})
+
+	if results[0].Success {
+		t.Fatal("expected upload to fail")
+	}
+	if results[0].Attempts != 1 || fake.count("PutObject") != 1 {
+		t.Fatalf("expected a single attempt, got %d (%d requests)", results[0].Attempts, fake.count("PutObject"))
+	}
+}
+
+func TestMultipartRetriesOnlyTheFailedPart(t *testing.T) {
+	fake, client := newFakeS3(t)
+	fake.failTimes("UploadPart", 2, 2, 500)
+	path, content := writeTestFile(t, 3000)
+	checkpointDir := t.TempDir()
+
+	results := newTestWorker(client, checkpointDir).UploadMultipleFiles(context.Background(), []string{path})
+
+	if !results[0].Success {
+		t.Fatalf("upload failed: %v", results[0].Error)
+	}
+	if results[0].Attempts != 1 {
+		t.Fatalf("part retries should not restart the object, got %d attempts", results[0].Attempts)
+	}
+	if fake.count("CreateMultipartUpload") != 1 || fake.count("UploadPart:1") != 1 || fake.count("UploadPart:2") != 3 {
+		t.Fatalf("unexpected requests: %v", fake.calls)
+	}
+	if got, _ := fake.object(testBucket, path); !bytes.Equal(got, content) {
+		t.Fatal("stored object does not match the file")
+	}
+	if files := checkpointFiles(t, checkpointDir); len(files) != 0 {
+		t.Fatalf("checkpoint should be removed after completion, found %v", files)
+	}
+}
+
+func TestMultipartResumesFromCheckpoint(t *testing.T) {
+	fake, client := newFakeS3(t)
+	fake.maxParts = 2 // Força o ListParts a paginar
+	fake.failTimes("UploadPart", 5, 1000, 500)
+	path, content := writeTestFile(t, 5000)
+	checkpointDir := t.TempDir()
+
+	// Primeira execução: a parte 5 nunca passa
+	results := newTestWorker(client, checkpointDir).UploadMultipleFiles(context.Background(), []string{path})
+	if results[0].Success {
+		t.Fatal("expected first run to fail")
+	}
+	files := checkpointFiles(t, checkpointDir)
+	if len(files) != 1 {
+		t.Fatalf("expected one checkpoint, found %v", files)
+	}
+	cp, err := loadCheckpoint(files[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cp.Parts) != 4 {
+		t.Fatalf("expected 4 completed parts in checkpoint, got %d", len(cp.Parts))
+	}
+
+	// Segunda execução: retoma o mesmo upload e envia só a parte que faltou
+	fake.inject = nil
+	results = newTestWorker(client, checkpointDir).UploadMultipleFiles(context.Background(), []string{path})
+	if !results[0].Success {
+		t.Fatalf("resume failed: %v", results[0].Error)
+	}
+
+	if fake.count("CreateMultipartUpload") != 1 {
+		t.Fatalf("expected the upload to be resumed, got %d uploads", fake.count("CreateMultipartUpload"))
+	}
+	for _, part := range []string{"UploadPart:1", "UploadPart:2", "UploadPart:3", "UploadPart:4"} {
+		if fake.count(part) != 1 {
+			t.Fatalf("%s sent %d times, expected once", part, fake.count(part))
+		}
+	}
+	if got, _ := fake.object(testBucket, path); !bytes.Equal(got, content) {
+		t.Fatal("stored object does not match the file")
+	}
+	if files := checkpointFiles(t, checkpointDir); len(files) != 0 {
+		t.Fatalf("checkpoint should be removed after completion, found %v", files)
+	}
+}
+
+func TestMultipartRestartsWhenUploadIsGone(t *testing.T) {
+	fake, client := newFakeS3(t)
+	fake.failTimes("UploadPart", 3, 1000, 500)
+	path, content := writeTestFile(t, 3000)
+	checkpointDir := t.TempDir()
+
+	newTestWorker(client, checkpointDir).UploadMultipleFiles(context.Background(), []string{path})
+
+	// Simula uma lifecycle rule que limpou o upload incompleto
+	fake.inject = nil
+	clear(fake.uploads)
+
+	results := newTestWorker(client, checkpointDir).UploadMultipleFiles(context.Background(), []string{path})
+	if !results[0].Success {
+		t.Fatalf("upload failed: %v", results[0].Error)
+	}
+	if fake.count("CreateMultipartUpload") != 2 {
+		t.Fatalf("expected a new upload, got %d", fake.count("CreateMultipartUpload"))
+	}
+	if got, _ := fake.object(testBucket, path); !bytes.Equal(got, content) {
+		t.Fatal("stored object does not match the file")
+	}
+}
+
+func TestMultipartRestartsWhenFileChanged(t *testing.T) {
+	fake, client := newFakeS3(t)
+	fake.failTimes("UploadPart", 3, 1000, 500)
+	path, _ := writeTestFile(t, 3000)
+	checkpointDir := t.TempDir()
+
+	newTestWorker(client, checkpointDir).UploadMultipleFiles(context.Background(), []string{path})
+
+	changed := bytes.Repeat([]byte("x"), 2500)
+	if err := os.WriteFile(path, changed, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	fake.inject = nil
+	results := newTestWorker(client, checkpointDir).UploadMultipleFiles(context.Background(), []string{path})
+	if !results[0].Success {
+		t.Fatalf("upload failed: %v", results[0].Error)
+	}
+	if fake.count("AbortMultipartUpload") != 1 || len(fake.uploads) != 0 {
+		t.Fatalf("stale upload should be aborted, calls: %v", fake.calls)
+	}
+	if got, _ := fake.object(testBucket, path); !bytes.Equal(got, changed) {
+		t.Fatal("stored object does not match the changed file")
+	}
+}
+
+func TestEncryptedMultipartResumesWithTheSameKey(t *testing.T) {
+	fake, client := newFakeS3(t)
+	fake.failTimes("UploadPart", 4, 1000, 500)
+	path, content := writeTestFile(t, 5000)
+	checkpointDir := t.TempDir()
+	opts := []TransferOption{WithCompression(CompressionZstd), WithEncryption(newTestKeys(t))}
+
+	results := newTestWorker(client, checkpointDir, opts...).UploadMultipleFiles(context.Background(), []string{path})
+	if results[0].Success {
+		t.Fatal("expected first run to fail")
+	}
+
+	// A segunda execução precisa gerar os mesmos bytes (mesma chave e nonce) para aproveitar as partes
+	fake.inject = nil
+	results = newTestWorker(client, checkpointDir, opts...).UploadMultipleFiles(context.Background(), []string{path})
+	if !results[0].Success {
+		t.Fatalf("resume failed: %v", results[0].Error)
+	}
+	if fake.count("CreateMultipartUpload") != 1 || fake.count("UploadPart:1") != 1 || fake.count("UploadPart:3") != 1 {
+		t.Fatalf("expected encrypted upload to be resumed, calls: %v", fake.calls)
+	}
+
+	dest := filepath.Join(t.TempDir(), "out")
+	result := newTestDownloader(client, t.TempDir(), opts...).Download(context.Background(), path, dest)
+	if got, _ := os.ReadFile(dest); !result.Success || !bytes.Equal(got, content) {
+		t.Fatalf("resumed object does not decode to the file: %v", result.Error)
+	}
+}
+
+func TestMultipartRestartsWhenEncodingChanges(t *testing.T) {
+	fake, client := newFakeS3(t)
+	fake.failTimes("UploadPart", 3, 1000, 500)
+	path, _ := writeTestFile(t, 4000)
+	checkpointDir := t.TempDir()
+
+	newTestWorker(client, checkpointDir, WithCompression(CompressionGzip)).UploadMultipleFiles(context.Background(), []string{path})
+
+	fake.inject = nil
+	results := newTestWorker(client, checkpointDir, WithCompression(CompressionZstd)).UploadMultipleFiles(context.Background(), []string{path})
+	if !results[0].Success {
+		t.Fatal(results[0].Error)
+	}
+	if fake.count("CreateMultipartUpload") != 2 || fake.count("AbortMultipartUpload") != 1 {
+		t.Fatalf("expected a new upload after changing compression, calls: %v", fake.calls)
+	}
+}
@@ -0,0 +1,283 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/ElizCarvalho/FC_PosGolang/16_SQLC/internal/db"
+	"github.com/ElizCarvalho/FC_PosGolang/pkg/txn"
+)
+
+const (
+	defaultPageSize = 20
+	maxPageSize     = 100
+)
+
+var (
+	ErrCategoryNotFound = errors.New("category not found")
+	ErrCourseNotFound   = errors.New("course not found")
+	ErrInvalidFilter    = errors.New("invalid course filter")
+)
+
+type CourseDB struct {
+	dbConn *sql.DB
+	*db.Queries
+}
+
+type CourseParams struct {
+	ID          string
+	Name        string
+	Description sql.NullString
+	Price       float64
+	CategoryID  string // Ignorado em CreateCourseAndCategory, que usa a categoria criada junto
+}
+
+type CategoryParams struct {
+	ID          string
+	Name        string
+	Description sql.NullString
+}
+
+// CourseFilter restringe a listagem; campos vazios (nil) não filtram
+type CourseFilter struct {
+	CategoryID string
+	MinPrice   *float64
+	MaxPrice   *float64
+	Limit      int32 // 0 usa defaultPageSize; o máximo é maxPageSize
+	Offset     int32
+}
+
+// CoursePage é uma página da listagem, com o total de cursos que passam no filtro
+type CoursePage struct {
+	Courses []db.ListCoursesRow
+	Total   int64
+	Limit   int32
+	Offset  int32
+}
+
+func NewCourseDB(dbConn *sql.DB) *CourseDB {
+	return &CourseDB{
+		dbConn:  dbConn,
+		Queries: db.New(dbConn),
+	}
+}
+
+// callTx roda fn numa transação do txn: rollback em erro e nova tentativa em deadlock,
+// então fn pode rodar mais de uma vez e só deve preencher variáveis do caso de uso
+func (c *CourseDB) callTx(ctx context.Context, fn func(*db.Queries) error, opts ...txn.Option) error {
+	return txn.RunQueries(ctx, c.dbConn, newTxQueries, func(q *db.Queries, _ *txn.Tx) error {
+		return fn(q)
+	}, opts...)
+}
+
+func newTxQueries(tx *sql.Tx) *db.Queries {
+	return db.New(tx)
+}
+
+func (c *CourseDB) CreateCourseAndCategory(ctx context.Context, argsCategory CategoryParams, argsCourse CourseParams) error {
+	err := c.callTx(ctx, func(q *db.Queries) error {
+		var err error
+		err = q.CreateCategory(ctx, db.CreateCategoryParams{
+			ID:          argsCategory.ID,
+			Name:        argsCategory.Name,
+			Description: argsCategory.Description,
+		})
+		if err != nil {
+			return err
+		}
+		err = q.CreateCourse(ctx, db.CreateCourseParams{
+			ID:          argsCourse.ID,
+			Name:        argsCourse.Name,
+			Description: argsCourse.Description,
+			CategoryID:  argsCategory.ID,
+			Price:       argsCourse.Price,
+		})
+		if err != nil {
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// CreateCourseInCategory cria o curso numa categoria que já existe
+func (c *CourseDB) CreateCourseInCategory(ctx context.Context, args CourseParams) error {
+	return c.callTx(ctx, func(q *db.Queries) error {
+		if err := categoryExists(ctx, q, args.CategoryID); err != nil {
+			return err
+		}
+		return q.CreateCourse(ctx, db.CreateCourseParams{
+			ID:          args.ID,
+			Name:        args.Name,
+			Description: args.Description,
+			CategoryID:  args.CategoryID,
+			Price:       args.Price,
+		})
+	})
+}
+
+func (c *CourseDB) GetCourse(ctx context.Context, id string) (db.Course, error) {
+	course, err := c.GetCourseByID(ctx, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return db.Course{}, fmt.Errorf("%s: %w", id, ErrCourseNotFound)
+	}
+	return course, err
+}
+
+// EditCourse atualiza todos os campos do curso, inclusive a categoria
+func (c *CourseDB) EditCourse(ctx context.Context, args CourseParams) error {
+	return c.callTx(ctx, func(q *db.Queries) error {
+		if err := courseExists(ctx, q, args.ID); err != nil {
+			return err
+		}
+		if err := categoryExists(ctx, q, args.CategoryID); err != nil {
+			return err
+		}
+		return q.UpdateCourse(ctx, db.UpdateCourseParams{
+			Name:        args.Name,
+			Description: args.Description,
+			CategoryID:  args.CategoryID,
+			Price:       args.Price,
+			ID:          args.ID,
+		})
+	})
+}
+
+func (c *CourseDB) RemoveCourse(ctx context.Context, id string) error {
+	return c.callTx(ctx, func(q *db.Queries) error {
+		if err := courseExists(ctx, q, id); err != nil {
+			return err
+		}
+		return q.DeleteCourse(ctx, id)
+	})
+}
+
+// SearchCourses lista uma página de cursos. Página e total saem da mesma transação,
+// para que o total corresponda aos cursos listados.
+func (c *CourseDB) SearchCourses(ctx context.Context, filter CourseFilter) (CoursePage, error) {
+	if filter.MinPrice != nil && filter.MaxPrice != nil && *filter.MinPrice > *filter.MaxPrice {
+		return CoursePage{}, fmt.Errorf("%w: min price %.2f is greater than max price %.2f", ErrInvalidFilter, *filter.MinPrice, *filter.MaxPrice)
+	}
+	if filter.Limit < 0 || filter.Offset < 0 {
+		return CoursePage{}, fmt.Errorf("%w: limit and offset must not be negative", ErrInvalidFilter)
+	}
+	if filter.Limit == 0 {
+		filter.Limit = defaultPageSize
+	}
+	filter.Limit = min(filter.Limit, maxPageSize)
+
+	category := sql.NullString{String: filter.CategoryID, Valid: filter.CategoryID != ""}
+	minPrice, maxPrice := nullFloat(filter.MinPrice), nullFloat(filter.MaxPrice)
+
+	page := CoursePage{Limit: filter.Limit, Offset: filter.Offset}
+	err := c.callTx(ctx, func(q *db.Queries) error {
+		var err error
+		page.Total, err = q.CountCourses(ctx, db.CountCoursesParams{
+			CategoryID: category,
+			MinPrice:   minPrice,
+			MaxPrice:   maxPrice,
+		})
+		if err != nil {
+			return err
+		}
+		page.Courses, err = q.ListCourses(ctx, db.ListCoursesParams{
+			CategoryID: category,
+			MinPrice:   minPrice,
+			MaxPrice:   maxPrice,
+			Limit:      filter.Limit,
+			Offset:     filter.Offset,
+		})
+		return err
+	}, txn.ReadOnly())
+	if err != nil {
+		return CoursePage{}, err
+	}
+	return page, nil
+}
+
+// DeleteCategoryCascade remove a categoria e todos os cursos dela. Retorna quantos cursos foram removidos.
+func (c *CourseDB) DeleteCategoryCascade(ctx context.Context, categoryID string) (int64, error) {
+	var deleted int64
+	err := c.callTx(ctx, func(q *db.Queries) error {
+		if err := categoryExists(ctx, q, categoryID); err != nil {
+			return err
+		}
+		var err error
+		deleted, err = q.DeleteCoursesByCategory(ctx, categoryID)
+		if err != nil {
+			return err
+		}
+		return q.DeleteCategory(ctx, categoryID)
+	})
+	if err != nil {
+		return 0, err
+	}
+	return deleted, nil
+}
+
+// MoveCourse troca a categoria de um curso
+func (c *CourseDB) MoveCourse(ctx context.Context, courseID, categoryID string) error {
+	return c.callTx(ctx, func(q *db.Queries) error {
+		if err := courseExists(ctx, q, courseID); err != nil {
+			return err
+		}
+		if err := categoryExists(ctx, q, categoryID); err != nil {
+			return err
+		}
+		return q.UpdateCourseCategory(ctx, db.UpdateCourseCategoryParams{
+			CategoryID: categoryID,
+			ID:         courseID,
+		})
+	})
+}
+
+// MoveAllCourses leva todos os cursos de uma categoria para outra. Retorna quantos cursos mudaram.
+func (c *CourseDB) MoveAllCourses(ctx context.Context, fromCategoryID, toCategoryID string) (int64, error) {
+	var moved int64
+	err := c.callTx(ctx, func(q *db.Queries) error {
+		if err := categoryExists(ctx, q, fromCategoryID); err != nil {
+			return err
+		}
+		if err := categoryExists(ctx, q, toCategoryID); err != nil {
+			return err
+		}
+		var err error
+		moved, err = q.MoveCoursesToCategory(ctx, db.MoveCoursesToCategoryParams{
+			ToCategoryID:   toCategoryID,
+			FromCategoryID: fromCategoryID,
+		})
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+	return moved, nil
+}
+
+func categoryExists(ctx context.Context, q *db.Queries, id string) error {
+	_, err := q.GetCategoryByID(ctx, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("%s: %w", id, ErrCategoryNotFound)
+	}
+	return err
+}
+
+func courseExists(ctx context.Context, q *db.Queries, id string) error {
+	_, err := q.GetCourseByID(ctx, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("%s: %w", id, ErrCourseNotFound)
+	}
+	return err
+}
+
+func nullFloat(v *float64) sql.NullFloat64 {
+	if v == nil {
+		return sql.NullFloat64{}
+	}
+	return sql.NullFloat64{Float64: *v, Valid: true}
+}
@@ -0,0 +1,309 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ElizCarvalho/FC_PosGolang/16_SQLC/internal/db"
+	"github.com/go-sql-driver/mysql"
+	"github.com/google/uuid"
+)
+
+// defaultTestDSN aponta para o MySQL do docker-compose (make docker-up), sem banco selecionado
+const defaultTestDSN = "root:root@tcp(localhost:3306)/"
+
+// newTestCourseDB cria um banco descartável com as migrações aplicadas e o remove no fim do teste.
+// Sem MySQL acessível (TEST_MYSQL_DSN ou defaultTestDSN) o teste é pulado.
+func newTestCourseDB(t *testing.T) *CourseDB {
+	t.Helper()
+	dsn := os.Getenv("TEST_MYSQL_DSN")
+	if dsn == "" {
+		dsn = defaultTestDSN
+	}
+	cfg, err := mysql.ParseDSN(dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	admin, err := sql.Open("mysql", dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { admin.Close() })
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	if err := admin.PingContext(ctx); err != nil {
+		t.Skipf("MySQL indisponível (%v): rode make docker-up ou defina TEST_MYSQL_DSN", err)
+	}
+
+	name := "courses_test_" + strings.ReplaceAll(uuid.New().String(), "-", "")[:12]
+	if _, err := admin.Exec("CREATE DATABASE " + name); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { admin.Exec("DROP DATABASE " + name) })
+
+	cfg.DBName = name
+	cfg.MultiStatements = true // Cada migração tem mais de um comando
+	conn, err := sql.Open("mysql", cfg.FormatDSN())
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	migrations, err := filepath.Glob("../../sql/migrations/*.up.sql")
+	if err != nil || len(migrations) == 0 {
+		t.Fatalf("migrations not found: %v", err)
+	}
+	for _, path := range migrations {
+		migration, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := conn.Exec(string(migration)); err != nil {
+			t.Fatalf("%s: %v", filepath.Base(path), err)
+		}
+	}
+	return NewCourseDB(conn)
+}
+
+func createTestCategory(t *testing.T, c *CourseDB, name string) string {
+	t.Helper()
+	id := uuid.New().String()
+	if err := c.CreateCategory(context.Background(), db.CreateCategoryParams{ID: id, Name: name}); err != nil {
+		t.Fatal(err)
+	}
+	return id
+}
+
+func createTestCourse(t *testing.T, c *CourseDB, categoryID, name string, price float64) string {
+	t.Helper()
+	id := uuid.New().String()
+	err := c.CreateCourseInCategory(context.Background(), CourseParams{ID: id, Name: name, Price: price, CategoryID: categoryID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return id
+}
+
+func countCourses(t *testing.T, c *CourseDB, categoryID string) int64 {
+	t.Helper()
+	total, err := c.CountCourses(context.Background(), db.CountCoursesParams{
+		CategoryID: sql.NullString{String: categoryID, Valid: categoryID != ""},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return total
+}
+
+func TestCourseCRUD(t *testing.T) {
+	c := newTestCourseDB(t)
+	ctx := context.Background()
+	backend := createTestCategory(t, c, "Backend")
+	frontend := createTestCategory(t, c, "Frontend")
+
+	id := createTestCourse(t, c, backend, "Go Expert", 150)
+	course, err := c.GetCourse(ctx, id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if course.Name != "Go Expert" || course.Price != 150 || course.CategoryID != backend {
+		t.Fatalf("unexpected course %+v", course)
+	}
+
+	err = c.EditCourse(ctx, CourseParams{
+		ID:          id,
+		Name:        "Go Expert 2",
+		Description: sql.NullString{String: "Atualizado", Valid: true},
+		Price:       199.9,
+		CategoryID:  frontend,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	course, err = c.GetCourse(ctx, id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if course.Name != "Go Expert 2" || course.Description.String != "Atualizado" || course.Price != 199.9 || course.CategoryID != frontend {
+		t.Fatalf("course was not updated: %+v", course)
+	}
+
+	if err := c.RemoveCourse(ctx, id); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.GetCourse(ctx, id); !errors.Is(err, ErrCourseNotFound) {
+		t.Fatalf("expected ErrCourseNotFound, got %v", err)
+	}
+	if err := c.RemoveCourse(ctx, id); !errors.Is(err, ErrCourseNotFound) {
+		t.Fatalf("removing twice should return ErrCourseNotFound, got %v", err)
+	}
+	if err := c.EditCourse(ctx, CourseParams{ID: id, Name: "x", CategoryID: backend}); !errors.Is(err, ErrCourseNotFound) {
+		t.Fatalf("editing a removed course should return ErrCourseNotFound, got %v", err)
+	}
+
+	err = c.CreateCourseInCategory(ctx, CourseParams{ID: uuid.New().String(), Name: "Sem categoria", CategoryID: uuid.New().String()})
+	if !errors.Is(err, ErrCategoryNotFound) {
+		t.Fatalf("expected ErrCategoryNotFound, got %v", err)
+	}
+	if total := countCourses(t, c, ""); total != 0 {
+		t.Fatalf("expected no courses, got %d", total)
+	}
+}
+
+func TestSearchCoursesFiltersAndPaginates(t *testing.T) {
+	c := newTestCourseDB(t)
+	ctx := context.Background()
+	backend := createTestCategory(t, c, "Backend")
+	frontend := createTestCategory(t, c, "Frontend")
+	createTestCourse(t, c, backend, "A - Go", 100)
+	createTestCourse(t, c, backend, "B - Rust", 250)
+	createTestCourse(t, c, backend, "C - Java", 400)
+	createTestCourse(t, c, frontend, "D - React", 200)
+
+	names := func(page CoursePage) string {
+		var names []string
+		for _, course := range page.Courses {
+			names = append(names, course.Name)
+		}
+		return strings.Join(names, ",")
+	}
+
+	page, err := c.SearchCourses(ctx, CourseFilter{CategoryID: backend})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if page.Total != 3 || names(page) != "A - Go,B - Rust,C - Java" || page.Courses[0].CategoryName != "Backend" {
+		t.Fatalf("unexpected category page %+v", page)
+	}
+
+	minPrice, maxPrice := 150.0, 300.0
+	page, err = c.SearchCourses(ctx, CourseFilter{MinPrice: &minPrice, MaxPrice: &maxPrice})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if page.Total != 2 || names(page) != "B - Rust,D - React" {
+		t.Fatalf("unexpected price range page %+v", page)
+	}
+
+	page, err = c.SearchCourses(ctx, CourseFilter{Limit: 2, Offset: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if page.Total != 4 || names(page) != "C - Java,D - React" {
+		t.Fatalf("unexpected second page %+v", page)
+	}
+
+	page, err = c.SearchCourses(ctx, CourseFilter{Limit: 1000})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if page.Limit != maxPageSize || len(page.Courses) != 4 {
+		t.Fatalf("limit should be capped at %d, got %+v", maxPageSize, page)
+	}
+
+	if _, err := c.SearchCourses(ctx, CourseFilter{MinPrice: &maxPrice, MaxPrice: &minPrice}); !errors.Is(err, ErrInvalidFilter) {
+		t.Fatalf("expected ErrInvalidFilter, got %v", err)
+	}
+}
+
+func TestDeleteCategoryCascade(t *testing.T) {
+	c := newTestCourseDB(t)
+	ctx := context.Background()
+	backend := createTestCategory(t, c, "Backend")
+	frontend := createTestCategory(t, c, "Frontend")
+	createTestCourse(t, c, backend, "Go", 100)
+	createTestCourse(t, c, backend, "Rust", 200)
+	kept := createTestCourse(t, c, frontend, "React", 300)
+
+	deleted, err := c.DeleteCategoryCascade(ctx, backend)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if deleted != 2 {
+		t.Fatalf("expected 2 deleted courses, got %d", deleted)
+	}
+	if _, err := c.GetCategoryByID(ctx, backend); !errors.Is(err, sql.ErrNoRows) {
+		t.Fatalf("category should be gone, got %v", err)
+	}
+	if _, err := c.GetCourse(ctx, kept); err != nil {
+		t.Fatalf("courses from other categories should stay: %v", err)
+	}
+
+	if _, err := c.DeleteCategoryCascade(ctx, backend); !errors.Is(err, ErrCategoryNotFound) {
+		t.Fatalf("expected ErrCategoryNotFound, got %v", err)
+	}
+}
+
+func TestMoveCourses(t *testing.T) {
+	c := newTestCourseDB(t)
+	ctx := context.Background()
+	backend := createTestCategory(t, c, "Backend")
+	frontend := createTestCategory(t, c, "Frontend")
+	goCourse := createTestCourse(t, c, backend, "Go", 100)
+	createTestCourse(t, c, backend, "Rust", 200)
+
+	if err := c.MoveCourse(ctx, goCourse, frontend); err != nil {
+		t.Fatal(err)
+	}
+	if course, _ := c.GetCourse(ctx, goCourse); course.CategoryID != frontend {
+		t.Fatalf("course should be in %s, got %s", frontend, course.CategoryID)
+	}
+
+	moved, err := c.MoveAllCourses(ctx, backend, frontend)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if moved != 1 || countCourses(t, c, backend) != 0 || countCourses(t, c, frontend) != 2 {
+		t.Fatalf("expected 1 course moved to frontend, got %d", moved)
+	}
+
+	// Categoria de destino inexistente: nada muda
+	unknown := uuid.New().String()
+	if _, err := c.MoveAllCourses(ctx, frontend, unknown); !errors.Is(err, ErrCategoryNotFound) {
+		t.Fatalf("expected ErrCategoryNotFound, got %v", err)
+	}
+	if err := c.MoveCourse(ctx, goCourse, unknown); !errors.Is(err, ErrCategoryNotFound) {
+		t.Fatalf("expected ErrCategoryNotFound, got %v", err)
+	}
+	if countCourses(t, c, frontend) != 2 {
+		t.Fatal("failed moves should not change any course")
+	}
+}
+
+func TestCallTxRollsBackOnError(t *testing.T) {
+	c := newTestCourseDB(t)
+	ctx := context.Background()
+	categoryID := uuid.New().String()
+	errFail := errors.New("fail after insert")
+
+	err := c.callTx(ctx, func(q *db.Queries) error {
+		if err := q.CreateCategory(ctx, db.CreateCategoryParams{ID: categoryID, Name: "Temporária"}); err != nil {
+			return err
+		}
+		return errFail
+	})
+	if !errors.Is(err, errFail) {
+		t.Fatalf("expected the function error, got %v", err)
+	}
+	if _, err := c.GetCategoryByID(ctx, categoryID); !errors.Is(err, sql.ErrNoRows) {
+		t.Fatalf("category should have been rolled back, got %v", err)
+	}
+
+	// Um ID maior que varchar(36) falha no modo estrito do MySQL e desfaz a categoria criada antes
+	err = c.CreateCourseAndCategory(ctx,
+		CategoryParams{ID: categoryID, Name: "Backend"},
+		CourseParams{ID: strings.Repeat("x", 40), Name: "ID grande demais"})
+	if err == nil {
+		t.Fatal("expected an error for an invalid course id")
+	}
+	if _, err := c.GetCategoryByID(ctx, categoryID); !errors.Is(err, sql.ErrNoRows) {
+		t.Fatalf("category should have been rolled back with the course, got %v", err)
+	}
+}
@@ -3,8 +3,11 @@ module github.com/ElizCarvalho/FC_PosGolang/16_SQLC
 go 1.23.5
 
 require (
+	github.com/ElizCarvalho/FC_PosGolang/pkg/txn v0.0.0
 	github.com/go-sql-driver/mysql v1.9.3
 	github.com/google/uuid v1.6.0
 )
 
 require filippo.io/edwards25519 v1.1.0 // indirect
+
+replace github.com/ElizCarvalho/FC_PosGolang/pkg/txn => ../pkg/txn
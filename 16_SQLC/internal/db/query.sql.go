@@ -10,6 +10,33 @@ import (
 	"database/sql"
 )
 
+const countCourses = `-- name: CountCourses :one
+SELECT COUNT(*) FROM courses c
+WHERE (? IS NULL OR c.category_id = ?)
+  AND (? IS NULL OR c.price >= ?)
+  AND (? IS NULL OR c.price <= ?)
+`
+
+type CountCoursesParams struct {
+	CategoryID sql.NullString
+	MinPrice   sql.NullFloat64
+	MaxPrice   sql.NullFloat64
+}
+
+func (q *Queries) CountCourses(ctx context.Context, arg CountCoursesParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countCourses,
+		arg.CategoryID,
+		arg.CategoryID,
+		arg.MinPrice,
+		arg.MinPrice,
+		arg.MaxPrice,
+		arg.MaxPrice,
+	)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
 const createCategory = `-- name: CreateCategory :exec
 INSERT INTO categories (id, name, description) VALUES (?, ?, ?)
 `
@@ -57,6 +84,27 @@ func (q *Queries) DeleteCategory(ctx context.Context, id string) error {
 	return err
 }
 
+const deleteCourse = `-- name: DeleteCourse :exec
+DELETE FROM courses WHERE id = ?
+`
+
+func (q *Queries) DeleteCourse(ctx context.Context, id string) error {
+	_, err := q.db.ExecContext(ctx, deleteCourse, id)
+	return err
+}
+
+const deleteCoursesByCategory = `-- name: DeleteCoursesByCategory :execrows
+DELETE FROM courses WHERE category_id = ?
+`
+
+func (q *Queries) DeleteCoursesByCategory(ctx context.Context, categoryID string) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteCoursesByCategory, categoryID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
 const getCategoryByID = `-- name: GetCategoryByID :one
 SELECT id, name, description FROM categories WHERE id = ?
 `
@@ -68,6 +116,23 @@ func (q *Queries) GetCategoryByID(ctx context.Context, id string) (Category, err
 	return i, err
 }
 
+const getCourseByID = `-- name: GetCourseByID :one
+SELECT id, category_id, name, description, price FROM courses WHERE id = ?
+`
+
+func (q *Queries) GetCourseByID(ctx context.Context, id string) (Course, error) {
+	row := q.db.QueryRowContext(ctx, getCourseByID, id)
+	var i Course
+	err := row.Scan(
+		&i.ID,
+		&i.CategoryID,
+		&i.Name,
+		&i.Description,
+		&i.Price,
+	)
+	return i, err
+}
+
 const listCategories = `-- name: ListCategories :many
 SELECT id, name, description FROM categories
 `
@@ -98,8 +163,21 @@ func (q *Queries) ListCategories(ctx context.Context) ([]Category, error) {
 const listCourses = `-- name: ListCourses :many
 SELECT c.id, c.category_id, c.name, c.description, c.price, ca.name as category_name
 FROM courses c JOIN categories ca ON c.category_id = ca.id
+WHERE (? IS NULL OR c.category_id = ?)
+  AND (? IS NULL OR c.price >= ?)
+  AND (? IS NULL OR c.price <= ?)
+ORDER BY c.name, c.id
+LIMIT ? OFFSET ?
 `
 
+type ListCoursesParams struct {
+	CategoryID sql.NullString
+	MinPrice   sql.NullFloat64
+	MaxPrice   sql.NullFloat64
+	Limit      int32
+	Offset     int32
+}
+
 type ListCoursesRow struct {
 	ID           string
 	CategoryID   string
@@ -109,8 +187,17 @@ type ListCoursesRow struct {
 	CategoryName string
 }
 
-func (q *Queries) ListCourses(ctx context.Context) ([]ListCoursesRow, error) {
-	rows, err := q.db.QueryContext(ctx, listCourses)
+func (q *Queries) ListCourses(ctx context.Context, arg ListCoursesParams) ([]ListCoursesRow, error) {
+	rows, err := q.db.QueryContext(ctx, listCourses,
+		arg.CategoryID,
+		arg.CategoryID,
+		arg.MinPrice,
+		arg.MinPrice,
+		arg.MaxPrice,
+		arg.MaxPrice,
+		arg.Limit,
+		arg.Offset,
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -139,6 +226,23 @@ func (q *Queries) ListCourses(ctx context.Context) ([]ListCoursesRow, error) {
 	return items, nil
 }
 
+const moveCoursesToCategory = `-- name: MoveCoursesToCategory :execrows
+UPDATE courses SET category_id = ? WHERE category_id = ?
+`
+
+type MoveCoursesToCategoryParams struct {
+	ToCategoryID   string
+	FromCategoryID string
+}
+
+func (q *Queries) MoveCoursesToCategory(ctx context.Context, arg MoveCoursesToCategoryParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, moveCoursesToCategory, arg.ToCategoryID, arg.FromCategoryID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
 const updateCategory = `-- name: UpdateCategory :exec
 UPDATE categories SET name = ?, description = ? WHERE id = ?
 `
@@ -153,3 +257,40 @@ func (q *Queries) UpdateCategory(ctx context.Context, arg UpdateCategoryParams)
 	_, err := q.db.ExecContext(ctx, updateCategory, arg.Name, arg.Description, arg.ID)
 	return err
 }
+
+const updateCourse = `-- name: UpdateCourse :exec
+UPDATE courses SET name = ?, description = ?, category_id = ?, price = ? WHERE id = ?
+`
+
+type UpdateCourseParams struct {
+	Name        string
+	Description sql.NullString
+	CategoryID  string
+	Price       float64
+	ID          string
+}
+
+func (q *Queries) UpdateCourse(ctx context.Context, arg UpdateCourseParams) error {
+	_, err := q.db.ExecContext(ctx, updateCourse,
+		arg.Name,
+		arg.Description,
+		arg.CategoryID,
+		arg.Price,
+		arg.ID,
+	)
+	return err
+}
+
+const updateCourseCategory = `-- name: UpdateCourseCategory :exec
+UPDATE courses SET category_id = ? WHERE id = ?
+`
+
+type UpdateCourseCategoryParams struct {
+	CategoryID string
+	ID         string
+}
+
+func (q *Queries) UpdateCourseCategory(ctx context.Context, arg UpdateCourseCategoryParams) error {
+	_, err := q.db.ExecContext(ctx, updateCourseCategory, arg.CategoryID, arg.ID)
+	return err
+}
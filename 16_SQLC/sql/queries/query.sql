@@ -18,13 +18,33 @@ INSERT INTO courses (id, name, description, category_id, price) VALUES (?, ?, ?,
 
 -- name: ListCourses :many
 SELECT c.*, ca.name as category_name
-FROM courses c JOIN categories ca ON c.category_id = ca.id;
+FROM courses c JOIN categories ca ON c.category_id = ca.id
+WHERE (sqlc.narg('category_id') IS NULL OR c.category_id = sqlc.narg('category_id'))
+  AND (sqlc.narg('min_price') IS NULL OR c.price >= sqlc.narg('min_price'))
+  AND (sqlc.narg('max_price') IS NULL OR c.price <= sqlc.narg('max_price'))
+ORDER BY c.name, c.id
+LIMIT ? OFFSET ?;
 
--- -- name: GetCourseByID :one
--- SELECT * FROM courses WHERE id = ?;
+-- name: CountCourses :one
+SELECT COUNT(*) FROM courses c
+WHERE (sqlc.narg('category_id') IS NULL OR c.category_id = sqlc.narg('category_id'))
+  AND (sqlc.narg('min_price') IS NULL OR c.price >= sqlc.narg('min_price'))
+  AND (sqlc.narg('max_price') IS NULL OR c.price <= sqlc.narg('max_price'));
 
--- -- name: UpdateCourse :exec
--- UPDATE courses SET name = ?, description = ?, category_id = ?, price = ? WHERE id = ?;
+-- name: GetCourseByID :one
+SELECT * FROM courses WHERE id = ?;
 
--- -- name: DeleteCourse :exec
--- DELETE FROM courses WHERE id = ?;
\ No newline at end of file
+-- name: UpdateCourse :exec
+UPDATE courses SET name = ?, description = ?, category_id = ?, price = ? WHERE id = ?;
+
+-- name: DeleteCourse :exec
+DELETE FROM courses WHERE id = ?;
+
+-- name: DeleteCoursesByCategory :execrows
+DELETE FROM courses WHERE category_id = ?;
+
+-- name: UpdateCourseCategory :exec
+UPDATE courses SET category_id = ? WHERE id = ?;
+
+-- name: MoveCoursesToCategory :execrows
+UPDATE courses SET category_id = sqlc.arg(to_category_id) WHERE category_id = sqlc.arg(from_category_id);
\ No newline at end of file
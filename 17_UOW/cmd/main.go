@@ -6,9 +6,12 @@ import (
 	"fmt"
 	"log"
 
+	"github.com/ElizCarvalho/FC_PosGolang/17_UOW/internal/db"
+	"github.com/ElizCarvalho/FC_PosGolang/17_UOW/internal/event/handler"
 	"github.com/ElizCarvalho/FC_PosGolang/17_UOW/internal/repository"
 	"github.com/ElizCarvalho/FC_PosGolang/17_UOW/internal/usecase"
 	"github.com/ElizCarvalho/FC_PosGolang/17_UOW/pkg/uow"
+	"github.com/ElizCarvalho/fcutils/pkg/events"
 	_ "github.com/go-sql-driver/mysql"
 )
 
@@ -34,16 +37,23 @@ func main() {
 	// Configuração do UOW
 	uowInstance := uow.NewUow(ctx, dbConn)
 
-	// Registra os repositórios no UOW
-	uowInstance.Register("CategoryRepository", func(tx *sql.Tx) interface{} {
+	// Eventos de domínio registrados na transação são publicados depois do commit
+	eventDispatcher := events.NewEventDispatcher()
+	logHandler := handler.NewLogHandler()
+	eventDispatcher.Register("CategoryCreated", logHandler)
+	eventDispatcher.Register("CourseCreated", logHandler)
+	uowInstance.EventDispatcher = eventDispatcher
+
+	// Registra os repositórios no UOW: cada fábrica recebe a transação aberta pelo UOW
+	uow.Register(uowInstance, usecase.CategoryRepositoryName, func(tx *sql.Tx) repository.CategoryRepositoryInterface {
 		repo := repository.NewCategoryRepository(dbConn)
-		repo.Queries = repository.NewCategoryRepository(dbConn).Queries
+		repo.Queries = db.New(tx)
 		return repo
 	})
 
-	uowInstance.Register("CourseRepository", func(tx *sql.Tx) interface{} {
+	uow.Register(uowInstance, usecase.CourseRepositoryName, func(tx *sql.Tx) repository.CourseRepositoryInterface {
 		repo := repository.NewCourseRepository(dbConn)
-		repo.Queries = repository.NewCourseRepository(dbConn).Queries
+		repo.Queries = db.New(tx)
 		return repo
 	})
 
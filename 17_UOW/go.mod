@@ -3,8 +3,11 @@ module github.com/ElizCarvalho/FC_PosGolang/17_UOW
 go 1.23.5
 
 require (
+	github.com/ElizCarvalho/FC_PosGolang/pkg/txn v0.0.0
+	github.com/ElizCarvalho/fcutils v0.0.0
 	github.com/go-sql-driver/mysql v1.7.0
-	github.com/stretchr/testify v1.8.1
+	github.com/mattn/go-sqlite3 v1.14.32
+	github.com/stretchr/testify v1.11.1
 )
 
 require (
@@ -12,3 +15,8 @@ require (
 	github.com/pmezard/go-difflib v1.0.0 // indirect
 	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
+
+replace (
+	github.com/ElizCarvalho/FC_PosGolang/pkg/txn => ../pkg/txn
+	github.com/ElizCarvalho/fcutils => ../9_Eventos
+)
@@ -7,20 +7,15 @@ package db
 
 import (
 	"context"
+	"database/sql"
 )
 
-const createCategory = `-- name: CreateCategory :exec
-INSERT INTO categories (id, name) VALUES (?, ?)
+const createCategory = `-- name: CreateCategory :execresult
+INSERT INTO categories (name) VALUES (?)
 `
 
-type CreateCategoryParams struct {
-	ID   int32
-	Name string
-}
-
-func (q *Queries) CreateCategory(ctx context.Context, arg CreateCategoryParams) error {
-	_, err := q.db.ExecContext(ctx, createCategory, arg.ID, arg.Name)
-	return err
+func (q *Queries) CreateCategory(ctx context.Context, name string) (sql.Result, error) {
+	return q.db.ExecContext(ctx, createCategory, name)
 }
 
 const createCourse = `-- name: CreateCourse :exec
@@ -0,0 +1,33 @@
+package event
+
+import (
+	"time"
+
+	"github.com/ElizCarvalho/FC_PosGolang/17_UOW/internal/entity"
+)
+
+type CategoryCreated struct {
+	Name     string
+	Payload  interface{}
+	DateTime time.Time
+}
+
+func NewCategoryCreated(category entity.Category) *CategoryCreated {
+	return &CategoryCreated{
+		Name:     "CategoryCreated",
+		Payload:  category,
+		DateTime: time.Now(),
+	}
+}
+
+func (e *CategoryCreated) GetName() string {
+	return e.Name
+}
+
+func (e *CategoryCreated) GetPayload() interface{} {
+	return e.Payload
+}
+
+func (e *CategoryCreated) GetDateTime() time.Time {
+	return e.DateTime
+}
@@ -0,0 +1,33 @@
+package event
+
+import (
+	"time"
+
+	"github.com/ElizCarvalho/FC_PosGolang/17_UOW/internal/entity"
+)
+
+type CourseCreated struct {
+	Name     string
+	Payload  interface{}
+	DateTime time.Time
+}
+
+func NewCourseCreated(course entity.Course) *CourseCreated {
+	return &CourseCreated{
+		Name:     "CourseCreated",
+		Payload:  course,
+		DateTime: time.Now(),
+	}
+}
+
+func (e *CourseCreated) GetName() string {
+	return e.Name
+}
+
+func (e *CourseCreated) GetPayload() interface{} {
+	return e.Payload
+}
+
+func (e *CourseCreated) GetDateTime() time.Time {
+	return e.DateTime
+}
@@ -0,0 +1,20 @@
+package handler
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/ElizCarvalho/fcutils/pkg/events"
+)
+
+// LogHandler imprime os eventos publicados pelo UOW
+type LogHandler struct{}
+
+func NewLogHandler() *LogHandler {
+	return &LogHandler{}
+}
+
+func (h *LogHandler) Handle(event events.EventInterface, wg *sync.WaitGroup) {
+	defer wg.Done()
+	fmt.Printf("📣 %s em %s: %+v\n", event.GetName(), event.GetDateTime().Format("15:04:05"), event.GetPayload())
+}
@@ -9,7 +9,8 @@ import (
 )
 
 type CategoryRepositoryInterface interface {
-	Insert(ctx context.Context, category entity.Category) error
+	// Insert grava a categoria e devolve o id gerado pelo banco
+	Insert(ctx context.Context, category entity.Category) (int, error)
 }
 
 type CategoryRepository struct {
@@ -24,8 +25,14 @@ func NewCategoryRepository(dtb *sql.DB) *CategoryRepository {
 	}
 }
 
-func (r *CategoryRepository) Insert(ctx context.Context, category entity.Category) error {
-	return r.Queries.CreateCategory(ctx, db.CreateCategoryParams{
-		Name: category.Name,
-	})
+func (r *CategoryRepository) Insert(ctx context.Context, category entity.Category) (int, error) {
+	result, err := r.Queries.CreateCategory(ctx, category.Name)
+	if err != nil {
+		return 0, err
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+	return int(id), nil
 }
@@ -30,7 +30,7 @@ func (a *AddCourseUseCase) Execute(ctx context.Context, input InputUseCase) erro
 		Name: input.CategoryName,
 	}
 
-	err := a.CategoryRepository.Insert(ctx, category)
+	_, err := a.CategoryRepository.Insert(ctx, category)
 	if err != nil {
 		return err
 	}
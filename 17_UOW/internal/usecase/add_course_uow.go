@@ -4,10 +4,17 @@ import (
 	"context"
 
 	"github.com/ElizCarvalho/FC_PosGolang/17_UOW/internal/entity"
+	"github.com/ElizCarvalho/FC_PosGolang/17_UOW/internal/event"
 	"github.com/ElizCarvalho/FC_PosGolang/17_UOW/internal/repository"
 	"github.com/ElizCarvalho/FC_PosGolang/17_UOW/pkg/uow"
 )
 
+// Nomes com que os repositórios são registrados no UOW
+const (
+	CategoryRepositoryName = "CategoryRepository"
+	CourseRepositoryName   = "CourseRepository"
+)
+
 type InputUseCaseUow struct {
 	CategoryName     string
 	CourseName       string
@@ -30,38 +37,42 @@ func (a *AddCourseUseCaseUow) Execute(ctx context.Context, input InputUseCase) e
 		category := entity.Category{
 			Name: input.CategoryName,
 		}
-		repoCategory := a.getCategoryRepository(ctx)
-		err := repoCategory.Insert(ctx, category)
+		repoCategory, err := a.getCategoryRepository(ctx)
+		if err != nil {
+			return err
+		}
+		category.ID, err = repoCategory.Insert(ctx, category)
+		if err != nil {
+			return err
+		}
+		err = uow.AddEvent(event.NewCategoryCreated(category))
 		if err != nil {
 			return err
 		}
 
+		// o curso entra na categoria criada nesta mesma transação
 		course := entity.Course{
 			Name:       input.CourseName,
-			CategoryID: input.CourseCategoryID,
+			CategoryID: category.ID,
 		}
 
-		repoCourse := a.getCourseRepository(ctx)
+		repoCourse, err := a.getCourseRepository(ctx)
+		if err != nil {
+			return err
+		}
 		err = repoCourse.Insert(ctx, course)
 		if err != nil {
 			return err
 		}
-		return nil
+		// Publicados só depois do commit; se algo falhar, o rollback descarta os dois
+		return uow.AddEvent(event.NewCourseCreated(course))
 	})
 }
 
-func (a *AddCourseUseCaseUow) getCategoryRepository(ctx context.Context) repository.CategoryRepositoryInterface {
-	repo, err := a.Uow.GetRepository(ctx, "CategoryRepository")
-	if err != nil {
-		panic(err)
-	}
-	return repo.(repository.CategoryRepositoryInterface)
+func (a *AddCourseUseCaseUow) getCategoryRepository(ctx context.Context) (repository.CategoryRepositoryInterface, error) {
+	return uow.GetRepository[repository.CategoryRepositoryInterface](ctx, a.Uow, CategoryRepositoryName)
 }
 
-func (a *AddCourseUseCaseUow) getCourseRepository(ctx context.Context) repository.CourseRepositoryInterface {
-	repo, err := a.Uow.GetRepository(ctx, "CourseRepository")
-	if err != nil {
-		panic(err)
-	}
-	return repo.(repository.CourseRepositoryInterface)
+func (a *AddCourseUseCaseUow) getCourseRepository(ctx context.Context) (repository.CourseRepositoryInterface, error) {
+	return uow.GetRepository[repository.CourseRepositoryInterface](ctx, a.Uow, CourseRepositoryName)
 }
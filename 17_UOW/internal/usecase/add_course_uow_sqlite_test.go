@@ -0,0 +1,132 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/ElizCarvalho/FC_PosGolang/17_UOW/internal/entity"
+	"github.com/ElizCarvalho/FC_PosGolang/17_UOW/internal/repository"
+	"github.com/ElizCarvalho/FC_PosGolang/17_UOW/pkg/uow"
+	"github.com/ElizCarvalho/fcutils/pkg/events"
+	"github.com/stretchr/testify/assert"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// newSQLiteDB cria o schema num SQLite em memória, com chaves estrangeiras ligadas
+func newSQLiteDB(t *testing.T) *sql.DB {
+	t.Helper()
+	dbt, err := sql.Open("sqlite3", "file::memory:?_foreign_keys=on")
+	assert.NoError(t, err)
+	dbt.SetMaxOpenConns(1) // Cada conexão teria seu próprio banco em memória
+	t.Cleanup(func() { dbt.Close() })
+
+	_, err = dbt.Exec("CREATE TABLE categories (id INTEGER PRIMARY KEY, name varchar(255) NOT NULL);")
+	assert.NoError(t, err)
+	_, err = dbt.Exec("CREATE TABLE courses (id INTEGER PRIMARY KEY, name varchar(255) NOT NULL, category_id INTEGER NOT NULL, FOREIGN KEY (category_id) REFERENCES categories(id));")
+	assert.NoError(t, err)
+	return dbt
+}
+
+// eventRecorder guarda os eventos recebidos do dispatcher
+type eventRecorder struct {
+	mu     sync.Mutex
+	events []events.EventInterface
+}
+
+func (r *eventRecorder) Handle(event events.EventInterface, wg *sync.WaitGroup) {
+	defer wg.Done()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = append(r.events, event)
+}
+
+func newRecordingDispatcher(t *testing.T, u *uow.Uow) *eventRecorder {
+	t.Helper()
+	recorder := &eventRecorder{}
+	dispatcher := events.NewEventDispatcher()
+	assert.NoError(t, dispatcher.Register("CategoryCreated", recorder))
+	assert.NoError(t, dispatcher.Register("CourseCreated", recorder))
+	u.EventDispatcher = dispatcher
+	return recorder
+}
+
+func countRows(t *testing.T, dbt *sql.DB, table string) int {
+	t.Helper()
+	var total int
+	assert.NoError(t, dbt.QueryRow("SELECT COUNT(*) FROM "+table).Scan(&total))
+	return total
+}
+
+func TestAddCourseUowSQLite(t *testing.T) {
+	dbt := newSQLiteDB(t)
+	ctx := context.Background()
+	u := uow.NewUow(ctx, dbt)
+	registerRepositories(u, dbt)
+	recorder := newRecordingDispatcher(t, u)
+
+	// O curso entra na categoria criada na mesma transação, com o id gerado pelo banco
+	input := InputUseCase{
+		CategoryName: "Category 1",
+		CourseName:   "Course 1",
+	}
+
+	err := NewAddCourseUseCaseUow(u).Execute(ctx, input)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, countRows(t, dbt, "categories"))
+	assert.Equal(t, 1, countRows(t, dbt, "courses"))
+
+	assert.Len(t, recorder.events, 2)
+	assert.Equal(t, "CategoryCreated", recorder.events[0].GetName())
+	assert.Equal(t, entity.Category{ID: 1, Name: "Category 1"}, recorder.events[0].GetPayload())
+	assert.Equal(t, "CourseCreated", recorder.events[1].GetName())
+	assert.Equal(t, entity.Course{Name: "Course 1", CategoryID: 1}, recorder.events[1].GetPayload())
+
+	var categoryID int
+	assert.NoError(t, dbt.QueryRow("SELECT category_id FROM courses").Scan(&categoryID))
+	assert.Equal(t, 1, categoryID)
+}
+
+// failingCourseRepository falha no insert do curso, depois que a categoria já foi gravada
+type failingCourseRepository struct {
+	err error
+}
+
+func (r failingCourseRepository) Insert(ctx context.Context, course entity.Course) error {
+	return r.err
+}
+
+func TestAddCourseUowSQLiteRollsBack(t *testing.T) {
+	dbt := newSQLiteDB(t)
+	ctx := context.Background()
+	u := uow.NewUow(ctx, dbt)
+	registerRepositories(u, dbt)
+	recorder := newRecordingDispatcher(t, u)
+
+	errInsert := errors.New("insert failed")
+	uow.Register(u, CourseRepositoryName, func(tx *sql.Tx) repository.CourseRepositoryInterface {
+		return failingCourseRepository{err: errInsert}
+	})
+
+	err := NewAddCourseUseCaseUow(u).Execute(ctx, InputUseCase{CategoryName: "Category 1", CourseName: "Course 1"})
+	assert.ErrorIs(t, err, errInsert)
+	assert.Equal(t, 0, countRows(t, dbt, "categories"), "the category must be rolled back with the course")
+	assert.Equal(t, 0, countRows(t, dbt, "courses"))
+	assert.Nil(t, u.Tx)
+	assert.Empty(t, recorder.events, "CategoryCreated must not leak from the rolled back work")
+}
+
+func TestAddCourseUowMissingRepository(t *testing.T) {
+	dbt := newSQLiteDB(t)
+	ctx := context.Background()
+	u := uow.NewUow(ctx, dbt)
+	registerRepositories(u, dbt)
+	u.UnRegister(CourseRepositoryName)
+
+	err := NewAddCourseUseCaseUow(u).Execute(ctx, InputUseCase{CategoryName: "Category 1", CourseName: "Course 1"})
+	assert.ErrorIs(t, err, uow.ErrRepositoryNotFound)
+	assert.Equal(t, 0, countRows(t, dbt, "categories"))
+}
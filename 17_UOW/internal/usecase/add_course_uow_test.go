@@ -5,8 +5,6 @@ import (
 	"database/sql"
 	"testing"
 
-	"github.com/ElizCarvalho/FC_PosGolang/17_UOW/internal/db"
-	"github.com/ElizCarvalho/FC_PosGolang/17_UOW/internal/repository"
 	"github.com/ElizCarvalho/FC_PosGolang/17_UOW/pkg/uow"
 	"github.com/stretchr/testify/assert"
 
@@ -26,17 +24,7 @@ func TestAddCourseUow(t *testing.T) {
 	ctx := context.Background()
 	uow := uow.NewUow(ctx, dbt)
 
-	uow.Register("CategoryRepository", func(tx *sql.Tx) interface{} {
-		repo := repository.NewCategoryRepository(dbt)
-		repo.Queries = db.New(tx)
-		return repo
-	})
-
-	uow.Register("CourseRepository", func(tx *sql.Tx) interface{} {
-		repo := repository.NewCourseRepository(dbt)
-		repo.Queries = db.New(tx)
-		return repo
-	})
+	registerRepositories(uow, dbt)
 
 	input := InputUseCase{
 		CategoryName:     "Category 1", // ID->1
@@ -0,0 +1,24 @@
+package usecase
+
+import (
+	"database/sql"
+
+	"github.com/ElizCarvalho/FC_PosGolang/17_UOW/internal/db"
+	"github.com/ElizCarvalho/FC_PosGolang/17_UOW/internal/repository"
+	"github.com/ElizCarvalho/FC_PosGolang/17_UOW/pkg/uow"
+)
+
+// registerRepositories registra os repositórios usando as queries da transação do UOW.
+// Fica num arquivo próprio porque é usado pelos testes com MySQL e com SQLite.
+func registerRepositories(u uow.UowInterface, dbt *sql.DB) {
+	uow.Register(u, CategoryRepositoryName, func(tx *sql.Tx) repository.CategoryRepositoryInterface {
+		repo := repository.NewCategoryRepository(dbt)
+		repo.Queries = db.New(tx)
+		return repo
+	})
+	uow.Register(u, CourseRepositoryName, func(tx *sql.Tx) repository.CourseRepositoryInterface {
+		repo := repository.NewCourseRepository(dbt)
+		repo.Queries = db.New(tx)
+		return repo
+	})
+}
@@ -5,8 +5,24 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"reflect"
+
+	"github.com/ElizCarvalho/FC_PosGolang/pkg/txn"
+	"github.com/ElizCarvalho/fcutils/pkg/events"
+)
+
+var (
+	ErrRepositoryNotFound = errors.New("repository not registered")
+	ErrRepositoryType     = errors.New("repository has an unexpected type")
 )
 
+// EventDispatcher é a parte do events.EventDispatcher que o UOW usa
+type EventDispatcher interface {
+	Dispatch(event events.EventInterface) error
+}
+
+// RepositoryFactory é a forma guardada no registro; use Register e GetRepository
+// para trabalhar com o tipo concreto do repositório
 type RepositoryFactory func(tx *sql.Tx) interface{}
 
 type UowInterface interface {
@@ -16,18 +32,26 @@ type UowInterface interface {
 	CommitOrRollback() error
 	Rollback() error
 	UnRegister(name string)
+	AfterCommit(fn func()) error
+	AddEvent(evts ...events.EventInterface) error
 }
 
 type Uow struct {
 	Db           *sql.DB
 	Tx           *sql.Tx
 	Repositories map[string]RepositoryFactory
+	TxOptions    []txn.Option // Isolamento, somente leitura e novas tentativas usados pelo Do
+	// EventDispatcher publica os eventos registrados com AddEvent depois do commit; sem ele são descartados
+	EventDispatcher EventDispatcher
+	current         *txn.Tx
+	pending         []events.EventInterface
 }
 
-func NewUow(ctx context.Context, db *sql.DB) *Uow {
+func NewUow(ctx context.Context, db *sql.DB, opts ...txn.Option) *Uow {
 	return &Uow{
 		Db:           db,
 		Repositories: make(map[string]RepositoryFactory),
+		TxOptions:    opts,
 	}
 }
 
@@ -40,6 +64,10 @@ func (u *Uow) UnRegister(name string) {
 }
 
 func (u *Uow) GetRepository(ctx context.Context, name string) (interface{}, error) {
+	factory, ok := u.Repositories[name]
+	if !ok || factory == nil {
+		return nil, fmt.Errorf("%s: %w", name, ErrRepositoryNotFound)
+	}
 	if u.Tx == nil {
 		tx, err := u.Db.BeginTx(ctx, nil)
 		if err != nil {
@@ -47,30 +75,83 @@ func (u *Uow) GetRepository(ctx context.Context, name string) (interface{}, erro
 		}
 		u.Tx = tx
 	}
-	repo := u.Repositories[name](u.Tx)
+	repo := factory(u.Tx)
 	return repo, nil
 }
 
+// Register registra uma fábrica tipada: GetRepository com o mesmo R devolve o repositório sem type assertion
+func Register[R any](u UowInterface, name string, factory func(tx *sql.Tx) R) {
+	u.Register(name, func(tx *sql.Tx) interface{} {
+		return factory(tx)
+	})
+}
+
+// GetRepository busca o repositório pelo nome já como R (normalmente a interface do repositório).
+// Nome não registrado retorna ErrRepositoryNotFound e tipo diferente de R, ErrRepositoryType.
+func GetRepository[R any](ctx context.Context, u UowInterface, name string) (R, error) {
+	var zero R
+	repo, err := u.GetRepository(ctx, name)
+	if err != nil {
+		return zero, err
+	}
+	typed, ok := repo.(R)
+	if !ok {
+		return zero, fmt.Errorf("%s: %w: got %T, want %s", name, ErrRepositoryType, repo, reflect.TypeFor[R]())
+	}
+	return typed, nil
+}
+
+// Do executa fn numa transação. Em deadlock ou lock wait timeout fn roda de novo
+// numa transação nova, por isso efeitos fora do banco devem ir para AfterCommit ou AddEvent.
 func (u *Uow) Do(ctx context.Context, fn func(Uow *Uow) error) error {
 	if u.Tx != nil {
 		return fmt.Errorf("transaction already started")
 	}
 
-	tx, err := u.Db.BeginTx(ctx, nil)
+	err := txn.Run(ctx, u.Db, func(tx *txn.Tx) error {
+		u.Tx, u.current = tx.Tx, tx //esse é o cara que vai fazer o begin da transação
+		u.pending = nil             // Eventos de uma tentativa que falhou não valem
+		defer func() { u.Tx, u.current = nil, nil }()
+		return fn(u)
+	}, u.TxOptions...)
+
+	pending := u.pending
+	u.pending = nil
 	if err != nil {
 		return err
 	}
+	u.publish(pending)
+	return nil
+}
 
-	u.Tx = tx //esse é o cara que vai fazer o begin da transação
-	err = fn(u)
-	if err != nil {
-		errRb := u.Rollback()
-		if errRb != nil {
-			return fmt.Errorf("original error: %s, rollback error: %s", err.Error(), errRb.Error())
-		}
-		return err
+// publish entrega os eventos em ordem. O EventDispatcher espera todos os handlers
+// e não devolve erro: os handlers não têm como falhar depois do commit.
+func (u *Uow) publish(pending []events.EventInterface) {
+	if u.EventDispatcher == nil {
+		return
 	}
-	return u.CommitOrRollback()
+	for _, event := range pending {
+		u.EventDispatcher.Dispatch(event)
+	}
+}
+
+// AddEvent registra eventos de domínio na transação aberta pelo Do. Eles só são
+// publicados depois do commit e são descartados em rollback.
+func (u *Uow) AddEvent(evts ...events.EventInterface) error {
+	if u.current == nil {
+		return errors.New("no transaction started by Do")
+	}
+	u.pending = append(u.pending, evts...)
+	return nil
+}
+
+// AfterCommit agenda fn para depois do commit da transação aberta pelo Do
+func (u *Uow) AfterCommit(fn func()) error {
+	if u.current == nil {
+		return errors.New("no transaction started by Do")
+	}
+	u.current.AfterCommit(fn)
+	return nil
 }
 
 func (u *Uow) Rollback() error {
@@ -0,0 +1,152 @@
+package uow
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/ElizCarvalho/FC_PosGolang/pkg/txn"
+	"github.com/ElizCarvalho/fcutils/pkg/events"
+	"github.com/go-sql-driver/mysql"
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+)
+
+type nameRepository interface {
+	Insert(ctx context.Context, name string) error
+}
+
+type sqlNameRepository struct {
+	tx *sql.Tx
+}
+
+func (r *sqlNameRepository) Insert(ctx context.Context, name string) error {
+	_, err := r.tx.ExecContext(ctx, "INSERT INTO names (name) VALUES (?)", name)
+	return err
+}
+
+func newTestUow(t *testing.T) *Uow {
+	t.Helper()
+	dbt, err := sql.Open("sqlite3", ":memory:")
+	assert.NoError(t, err)
+	dbt.SetMaxOpenConns(1) // Cada conexão teria seu próprio banco em memória
+	t.Cleanup(func() { dbt.Close() })
+
+	_, err = dbt.Exec("CREATE TABLE names (name varchar(255) NOT NULL)")
+	assert.NoError(t, err)
+
+	u := NewUow(context.Background(), dbt)
+	Register(u, "NameRepository", func(tx *sql.Tx) nameRepository {
+		return &sqlNameRepository{tx: tx}
+	})
+	return u
+}
+
+func TestGetRepositoryTyped(t *testing.T) {
+	u := newTestUow(t)
+	ctx := context.Background()
+
+	err := u.Do(ctx, func(u *Uow) error {
+		repo, err := GetRepository[nameRepository](ctx, u, "NameRepository")
+		if err != nil {
+			return err
+		}
+		assert.Same(t, u.Tx, repo.(*sqlNameRepository).tx, "the repository must use the UOW transaction")
+		return repo.Insert(ctx, "Go")
+	})
+	assert.NoError(t, err)
+
+	var total int
+	assert.NoError(t, u.Db.QueryRow("SELECT COUNT(*) FROM names").Scan(&total))
+	assert.Equal(t, 1, total)
+}
+
+func TestGetRepositoryErrors(t *testing.T) {
+	u := newTestUow(t)
+	ctx := context.Background()
+
+	err := u.Do(ctx, func(u *Uow) error {
+		_, err := GetRepository[nameRepository](ctx, u, "UnknownRepository")
+		assert.ErrorIs(t, err, ErrRepositoryNotFound)
+		assert.ErrorContains(t, err, "UnknownRepository")
+
+		_, err = GetRepository[*sql.DB](ctx, u, "NameRepository")
+		assert.ErrorIs(t, err, ErrRepositoryType)
+		assert.ErrorContains(t, err, "*uow.sqlNameRepository")
+		return err
+	})
+	assert.ErrorIs(t, err, ErrRepositoryType)
+
+	u.UnRegister("NameRepository")
+	_, err = u.GetRepository(ctx, "NameRepository")
+	assert.ErrorIs(t, err, ErrRepositoryNotFound)
+	assert.Nil(t, u.Tx, "an unknown repository must not start a transaction")
+}
+
+type testEvent struct {
+	name string
+}
+
+func (e *testEvent) GetName() string         { return e.name }
+func (e *testEvent) GetPayload() interface{} { return nil }
+func (e *testEvent) GetDateTime() time.Time  { return time.Time{} }
+
+// recordingDispatcher guarda os nomes publicados
+type recordingDispatcher struct {
+	published []string
+}
+
+func (d *recordingDispatcher) Dispatch(event events.EventInterface) error {
+	d.published = append(d.published, event.GetName())
+	return nil
+}
+
+func TestDoPublishesEventsAfterCommit(t *testing.T) {
+	u := newTestUow(t)
+	dispatcher := &recordingDispatcher{}
+	u.EventDispatcher = dispatcher
+	ctx := context.Background()
+
+	err := u.Do(ctx, func(u *Uow) error {
+		assert.NoError(t, u.AddEvent(&testEvent{name: "First"}, &testEvent{name: "Second"}))
+		assert.Empty(t, dispatcher.published, "events must wait for the commit")
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"First", "Second"}, dispatcher.published)
+
+	// Rollback descarta os eventos, e eles não vazam para o próximo Do
+	dispatcher.published = nil
+	errFail := errors.New("fail")
+	err = u.Do(ctx, func(u *Uow) error {
+		assert.NoError(t, u.AddEvent(&testEvent{name: "RolledBack"}))
+		return errFail
+	})
+	assert.ErrorIs(t, err, errFail)
+	assert.NoError(t, u.Do(ctx, func(u *Uow) error { return nil }))
+	assert.Empty(t, dispatcher.published)
+
+	assert.Error(t, u.AddEvent(&testEvent{name: "Outside"}), "events need a transaction started by Do")
+}
+
+func TestDoDiscardsEventsFromRetriedAttempts(t *testing.T) {
+	u := newTestUow(t)
+	dispatcher := &recordingDispatcher{}
+	u.EventDispatcher = dispatcher
+	u.TxOptions = []txn.Option{txn.WithBackoff(time.Millisecond)}
+
+	attempts := 0
+	err := u.Do(context.Background(), func(u *Uow) error {
+		attempts++
+		assert.NoError(t, u.AddEvent(&testEvent{name: fmt.Sprintf("Attempt%d", attempts)}))
+		if attempts == 1 {
+			return &mysql.MySQLError{Number: 1213, Message: "Deadlock found when trying to get lock"}
+		}
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Attempt2"}, dispatcher.published)
+}
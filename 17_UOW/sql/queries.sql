@@ -1,5 +1,5 @@
--- name: CreateCategory :exec
-INSERT INTO categories (id, name) VALUES (?, ?);
+-- name: CreateCategory :execresult
+INSERT INTO categories (name) VALUES (?);
 
 -- name: CreateCourse :exec
 INSERT INTO courses (id, name, category_id) VALUES (?, ?, ?);
\ No newline at end of file
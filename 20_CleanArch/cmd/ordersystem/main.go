@@ -0,0 +1,218 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/99designs/gqlgen/graphql/playground"
+	"github.com/ElizCarvalho/FC_PosGolang/20_CleanArch/configs"
+	"github.com/ElizCarvalho/FC_PosGolang/20_CleanArch/events"
+	"github.com/ElizCarvalho/FC_PosGolang/20_CleanArch/internal/event/handler"
+	"github.com/ElizCarvalho/FC_PosGolang/20_CleanArch/internal/infra/graph"
+	"github.com/ElizCarvalho/FC_PosGolang/20_CleanArch/internal/infra/grpc/gateway"
+	"github.com/ElizCarvalho/FC_PosGolang/20_CleanArch/internal/infra/grpc/pb"
+	"github.com/ElizCarvalho/FC_PosGolang/20_CleanArch/internal/infra/web/webserver"
+	"github.com/ElizCarvalho/FC_PosGolang/pkg/gqlsecurity"
+	grpcgateway "github.com/ElizCarvalho/FC_PosGolang/pkg/grpckit/gateway"
+	"github.com/ElizCarvalho/FC_PosGolang/pkg/grpckit/interceptor"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/streadway/amqp"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/reflection"
+
+	// mysql
+	_ "github.com/go-sql-driver/mysql"
+)
+
+func main() {
+	configs, err := configs.LoadConfig(".")
+	if err != nil {
+		panic(err)
+	}
+
+	db, err := sql.Open(configs.DBDriver, fmt.Sprintf("%s:%s@tcp(%s:%s)/%s", configs.DBUser, configs.DBPassword, configs.DBHost, configs.DBPort, configs.DBName))
+	if err != nil {
+		panic(err)
+	}
+
+	rabbitMQConn, rabbitMQChannel := getRabbitMQChannel(configs.RabbitMQURL)
+
+	eventDispatcher := events.NewEventDispatcher()
+	if err := eventDispatcher.Register("OrderCreated", &handler.OrderCreatedHandler{
+		RabbitMQChannel: rabbitMQChannel,
+	}); err != nil {
+		panic(err)
+	}
+	// subscription orderCreated do GraphQL recebe os pedidos criados por qualquer API
+	orderEvents := graph.NewOrderCreatedHandler()
+	if err := eventDispatcher.Register("OrderCreated", orderEvents); err != nil {
+		panic(err)
+	}
+
+	createOrderUseCase := NewCreateOrderUseCase(db, eventDispatcher)
+
+	// Web Server (REST)
+	webserver := webserver.NewWebServer(configs.WebServerPort)
+	webOrderHandler := NewWebOrderHandler(db, eventDispatcher)
+	webserver.AddHandlerWithMethod("POST", "/order", webOrderHandler.Create)
+	webserver.AddHandlerWithMethod("GET", "/orders", webOrderHandler.List)
+
+	// Health Check
+	healthHandler := NewHealthHandler(db, rabbitMQChannel)
+	webserver.AddHandler("/health", healthHandler.Check)
+
+	// Métricas Prometheus (inclui as do servidor gRPC)
+	webserver.AddHandler("/metrics", promhttp.Handler().ServeHTTP)
+
+	fmt.Println("Starting web server on port", configs.WebServerPort)
+	go webserver.Start()
+
+	// gRPC Server
+	// log, métricas, recuperação de panics, prazo e validação em todas as chamadas
+	grpcServer := grpc.NewServer(interceptor.ServerOptions(interceptor.Options{
+		Logger:  slog.New(slog.NewJSONHandler(os.Stdout, nil)),
+		Metrics: interceptor.NewMetrics(prometheus.DefaultRegisterer),
+	})...)
+	orderService := NewOrderService(db, eventDispatcher)
+	pb.RegisterOrderServiceServer(grpcServer, orderService)
+	reflection.Register(grpcServer)
+
+	fmt.Println("Starting gRPC server on port", configs.GRPCServerPort)
+	lis, err := net.Listen("tcp", fmt.Sprintf(":%s", configs.GRPCServerPort))
+	if err != nil {
+		panic(err)
+	}
+	go func() {
+		if err := grpcServer.Serve(lis); err != nil {
+			fmt.Printf("Error starting gRPC server: %v\n", err)
+		}
+	}()
+
+	// REST/JSON Gateway do gRPC (mesmo processo, via conexão em memória)
+	var gatewayServer *http.Server
+	if configs.GatewayServerPort != "" {
+		gatewayServer = newGatewayServer(configs.GatewayServerPort, grpcServer)
+		fmt.Println("Starting gRPC gateway on port", configs.GatewayServerPort)
+		go func() {
+			if err := gatewayServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+				fmt.Printf("Error starting gRPC gateway: %v\n", err)
+			}
+		}()
+	}
+
+	// GraphQL Server
+	orderRepository := NewOrderRepository(db)
+	graphqlOptions := graph.ServerOptions{
+		ComplexityLimit: configs.GraphQLComplexityLimit,
+		DepthLimit:      configs.GraphQLDepthLimit,
+	}
+	if configs.GraphQLMode == "production" {
+		// só queries do allowlist, sem introspecção nem playground
+		graphqlOptions.Allowlist, err = gqlsecurity.LoadAllowlist(configs.GraphQLPersistedQueries)
+		if err != nil {
+			panic(err)
+		}
+		graphqlOptions.AllowedOrigins = strings.Split(configs.GraphQLAllowedOrigins, ",")
+		fmt.Printf("GraphQL production mode: %d persisted queries allowed\n", graphqlOptions.Allowlist.Len())
+	} else {
+		http.Handle("/", playground.Handler("GraphQL playground", "/query"))
+	}
+	srv := graph.NewServer(&graph.Resolver{
+		CreateOrderUseCase: *createOrderUseCase,
+		OrderRepository:    orderRepository,
+		OrderEvents:        orderEvents,
+	}, graphqlOptions)
+	http.Handle("/query", srv)
+
+	graphqlServer := &http.Server{
+		Addr:    ":" + configs.GraphQLServerPort,
+		Handler: nil,
+	}
+
+	fmt.Println("Starting GraphQL server on port", configs.GraphQLServerPort)
+	go func() {
+		if err := graphqlServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			fmt.Printf("Error starting GraphQL server: %v\n", err)
+		}
+	}()
+
+	// Graceful Shutdown
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+	<-quit
+
+	fmt.Println("\nShutting down servers gracefully...")
+
+	// Timeout para shutdown
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	// Shutdown GraphQL server
+	fmt.Println("Shutting down GraphQL server...")
+	if err := graphqlServer.Shutdown(ctx); err != nil {
+		fmt.Printf("GraphQL server forced to shutdown: %v\n", err)
+	}
+
+	// Shutdown gRPC gateway
+	if gatewayServer != nil {
+		fmt.Println("Shutting down gRPC gateway...")
+		if err := gatewayServer.Shutdown(ctx); err != nil {
+			fmt.Printf("gRPC gateway forced to shutdown: %v\n", err)
+		}
+	}
+
+	// Shutdown gRPC server
+	fmt.Println("Shutting down gRPC server...")
+	grpcServer.GracefulStop()
+
+	// Close RabbitMQ
+	fmt.Println("Closing RabbitMQ connection...")
+	if err := rabbitMQChannel.Close(); err != nil {
+		fmt.Printf("Error closing RabbitMQ channel: %v\n", err)
+	}
+	if err := rabbitMQConn.Close(); err != nil {
+		fmt.Printf("Error closing RabbitMQ connection: %v\n", err)
+	}
+
+	// Close database
+	fmt.Println("Closing database connection...")
+	if err := db.Close(); err != nil {
+		fmt.Printf("Error closing database: %v\n", err)
+	}
+
+	fmt.Println("Shutdown complete")
+}
+
+func newGatewayServer(port string, grpcServer *grpc.Server) *http.Server {
+	conn, err := grpcgateway.InProcess(grpcServer)
+	if err != nil {
+		panic(err)
+	}
+	handler, err := gateway.New(context.Background(), conn)
+	if err != nil {
+		panic(err)
+	}
+	return &http.Server{Addr: ":" + port, Handler: handler}
+}
+
+func getRabbitMQChannel(rabbitMQURL string) (*amqp.Connection, *amqp.Channel) {
+	conn, err := amqp.Dial(rabbitMQURL)
+	if err != nil {
+		panic(err)
+	}
+	ch, err := conn.Channel()
+	if err != nil {
+		panic(err)
+	}
+	return conn, ch
+}
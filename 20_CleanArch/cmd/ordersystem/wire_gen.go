@@ -0,0 +1,63 @@
+// Code generated by Wire. DO NOT EDIT.
+
+//go:generate go run github.com/google/wire/cmd/wire
+//go:build !wireinject
+// +build !wireinject
+
+package main
+
+import (
+	"database/sql"
+
+	"github.com/ElizCarvalho/FC_PosGolang/20_CleanArch/events"
+	"github.com/ElizCarvalho/FC_PosGolang/20_CleanArch/internal/entity"
+	"github.com/ElizCarvalho/FC_PosGolang/20_CleanArch/internal/event"
+	"github.com/ElizCarvalho/FC_PosGolang/20_CleanArch/internal/infra/database"
+	"github.com/ElizCarvalho/FC_PosGolang/20_CleanArch/internal/infra/grpc/service"
+	"github.com/ElizCarvalho/FC_PosGolang/20_CleanArch/internal/infra/web"
+	"github.com/ElizCarvalho/FC_PosGolang/20_CleanArch/internal/usecase"
+	"github.com/google/wire"
+	"github.com/streadway/amqp"
+)
+
+// Injectors from wire.go:
+
+func NewCreateOrderUseCase(db *sql.DB, eventDispatcher events.EventDispatcherInterface) *usecase.CreateOrderUseCase {
+	orderRepository := database.NewOrderRepository(db)
+	orderCreated := event.NewOrderCreated()
+	createOrderUseCase := usecase.NewCreateOrderUseCase(orderRepository, orderCreated, eventDispatcher)
+	return createOrderUseCase
+}
+
+func NewWebOrderHandler(db *sql.DB, eventDispatcher events.EventDispatcherInterface) *web.WebOrderHandler {
+	orderRepository := database.NewOrderRepository(db)
+	orderCreated := event.NewOrderCreated()
+	webOrderHandler := web.NewWebOrderHandler(eventDispatcher, orderRepository, orderCreated)
+	return webOrderHandler
+}
+
+func NewHealthHandler(db *sql.DB, rabbitMQChannel *amqp.Channel) *web.HealthHandler {
+	healthHandler := web.NewHealthHandler(db, rabbitMQChannel)
+	return healthHandler
+}
+
+func NewOrderService(db *sql.DB, eventDispatcher events.EventDispatcherInterface) *service.OrderService {
+	orderRepository := database.NewOrderRepository(db)
+	orderCreated := event.NewOrderCreated()
+	createOrderUseCase := usecase.NewCreateOrderUseCase(orderRepository, orderCreated, eventDispatcher)
+	orderService := service.NewOrderService(*createOrderUseCase, orderRepository)
+	return orderService
+}
+
+func NewOrderRepository(db *sql.DB) entity.OrderRepositoryInterface {
+	orderRepository := database.NewOrderRepository(db)
+	return orderRepository
+}
+
+// wire.go:
+
+var setOrderRepositoryDependency = wire.NewSet(database.NewOrderRepository, wire.Bind(new(entity.OrderRepositoryInterface), new(*database.OrderRepository)))
+
+var setEventDispatcherDependency = wire.NewSet(events.NewEventDispatcher, event.NewOrderCreated, wire.Bind(new(events.EventInterface), new(*event.OrderCreated)), wire.Bind(new(events.EventDispatcherInterface), new(*events.EventDispatcher)))
+
+var setOrderCreatedEvent = wire.NewSet(event.NewOrderCreated, wire.Bind(new(events.EventInterface), new(*event.OrderCreated)))
@@ -12,7 +12,15 @@ type conf struct {
 	WebServerPort     string `mapstructure:"WEB_SERVER_PORT"`
 	GRPCServerPort    string `mapstructure:"GRPC_SERVER_PORT"`
 	GraphQLServerPort string `mapstructure:"GRAPHQL_SERVER_PORT"`
+	GatewayServerPort string `mapstructure:"GATEWAY_SERVER_PORT"`
 	RabbitMQURL       string `mapstructure:"RABBITMQ_URL"`
+	// GraphQLMode=production aceita só as queries de GraphQLPersistedQueries
+	GraphQLMode             string `mapstructure:"GRAPHQL_MODE"`
+	GraphQLComplexityLimit  int    `mapstructure:"GRAPHQL_COMPLEXITY_LIMIT"`
+	GraphQLDepthLimit       int    `mapstructure:"GRAPHQL_DEPTH_LIMIT"`
+	GraphQLPersistedQueries string `mapstructure:"GRAPHQL_PERSISTED_QUERIES"`
+	// GraphQLAllowedOrigins são as origens, separadas por vírgula, que abrem subscriptions em produção
+	GraphQLAllowedOrigins string `mapstructure:"GRAPHQL_ALLOWED_ORIGINS"`
 }
 
 func LoadConfig(path string) (*conf, error) {
@@ -21,6 +29,11 @@ func LoadConfig(path string) (*conf, error) {
 	viper.SetConfigType("env")
 	viper.AddConfigPath(path)
 	viper.SetConfigFile(".env")
+	viper.SetDefault("GRAPHQL_MODE", "development")
+	viper.SetDefault("GRAPHQL_COMPLEXITY_LIMIT", 1000)
+	viper.SetDefault("GRAPHQL_DEPTH_LIMIT", 5)
+	viper.SetDefault("GRAPHQL_PERSISTED_QUERIES", "persisted-queries.json")
+	viper.SetDefault("GRAPHQL_ALLOWED_ORIGINS", "")
 	viper.AutomaticEnv()
 	err := viper.ReadInConfig()
 	if err != nil {
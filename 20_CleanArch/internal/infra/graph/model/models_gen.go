@@ -20,3 +20,6 @@ type OrderInput struct {
 
 type Query struct {
 }
+
+type Subscription struct {
+}
@@ -0,0 +1,39 @@
+package graph
+
+import (
+	"sync"
+
+	"github.com/ElizCarvalho/FC_PosGolang/20_CleanArch/events"
+	"github.com/ElizCarvalho/FC_PosGolang/20_CleanArch/internal/infra/graph/model"
+	"github.com/ElizCarvalho/FC_PosGolang/20_CleanArch/internal/usecase"
+	"github.com/ElizCarvalho/FC_PosGolang/pkg/pubsub"
+)
+
+// orderCreatedBuffer é quantos eventos um assinante pode acumular antes de perder os próximos
+const orderCreatedBuffer = 16
+
+// OrderCreatedHandler recebe o evento OrderCreated do EventDispatcher e repassa
+// a ordem para todas as subscriptions orderCreated abertas. Subscribe e
+// Subscribers vêm do Broker.
+type OrderCreatedHandler struct {
+	*pubsub.Broker[*model.Order]
+}
+
+func NewOrderCreatedHandler() *OrderCreatedHandler {
+	return &OrderCreatedHandler{Broker: pubsub.NewBroker[*model.Order](orderCreatedBuffer)}
+}
+
+func (h *OrderCreatedHandler) Handle(event events.EventInterface, wg *sync.WaitGroup) {
+	defer wg.Done()
+
+	output, ok := event.GetPayload().(usecase.OrderOutputDTO)
+	if !ok {
+		return
+	}
+	h.Publish(&model.Order{
+		ID:         output.ID,
+		Price:      output.Price,
+		Tax:        output.Tax,
+		FinalPrice: output.FinalPrice,
+	})
+}
@@ -0,0 +1,132 @@
+package graph
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/99designs/gqlgen/client"
+	"github.com/ElizCarvalho/FC_PosGolang/20_CleanArch/events"
+	"github.com/ElizCarvalho/FC_PosGolang/20_CleanArch/internal/entity"
+	"github.com/ElizCarvalho/FC_PosGolang/20_CleanArch/internal/event"
+	"github.com/ElizCarvalho/FC_PosGolang/20_CleanArch/internal/usecase"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockOrderRepository struct {
+	mock.Mock
+}
+
+func (m *MockOrderRepository) Save(order *entity.Order) error {
+	args := m.Called(order)
+	return args.Error(0)
+}
+
+func (m *MockOrderRepository) GetTotal() (int, error) {
+	args := m.Called()
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockOrderRepository) FindAll() ([]*entity.Order, error) {
+	args := m.Called()
+	return args.Get(0).([]*entity.Order), args.Error(1)
+}
+
+type orderCreatedEvent struct {
+	OrderCreated struct {
+		ID         string
+		Price      float64
+		Tax        float64
+		FinalPrice float64
+	}
+}
+
+func setupSubscriptionTest(t *testing.T) (*client.Client, *OrderCreatedHandler, *usecase.CreateOrderUseCase) {
+	t.Helper()
+	repository := &MockOrderRepository{}
+	repository.On("Save", mock.Anything).Return(nil)
+
+	orderEvents := NewOrderCreatedHandler()
+	dispatcher := events.NewEventDispatcher()
+	require.NoError(t, dispatcher.Register("OrderCreated", orderEvents))
+
+	createOrder := usecase.NewCreateOrderUseCase(repository, event.NewOrderCreated(), dispatcher)
+	srv := NewServer(&Resolver{
+		CreateOrderUseCase: *createOrder,
+		OrderRepository:    repository,
+		OrderEvents:        orderEvents,
+	}, ServerOptions{ComplexityLimit: 1000, DepthLimit: 5})
+	return client.New(srv), orderEvents, createOrder
+}
+
+func waitSubscribers(t *testing.T, h *OrderCreatedHandler, want int) {
+	t.Helper()
+	assert.Eventually(t, func() bool { return h.Subscribers() == want }, 2*time.Second, 5*time.Millisecond)
+}
+
+func TestOrderCreatedSubscription_ReceivesGraphQLMutation(t *testing.T) {
+	c, orderEvents, _ := setupSubscriptionTest(t)
+
+	sub := c.Websocket(`subscription { orderCreated { id Price Tax FinalPrice } }`)
+	defer sub.Close()
+	waitSubscribers(t, orderEvents, 1)
+
+	var created map[string]any
+	c.MustPost(`mutation { createOrder(input: {id: "order-1", Price: 100, Tax: 10}) { id } }`, &created)
+
+	var resp orderCreatedEvent
+	require.NoError(t, sub.Next(&resp))
+	assert.Equal(t, "order-1", resp.OrderCreated.ID)
+	assert.Equal(t, 100.0, resp.OrderCreated.Price)
+	assert.Equal(t, 10.0, resp.OrderCreated.Tax)
+	assert.Equal(t, 110.0, resp.OrderCreated.FinalPrice)
+}
+
+func TestOrderCreatedSubscription_ReceivesOrdersFromOtherAPIs(t *testing.T) {
+	c, orderEvents, createOrder := setupSubscriptionTest(t)
+
+	first := c.Websocket(`subscription { orderCreated { id } }`)
+	defer first.Close()
+	second := c.Websocket(`subscription { orderCreated { id } }`)
+	defer second.Close()
+	waitSubscribers(t, orderEvents, 2)
+
+	// REST e gRPC usam o mesmo use case e o mesmo EventDispatcher
+	_, err := createOrder.Execute(usecase.OrderInputDTO{ID: "order-2", Price: 50, Tax: 5})
+	require.NoError(t, err)
+
+	for _, sub := range []*client.Subscription{first, second} {
+		var resp orderCreatedEvent
+		require.NoError(t, sub.Next(&resp))
+		assert.Equal(t, "order-2", resp.OrderCreated.ID)
+	}
+}
+
+func TestOrderCreatedHandler_UnsubscribesOnClose(t *testing.T) {
+	c, orderEvents, _ := setupSubscriptionTest(t)
+
+	sub := c.Websocket(`subscription { orderCreated { id } }`)
+	waitSubscribers(t, orderEvents, 1)
+
+	require.NoError(t, sub.Close())
+	waitSubscribers(t, orderEvents, 0)
+}
+
+func TestOrderCreatedHandler_IgnoresUnknownPayload(t *testing.T) {
+	h := NewOrderCreatedHandler()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ch := h.Subscribe(ctx)
+
+	orderCreated := event.NewOrderCreated()
+	orderCreated.SetPayload("not an order")
+	wg := &sync.WaitGroup{}
+	wg.Add(1)
+	h.Handle(orderCreated, wg)
+	wg.Wait()
+
+	assert.Empty(t, ch)
+}
@@ -12,4 +12,6 @@ import (
 type Resolver struct {
 	CreateOrderUseCase usecase.CreateOrderUseCase
 	OrderRepository    entity.OrderRepositoryInterface
+	// OrderEvents alimenta a subscription orderCreated
+	OrderEvents *OrderCreatedHandler
 }
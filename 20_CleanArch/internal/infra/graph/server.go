@@ -0,0 +1,45 @@
+package graph
+
+import (
+	"github.com/99designs/gqlgen/graphql/handler"
+	"github.com/99designs/gqlgen/graphql/handler/extension"
+	"github.com/99designs/gqlgen/graphql/handler/lru"
+	"github.com/99designs/gqlgen/graphql/handler/transport"
+	"github.com/ElizCarvalho/FC_PosGolang/pkg/gqlsecurity"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+// ServerOptions configura os limites do servidor GraphQL.
+type ServerOptions struct {
+	ComplexityLimit int
+	DepthLimit      int
+	// Allowlist liga o modo produção: só queries persistidas, sem introspecção nem APQ
+	// e websocket só a partir de AllowedOrigins (ou da própria origem do servidor)
+	Allowlist      *gqlsecurity.Allowlist
+	AllowedOrigins []string
+}
+
+// NewServer monta o servidor GraphQL com websocket (subscriptions), limites de
+// complexidade e profundidade e, em produção, o allowlist de queries persistidas.
+func NewServer(resolver *Resolver, opts ServerOptions) *handler.Server {
+	cfg := Config{Resolvers: resolver}
+	cfg.Complexity.Query.ListOrders = gqlsecurity.ListComplexity
+
+	srv := handler.New(NewExecutableSchema(cfg))
+	srv.AddTransport(gqlsecurity.Websocket(opts.Allowlist != nil, opts.AllowedOrigins))
+	srv.AddTransport(transport.Options{})
+	srv.AddTransport(transport.GET{})
+	srv.AddTransport(transport.POST{})
+	srv.SetQueryCache(lru.New[*ast.QueryDocument](1000))
+
+	srv.Use(extension.FixedComplexityLimit(opts.ComplexityLimit))
+	srv.Use(gqlsecurity.DepthLimit{Limit: opts.DepthLimit})
+
+	if opts.Allowlist != nil {
+		srv.Use(opts.Allowlist)
+		return srv
+	}
+	srv.Use(extension.Introspection{})
+	srv.Use(extension.AutomaticPersistedQuery{Cache: lru.New[string](100)})
+	return srv
+}
@@ -0,0 +1,118 @@
+package graph
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/99designs/gqlgen/client"
+	"github.com/ElizCarvalho/FC_PosGolang/20_CleanArch/internal/entity"
+	"github.com/ElizCarvalho/FC_PosGolang/pkg/gqlsecurity"
+	"github.com/stretchr/testify/assert"
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/require"
+)
+
+const listOrdersQuery = `query ListOrders { listOrders { id Price Tax FinalPrice } }`
+
+func newLimitedClient(opts ServerOptions) *client.Client {
+	repository := &MockOrderRepository{}
+	repository.On("FindAll").Return([]*entity.Order{{ID: "order-1", Price: 10, Tax: 1, FinalPrice: 11}}, nil)
+	return client.New(NewServer(&Resolver{OrderRepository: repository, OrderEvents: NewOrderCreatedHandler()}, opts))
+}
+
+func TestNewServer_ComplexityLimit(t *testing.T) {
+	// listOrders { 4 campos } = 1 + 4*10
+	c := newLimitedClient(ServerOptions{ComplexityLimit: 40, DepthLimit: 5})
+
+	var resp map[string]any
+	err := c.Post(listOrdersQuery, &resp)
+	assert.ErrorContains(t, err, "operation has complexity 41, which exceeds the limit of 40")
+	assert.ErrorContains(t, err, "COMPLEXITY_LIMIT_EXCEEDED")
+
+	assert.NoError(t, c.Post(`{ listOrders { id } }`, &resp))
+}
+
+func TestNewServer_DepthLimit(t *testing.T) {
+	c := newLimitedClient(ServerOptions{ComplexityLimit: 1000, DepthLimit: 1})
+
+	var resp map[string]any
+	err := c.Post(listOrdersQuery, &resp)
+	assert.ErrorContains(t, err, "operation has depth 2, which exceeds the limit of 1")
+	assert.ErrorContains(t, err, "DEPTH_LIMIT_EXCEEDED")
+}
+
+func TestNewServer_ProductionModeOnlyRunsPersistedQueries(t *testing.T) {
+	allowlist := gqlsecurity.NewAllowlist(map[string]string{gqlsecurity.QueryHash(listOrdersQuery): listOrdersQuery})
+	c := newLimitedClient(ServerOptions{ComplexityLimit: 1000, DepthLimit: 5, Allowlist: allowlist})
+
+	var resp struct {
+		ListOrders []struct {
+			ID                     string
+			Price, Tax, FinalPrice float64
+		}
+	}
+	require.NoError(t, c.Post("", &resp, client.Extensions(map[string]any{
+		"persistedQuery": map[string]any{"version": 1, "sha256Hash": gqlsecurity.QueryHash(listOrdersQuery)},
+	})))
+	require.Len(t, resp.ListOrders, 1)
+	assert.Equal(t, "order-1", resp.ListOrders[0].ID)
+	assert.Equal(t, 11.0, resp.ListOrders[0].FinalPrice)
+
+	var other map[string]any
+	err := c.Post(`{ listOrders { id } }`, &other)
+	assert.ErrorContains(t, err, "PERSISTED_QUERY_NOT_ALLOWED")
+
+	err = c.Post(`{ __schema { queryType { name } } }`, &other)
+	assert.ErrorContains(t, err, "PERSISTED_QUERY_NOT_ALLOWED")
+}
+
+func TestNewServer_DevelopmentModeAllowsIntrospection(t *testing.T) {
+	c := newLimitedClient(ServerOptions{ComplexityLimit: 1000, DepthLimit: 5})
+
+	var resp struct {
+		Schema struct {
+			QueryType struct{ Name string }
+		} `json:"__schema"`
+	}
+	require.NoError(t, c.Post(`{ __schema { queryType { name } } }`, &resp))
+	assert.Equal(t, "Query", resp.Schema.QueryType.Name)
+}
+
+func TestNewServer_ShippedPersistedQueries(t *testing.T) {
+	allowlist, err := gqlsecurity.LoadAllowlist(filepath.Join("..", "..", "..", "cmd", "ordersystem", "persisted-queries.json"))
+	require.NoError(t, err)
+	assert.Equal(t, 3, allowlist.Len())
+}
+
+func TestNewServer_ProductionModeChecksWebsocketOrigin(t *testing.T) {
+	production := ServerOptions{
+		ComplexityLimit: 1000,
+		DepthLimit:      5,
+		Allowlist:       gqlsecurity.NewAllowlist(map[string]string{}),
+		AllowedOrigins:  []string{"https://app.example.com"},
+	}
+	dial := func(opts ServerOptions, origin string) (*http.Response, error) {
+		srv := httptest.NewServer(NewServer(&Resolver{OrderEvents: NewOrderCreatedHandler()}, opts))
+		t.Cleanup(srv.Close)
+		dialer := websocket.Dialer{Subprotocols: []string{"graphql-transport-ws"}}
+		conn, resp, err := dialer.Dial("ws"+strings.TrimPrefix(srv.URL, "http"), http.Header{"Origin": {origin}})
+		if err == nil {
+			conn.Close()
+		}
+		return resp, err
+	}
+
+	resp, err := dial(production, "https://evil.example")
+	assert.ErrorIs(t, err, websocket.ErrBadHandshake)
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+
+	_, err = dial(production, "https://app.example.com")
+	assert.NoError(t, err)
+
+	// em desenvolvimento o playground pode rodar em outra origem
+	_, err = dial(ServerOptions{ComplexityLimit: 1000, DepthLimit: 5}, "https://evil.example")
+	assert.NoError(t, err)
+}
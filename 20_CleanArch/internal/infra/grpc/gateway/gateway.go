@@ -0,0 +1,25 @@
+// Package gateway expõe o OrderService como REST/JSON. As rotas vêm das
+// anotações google.api.http em protofiles/order.proto e o código de tradução é
+// gerado pelo protoc-gen-grpc-gateway (pb/order.pb.gw.go). O mux, o JSON e a
+// conexão em memória ficam em pkg/grpckit/gateway, compartilhado com o 13_gRPC_FC.
+package gateway
+
+import (
+	"context"
+	_ "embed"
+	"net/http"
+
+	"github.com/ElizCarvalho/FC_PosGolang/20_CleanArch/internal/infra/grpc/pb"
+	grpcgateway "github.com/ElizCarvalho/FC_PosGolang/pkg/grpckit/gateway"
+	"google.golang.org/grpc"
+)
+
+// openAPI é gerado pelo protoc-gen-openapiv2 a partir das mesmas anotações
+//
+//go:embed order.swagger.json
+var openAPI []byte
+
+// New monta o handler HTTP do OrderService sobre conn
+func New(ctx context.Context, conn *grpc.ClientConn) (http.Handler, error) {
+	return grpcgateway.New(ctx, conn, openAPI, pb.RegisterOrderServiceHandler)
+}
@@ -0,0 +1,99 @@
+package gateway
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ElizCarvalho/FC_PosGolang/20_CleanArch/internal/infra/grpc/pb"
+	grpcgateway "github.com/ElizCarvalho/FC_PosGolang/pkg/grpckit/gateway"
+	"github.com/ElizCarvalho/FC_PosGolang/pkg/grpckit/interceptor"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+)
+
+type fakeOrderService struct {
+	pb.UnimplementedOrderServiceServer
+	orders []*pb.Order
+}
+
+func (s *fakeOrderService) CreateOrder(ctx context.Context, in *pb.CreateOrderRequest) (*pb.CreateOrderResponse, error) {
+	order := &pb.Order{Id: in.Id, Price: in.Price, Tax: in.Tax, FinalPrice: in.Price + in.Tax}
+	s.orders = append(s.orders, order)
+	return &pb.CreateOrderResponse{Id: order.Id, Price: order.Price, Tax: order.Tax, FinalPrice: order.FinalPrice}, nil
+}
+
+func (s *fakeOrderService) ListOrders(ctx context.Context, in *pb.ListOrdersRequest) (*pb.ListOrdersResponse, error) {
+	return &pb.ListOrdersResponse{Orders: s.orders}, nil
+}
+
+func newTestGateway(t *testing.T) string {
+	server := grpc.NewServer(interceptor.ServerOptions(interceptor.Options{
+		Logger: slog.New(slog.NewTextHandler(io.Discard, nil)),
+	})...)
+	pb.RegisterOrderServiceServer(server, &fakeOrderService{})
+	t.Cleanup(server.Stop)
+
+	conn, err := grpcgateway.InProcess(server)
+	assert.Nil(t, err)
+	t.Cleanup(func() { conn.Close() })
+	handler, err := New(context.Background(), conn)
+	assert.Nil(t, err)
+
+	httpServer := httptest.NewServer(handler)
+	t.Cleanup(httpServer.Close)
+	return httpServer.URL
+}
+
+func TestCreateAndListOrders(t *testing.T) {
+	baseURL := newTestGateway(t)
+
+	resp, err := http.Post(baseURL+"/v1/orders", "application/json", strings.NewReader(`{"id":"order-1","price":100,"tax":10}`))
+	assert.Nil(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	var created map[string]any
+	json.NewDecoder(resp.Body).Decode(&created)
+	assert.Equal(t, "order-1", created["id"])
+	assert.Equal(t, 110.0, created["final_price"])
+
+	resp, err = http.Get(baseURL + "/v1/orders")
+	assert.Nil(t, err)
+	defer resp.Body.Close()
+	var list struct {
+		Orders []map[string]any `json:"orders"`
+	}
+	json.NewDecoder(resp.Body).Decode(&list)
+	assert.Len(t, list.Orders, 1)
+}
+
+func TestInvalidOrderReturnsBadRequest(t *testing.T) {
+	baseURL := newTestGateway(t)
+
+	resp, err := http.Post(baseURL+"/v1/orders", "application/json", strings.NewReader(`{"id":"order-1","price":100}`))
+	assert.Nil(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	var status map[string]any
+	json.NewDecoder(resp.Body).Decode(&status)
+	assert.Equal(t, "invalid tax", status["message"])
+}
+
+func TestOpenAPIDocument(t *testing.T) {
+	baseURL := newTestGateway(t)
+
+	resp, err := http.Get(baseURL + "/openapi.json")
+	assert.Nil(t, err)
+	defer resp.Body.Close()
+	var spec struct {
+		Paths map[string]map[string]any `json:"paths"`
+	}
+	assert.Nil(t, json.NewDecoder(resp.Body).Decode(&spec))
+	assert.NotNil(t, spec.Paths["/v1/orders"]["post"])
+	assert.NotNil(t, spec.Paths["/v1/orders"]["get"])
+}
@@ -7,6 +7,7 @@
 package pb
 
 import (
+	_ "google.golang.org/genproto/googleapis/api/annotations"
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
 	reflect "reflect"
@@ -301,7 +302,7 @@ var File_internal_infra_grpc_protofiles_order_proto protoreflect.FileDescriptor
 
 const file_internal_infra_grpc_protofiles_order_proto_rawDesc = "" +
 	"\n" +
-	"*internal/infra/grpc/protofiles/order.proto\x12\x02pb\"L\n" +
+	"*internal/infra/grpc/protofiles/order.proto\x12\x02pb\x1a\x1cgoogle/api/annotations.proto\"L\n" +
 	"\x12CreateOrderRequest\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12\x14\n" +
 	"\x05price\x18\x02 \x01(\x02R\x05price\x12\x10\n" +
@@ -320,11 +321,13 @@ const file_internal_infra_grpc_protofiles_order_proto_rawDesc = "" +
 	"\x05price\x18\x02 \x01(\x02R\x05price\x12\x10\n" +
 	"\x03tax\x18\x03 \x01(\x02R\x03tax\x12\x1f\n" +
 	"\vfinal_price\x18\x04 \x01(\x02R\n" +
-	"finalPrice2\x8b\x01\n" +
-	"\fOrderService\x12>\n" +
-	"\vCreateOrder\x12\x16.pb.CreateOrderRequest\x1a\x17.pb.CreateOrderResponse\x12;\n" +
+	"finalPrice2\xb6\x01\n" +
+	"\fOrderService\x12U\n" +
+	"\vCreateOrder\x12\x16.pb.CreateOrderRequest\x1a\x17.pb.CreateOrderResponse\"\x15\x82\xd3\xe4\x93\x02\x0f:\x01*\"\n" +
+	"/v1/orders\x12O\n" +
 	"\n" +
-	"ListOrders\x12\x15.pb.ListOrdersRequest\x1a\x16.pb.ListOrdersResponseBJZHgithub.com/ElizCarvalho/FC_PosGolang/20_CleanArch/internal/infra/grpc/pbb\x06proto3"
+	"ListOrders\x12\x15.pb.ListOrdersRequest\x1a\x16.pb.ListOrdersResponse\"\x12\x82\xd3\xe4\x93\x02\f\x12\n" +
+	"/v1/ordersBJZHgithub.com/ElizCarvalho/FC_PosGolang/20_CleanArch/internal/infra/grpc/pbb\x06proto3"
 
 var (
 	file_internal_infra_grpc_protofiles_order_proto_rawDescOnce sync.Once
@@ -0,0 +1,20 @@
+package pb
+
+import "errors"
+
+// Regras de validação dos requests, as mesmas de entity.Order.IsValid. Ficam
+// fora dos arquivos gerados pelo protoc para não serem sobrescritas; o
+// interceptor de validação chama Validate antes de o request chegar ao serviço.
+
+func (r *CreateOrderRequest) Validate() error {
+	if r.GetId() == "" {
+		return errors.New("invalid id")
+	}
+	if r.GetPrice() <= 0 {
+		return errors.New("invalid price")
+	}
+	if r.GetTax() <= 0 {
+		return errors.New("invalid tax")
+	}
+	return nil
+}
@@ -1,20 +1,23 @@
 package main
 
 import (
+	"io"
 	"log"
 	"net/http"
+	"os"
+	"time"
 
 	"github.com/ElizCarvalho/FC_PosGolang/7_APIS/configs"
 	_ "github.com/ElizCarvalho/FC_PosGolang/7_APIS/docs"
 	"github.com/ElizCarvalho/FC_PosGolang/7_APIS/infra/database"
-	"github.com/ElizCarvalho/FC_PosGolang/7_APIS/internal/entity"
 	"github.com/ElizCarvalho/FC_PosGolang/7_APIS/internal/webserver/handlers"
+	"github.com/ElizCarvalho/FC_PosGolang/7_APIS/pkg/mailer"
+	"github.com/ElizCarvalho/FC_PosGolang/7_APIS/pkg/ratelimit"
+	"github.com/ElizCarvalho/FC_PosGolang/7_APIS/pkg/token"
 	"github.com/go-chi/chi"
 	"github.com/go-chi/chi/middleware"
 	"github.com/go-chi/jwtauth"
 	httpSwagger "github.com/swaggo/http-swagger"
-	"gorm.io/driver/sqlite"
-	"gorm.io/gorm"
 )
 
 // @title FC Pos Golang API Example
@@ -40,21 +43,45 @@ func main() {
 	if err != nil {
 		panic(err)
 	}
-	db, err := gorm.Open(sqlite.Open(config.DBName), &gorm.Config{})
+	db, err := database.Open(database.ConnectionConfig{
+		Driver:          config.DBDriver,
+		Host:            config.DBHost,
+		Port:            config.DBPort,
+		User:            config.DBUser,
+		Password:        config.DBPassword,
+		Name:            config.DBName,
+		SSLMode:         config.DBSSLMode,
+		MaxOpenConns:    config.DBMaxOpenConns,
+		MaxIdleConns:    config.DBMaxIdleConns,
+		ConnMaxLifetime: time.Second * time.Duration(config.DBConnMaxLifetime),
+	})
 	if err != nil {
 		panic(err)
 	}
-	db.AutoMigrate(&entity.User{})
-	db.AutoMigrate(&entity.Product{})
+	if err := database.Migrate(db, database.Migrations); err != nil {
+		panic(err)
+	}
 	productDB := database.NewProductDB(db)
 	productHandler := handlers.NewProductHandler(productDB)
 	userDB := database.NewUserDB(db)
-	userHandler := handlers.NewUserHandler(userDB)
+	mailOutput, err := mailerOutput(config.MailerOutput)
+	if err != nil {
+		panic(err)
+	}
+	accountTokens := token.NewIssuer(config.AccountTokenAuth, time.Second*time.Duration(config.AccountTokenExpiresIn))
+	loginWindow := time.Second * time.Duration(config.LoginAttemptWindow)
+	loginLockout := time.Second * time.Duration(config.LoginLockoutDuration)
+	loginLimiter := ratelimit.NewLoginLimiter(
+		ratelimit.NewAttemptLimiter(config.LoginMaxAttempts, loginWindow, loginLockout),
+		ratelimit.NewAttemptLimiter(config.LoginMaxAttemptsPerIP, loginWindow, loginLockout),
+	)
+	userHandler := handlers.NewUserHandler(userDB, mailer.NewLogMailer(mailOutput), accountTokens, config.AppURL, loginLimiter)
 
 	r := chi.NewRouter()
 	//r.Use(middleware.Logger)
 	r.Use(LogRequest)
 	r.Use(middleware.Recoverer)
+	r.Use(ratelimit.Middleware(ratelimit.NewTokenBucket(config.RateLimitRPS, config.RateLimitBurst), ratelimit.ClientIP))
 	r.Use(middleware.WithValue("jwt", config.TokenAuth))
 	r.Use(middleware.WithValue("jwtExpiresIn", config.JWTExpiresIn))
 
@@ -63,6 +90,9 @@ func main() {
 		r.Use(jwtauth.Authenticator)
 		r.Post("/", productHandler.CreateProduct)
 		r.Get("/", productHandler.GetProducts)
+		r.Post("/batch", productHandler.CreateProductsBatch)
+		r.Put("/batch", productHandler.UpdateProductsBatch)
+		r.Delete("/batch", productHandler.DeleteProductsBatch)
 		r.Get("/{id}", productHandler.GetProduct)
 		r.Put("/{id}", productHandler.UpdateProduct)
 		r.Delete("/{id}", productHandler.DeleteProduct)
@@ -71,6 +101,17 @@ func main() {
 	r.Route("/users", func(r chi.Router) {
 		r.Post("/", userHandler.CreateUser)
 		r.Post("/generate_token", userHandler.GetJWT)
+		r.Post("/verify_email", userHandler.VerifyEmail)
+		r.Post("/resend_verification", userHandler.ResendVerification)
+		r.Post("/forgot_password", userHandler.ForgotPassword)
+		r.Post("/reset_password", userHandler.ResetPassword)
+
+		r.Group(func(r chi.Router) {
+			r.Use(jwtauth.Verifier(config.TokenAuth))
+			r.Use(jwtauth.Authenticator)
+			r.Get("/me", userHandler.GetProfile)
+			r.Put("/me", userHandler.UpdateProfile)
+		})
 	})
 
 	//swaggerURL := "http://localhost" + config.WebServerPort + "/docs/doc.json"
@@ -87,3 +128,11 @@ func LogRequest(next http.Handler) http.Handler {
 		next.ServeHTTP(w, r)
 	})
 }
+
+// mailerOutput devolve onde o LogMailer grava as mensagens: o arquivo configurado ou stdout
+func mailerOutput(path string) (io.Writer, error) {
+	if path == "" {
+		return os.Stdout, nil
+	}
+	return os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+}
@@ -1,41 +1,110 @@
 package configs
 
 import (
+	"fmt"
+
 	"github.com/go-chi/jwtauth"
 	"github.com/spf13/viper"
 )
 
 type conf struct {
-	DBDriver      string `mapstructure:"DB_DRIVER"`
-	DBHost        string `mapstructure:"DB_HOST"`
-	DBPort        string `mapstructure:"DB_PORT"`
-	DBUser        string `mapstructure:"DB_USER"`
-	DBPassword    string `mapstructure:"DB_PASSWORD"`
-	DBName        string `mapstructure:"DB_NAME"`
-	WebServerPort string `mapstructure:"WEB_SERVER_PORT"`
-	JWTSecret     string `mapstructure:"JWT_SECRET"`
-	JWTExpiresIn  int    `mapstructure:"JWT_EXPIRES_IN"`
-	TokenAuth     *jwtauth.JWTAuth
+	DBDriver              string  `mapstructure:"DB_DRIVER"`
+	DBHost                string  `mapstructure:"DB_HOST"`
+	DBPort                string  `mapstructure:"DB_PORT"`
+	DBUser                string  `mapstructure:"DB_USER"`
+	DBPassword            string  `mapstructure:"DB_PASSWORD"`
+	DBName                string  `mapstructure:"DB_NAME"`
+	DBSSLMode             string  `mapstructure:"DB_SSL_MODE"`
+	DBMaxOpenConns        int     `mapstructure:"DB_MAX_OPEN_CONNS"`
+	DBMaxIdleConns        int     `mapstructure:"DB_MAX_IDLE_CONNS"`
+	DBConnMaxLifetime     int     `mapstructure:"DB_CONN_MAX_LIFETIME"`
+	WebServerPort         string  `mapstructure:"WEB_SERVER_PORT"`
+	JWTSecret             string  `mapstructure:"JWT_SECRET"`
+	JWTExpiresIn          int     `mapstructure:"JWT_EXPIRES_IN"`
+	AccountTokenSecret    string  `mapstructure:"ACCOUNT_TOKEN_SECRET"`
+	AccountTokenExpiresIn int     `mapstructure:"ACCOUNT_TOKEN_EXPIRES_IN"`
+	AppURL                string  `mapstructure:"APP_URL"`
+	MailerOutput          string  `mapstructure:"MAILER_OUTPUT"`
+	RateLimitRPS          float64 `mapstructure:"RATE_LIMIT_RPS"`
+	RateLimitBurst        int     `mapstructure:"RATE_LIMIT_BURST"`
+	LoginMaxAttempts      int     `mapstructure:"LOGIN_MAX_ATTEMPTS_PER_ACCOUNT"`
+	LoginMaxAttemptsPerIP int     `mapstructure:"LOGIN_MAX_ATTEMPTS_PER_IP"`
+	LoginAttemptWindow    int     `mapstructure:"LOGIN_ATTEMPT_WINDOW"`
+	LoginLockoutDuration  int     `mapstructure:"LOGIN_LOCKOUT_DURATION"`
+	TokenAuth             *jwtauth.JWTAuth
+	AccountTokenAuth      *jwtauth.JWTAuth
+}
+
+// defaults valem quando a chave não está no .env nem nas variáveis de ambiente.
+// Sem eles, limites zerados bloqueariam todas as requisições e todos os logins.
+var defaults = map[string]any{
+	"RATE_LIMIT_RPS":                 10.0,
+	"RATE_LIMIT_BURST":               20,
+	"LOGIN_MAX_ATTEMPTS_PER_ACCOUNT": 5,
+	"LOGIN_MAX_ATTEMPTS_PER_IP":      20,
+	"LOGIN_ATTEMPT_WINDOW":           900,
+	"LOGIN_LOCKOUT_DURATION":         900,
 }
 
 func LoadConfig(path string) (*conf, error) {
 	var cfg *conf
-	viper.SetConfigName(".env")
-	viper.SetConfigType("env")
-	viper.AddConfigPath(path)
-	viper.AutomaticEnv() //le as variaveis de ambiente
+	v := viper.New()
+	v.SetConfigName(".env")
+	v.SetConfigType("env")
+	v.AddConfigPath(path)
+	v.AutomaticEnv() //le as variaveis de ambiente
+	for key, value := range defaults {
+		v.SetDefault(key, value)
+	}
 
-	err := viper.ReadInConfig()
+	err := v.ReadInConfig()
 	if err != nil {
 		panic(err)
 	}
 
-	err = viper.Unmarshal(&cfg)
+	err = v.Unmarshal(&cfg)
 	if err != nil {
 		panic(err)
 	}
+	if err := cfg.validate(); err != nil {
+		return nil, err
+	}
 
 	cfg.TokenAuth = jwtauth.New("HS256", []byte(cfg.JWTSecret), nil)
+	// chave separada para que tokens de verificação/reset nunca sejam aceitos como token de acesso
+	cfg.AccountTokenAuth = jwtauth.New("HS256", []byte(cfg.AccountTokenSecret), nil)
 
 	return cfg, nil
 }
+
+// validate recusa limites que travariam a API: taxa 0 responde 429 a tudo e 0 tentativas bloqueia no primeiro erro.
+// Também recusa a chave dos tokens de conta vazia (qualquer um forjaria um reset de senha)
+// ou igual à do JWT de acesso.
+func (c *conf) validate() error {
+	if c.AccountTokenSecret == "" {
+		return fmt.Errorf("ACCOUNT_TOKEN_SECRET is required")
+	}
+	if c.AccountTokenSecret == c.JWTSecret {
+		return fmt.Errorf("ACCOUNT_TOKEN_SECRET must differ from JWT_SECRET")
+	}
+	if c.RateLimitRPS <= 0 {
+		return fmt.Errorf("RATE_LIMIT_RPS must be greater than zero, got %v", c.RateLimitRPS)
+	}
+	positive := []struct {
+		key   string
+		value int
+	}{
+		{"ACCOUNT_TOKEN_EXPIRES_IN", c.AccountTokenExpiresIn},
+		{"RATE_LIMIT_BURST", c.RateLimitBurst},
+		{"LOGIN_MAX_ATTEMPTS_PER_ACCOUNT", c.LoginMaxAttempts},
+		{"LOGIN_MAX_ATTEMPTS_PER_IP", c.LoginMaxAttemptsPerIP},
+		{"LOGIN_ATTEMPT_WINDOW", c.LoginAttemptWindow},
+		{"LOGIN_LOCKOUT_DURATION", c.LoginLockoutDuration},
+	}
+	for _, p := range positive {
+		if p.value <= 0 {
+			return fmt.Errorf("%s must be greater than zero, got %d", p.key, p.value)
+		}
+	}
+	return nil
+}
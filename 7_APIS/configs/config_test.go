@@ -0,0 +1,80 @@
+package configs
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// writeEnv cria um .env mínimo, sem as chaves de rate limit e de login
+func writeEnv(t *testing.T, extra string) string {
+	t.Helper()
+	return writeEnvFile(t, "JWT_SECRET=secret\nACCOUNT_TOKEN_SECRET=account-secret\nACCOUNT_TOKEN_EXPIRES_IN=3600\n"+extra)
+}
+
+func writeEnvFile(t *testing.T, tokens string) string {
+	t.Helper()
+	dir := t.TempDir()
+	env := "DB_DRIVER=sqlite\nDB_NAME=test.db\n" + tokens
+	if err := os.WriteFile(filepath.Join(dir, ".env"), []byte(env), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return dir
+}
+
+func TestLoadConfigDefaultsForMissingLimits(t *testing.T) {
+	cfg, err := LoadConfig(writeEnv(t, ""))
+	assert.NoError(t, err)
+	assert.Equal(t, 10.0, cfg.RateLimitRPS)
+	assert.Equal(t, 20, cfg.RateLimitBurst)
+	assert.Equal(t, 5, cfg.LoginMaxAttempts)
+	assert.Equal(t, 20, cfg.LoginMaxAttemptsPerIP)
+	assert.Equal(t, 900, cfg.LoginAttemptWindow)
+	assert.Equal(t, 900, cfg.LoginLockoutDuration)
+}
+
+func TestLoadConfigReadsLimitsFromEnvironment(t *testing.T) {
+	t.Setenv("RATE_LIMIT_RPS", "2.5")
+	t.Setenv("LOGIN_MAX_ATTEMPTS_PER_ACCOUNT", "3")
+
+	cfg, err := LoadConfig(writeEnv(t, ""))
+	assert.NoError(t, err)
+	assert.Equal(t, 2.5, cfg.RateLimitRPS)
+	assert.Equal(t, 3, cfg.LoginMaxAttempts)
+}
+
+func TestLoadConfigRejectsZeroLimits(t *testing.T) {
+	for _, line := range []string{
+		"ACCOUNT_TOKEN_EXPIRES_IN=0",
+		"RATE_LIMIT_RPS=0",
+		"RATE_LIMIT_BURST=0",
+		"LOGIN_MAX_ATTEMPTS_PER_ACCOUNT=0",
+		"LOGIN_MAX_ATTEMPTS_PER_IP=-1",
+		"LOGIN_ATTEMPT_WINDOW=0",
+		"LOGIN_LOCKOUT_DURATION=0",
+	} {
+		t.Run(line, func(t *testing.T) {
+			_, err := LoadConfig(writeEnv(t, line+"\n"))
+			assert.ErrorContains(t, err, "must be greater than zero")
+		})
+	}
+}
+
+func TestLoadConfigRejectsUnsafeAccountTokenSecret(t *testing.T) {
+	tests := []struct {
+		name string
+		env  string
+		want string
+	}{
+		{"missing", "JWT_SECRET=secret\nACCOUNT_TOKEN_EXPIRES_IN=3600\n", "ACCOUNT_TOKEN_SECRET is required"},
+		{"same as jwt", "JWT_SECRET=secret\nACCOUNT_TOKEN_SECRET=secret\nACCOUNT_TOKEN_EXPIRES_IN=3600\n", "must differ from JWT_SECRET"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := LoadConfig(writeEnvFile(t, tt.env))
+			assert.ErrorContains(t, err, tt.want)
+		})
+	}
+}
@@ -0,0 +1,101 @@
+package database
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"time"
+
+	mysqldriver "github.com/go-sql-driver/mysql"
+	"gorm.io/driver/mysql"
+	"gorm.io/driver/postgres"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+// ConnectionConfig descreve como abrir o banco. Para sqlite, Name é o caminho do arquivo.
+type ConnectionConfig struct {
+	Driver          string
+	Host            string
+	Port            string
+	User            string
+	Password        string
+	Name            string
+	SSLMode         string
+	MaxOpenConns    int
+	MaxIdleConns    int
+	ConnMaxLifetime time.Duration
+}
+
+// Open abre a conexão de acordo com cfg.Driver (sqlite, postgres ou mysql)
+// e aplica as configurações do pool de conexões.
+func Open(cfg ConnectionConfig) (*gorm.DB, error) {
+	dialector, err := Dialector(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	db, err := gorm.Open(dialector, &gorm.Config{TranslateError: true})
+	if err != nil {
+		return nil, err
+	}
+
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, err
+	}
+	if cfg.MaxOpenConns > 0 {
+		sqlDB.SetMaxOpenConns(cfg.MaxOpenConns)
+	}
+	if cfg.MaxIdleConns > 0 {
+		sqlDB.SetMaxIdleConns(cfg.MaxIdleConns)
+	}
+	if cfg.ConnMaxLifetime > 0 {
+		sqlDB.SetConnMaxLifetime(cfg.ConnMaxLifetime)
+	}
+	return db, nil
+}
+
+func Dialector(cfg ConnectionConfig) (gorm.Dialector, error) {
+	switch cfg.Driver {
+	case "sqlite", "sqlite3", "":
+		return sqlite.Open(cfg.Name), nil
+	case "postgres", "postgresql":
+		return postgres.Open(postgresDSN(cfg)), nil
+	case "mysql":
+		return mysql.Open(mysqlDSN(cfg)), nil
+	default:
+		return nil, fmt.Errorf("unsupported DB_DRIVER %q", cfg.Driver)
+	}
+}
+
+// postgresDSN monta a DSN como URL para que usuário, senha e nome do banco com
+// espaços, aspas ou barras não quebrem a string nem injetem outras opções.
+func postgresDSN(cfg ConnectionConfig) string {
+	sslMode := cfg.SSLMode
+	if sslMode == "" {
+		sslMode = "disable"
+	}
+	dsn := url.URL{
+		Scheme:   "postgres",
+		User:     url.UserPassword(cfg.User, cfg.Password),
+		Host:     net.JoinHostPort(cfg.Host, cfg.Port),
+		Path:     "/" + cfg.Name,
+		RawQuery: url.Values{"sslmode": {sslMode}, "TimeZone": {"UTC"}}.Encode(),
+	}
+	return dsn.String()
+}
+
+// mysqlDSN monta a DSN pelo próprio driver, que escapa @, / e ? na senha.
+func mysqlDSN(cfg ConnectionConfig) string {
+	dsn := mysqldriver.NewConfig()
+	dsn.User = cfg.User
+	dsn.Passwd = cfg.Password
+	dsn.Net = "tcp"
+	dsn.Addr = net.JoinHostPort(cfg.Host, cfg.Port)
+	dsn.DBName = cfg.Name
+	dsn.ParseTime = true
+	dsn.Loc = time.UTC
+	dsn.Params = map[string]string{"charset": "utf8mb4"}
+	return dsn.FormatDSN()
+}
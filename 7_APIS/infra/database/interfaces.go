@@ -5,6 +5,8 @@ import "github.com/ElizCarvalho/FC_PosGolang/7_APIS/internal/entity"
 type UserInterface interface {
 	Create(user *entity.User) error
 	FindByEmail(email string) (*entity.User, error)
+	FindByID(id string) (*entity.User, error)
+	Update(user *entity.User) error
 }
 
 type ProductInterface interface {
@@ -12,5 +14,7 @@ type ProductInterface interface {
 	FindAll(page, limit int, sort string) ([]entity.Product, error)
 	FindById(id string) (*entity.Product, error)
 	Update(product *entity.Product) error
-	Delete(id string) error
+	Delete(id string, version int) error
+	CreateInBatches(products []*entity.Product, batchSize int) error
+	Transaction(fn func(repo ProductInterface) error) error
 }
@@ -0,0 +1,157 @@
+package database
+
+import (
+	"testing"
+
+	"github.com/ElizCarvalho/FC_PosGolang/7_APIS/internal/entity"
+	mysqldriver "github.com/go-sql-driver/mysql"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func TestMigrateCreatesSchema(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(testDBDSN), &gorm.Config{TranslateError: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = Migrate(db, Migrations)
+	assert.NoError(t, err)
+
+	var count int64
+	db.Model(&schemaMigration{}).Count(&count)
+	assert.Equal(t, int64(len(Migrations)), count)
+
+	product, _ := entity.NewProduct(testProduct1, testPrice10)
+	assert.NoError(t, NewProductDB(db).Create(product))
+	found, err := NewProductDB(db).FindById(product.ID.String())
+	assert.NoError(t, err)
+	assert.Equal(t, 1, found.Version)
+
+	user, _ := entity.NewUser("John Doe", "john.doe@example.com", testUserPassword)
+	assert.NoError(t, db.Create(user).Error)
+	duplicated, _ := entity.NewUser("John Doe", "john.doe@example.com", testUserPassword)
+	err = db.Create(duplicated).Error
+	assert.ErrorIs(t, err, gorm.ErrDuplicatedKey)
+}
+
+func TestMigrateIsIdempotent(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(testDBDSN), &gorm.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.NoError(t, Migrate(db, Migrations))
+	assert.NoError(t, Migrate(db, Migrations))
+
+	var count int64
+	db.Model(&schemaMigration{}).Count(&count)
+	assert.Equal(t, int64(len(Migrations)), count)
+}
+
+func TestMigrateAdoptsLegacyAutoMigrateSchema(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(testDBDSN), &gorm.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// schema criado pelo AutoMigrate antes das colunas version e email_verified
+	db.Exec("CREATE TABLE `users` (`id` text,`name` text,`email` text,`password` text,PRIMARY KEY (`id`))")
+	db.Exec("CREATE TABLE `products` (`id` text,`name` text,`price` real,`created_at` datetime,PRIMARY KEY (`id`))")
+	db.Exec("INSERT INTO `products` VALUES ('6dba1d1a-2089-4f5c-af73-e1ce4909a7c3', 'Legacy', 10, CURRENT_TIMESTAMP)")
+
+	assert.NoError(t, Migrate(db, Migrations))
+
+	found, err := NewProductDB(db).FindById("6dba1d1a-2089-4f5c-af73-e1ce4909a7c3")
+	assert.NoError(t, err)
+	assert.Equal(t, 1, found.Version)
+	assert.True(t, db.Migrator().HasColumn(&entity.User{}, "EmailVerified"))
+}
+
+func TestMigrateNormalizesLegacyEmails(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(testDBDSN), &gorm.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	db.Exec("CREATE TABLE `users` (`id` text,`name` text,`email` text,`password` text,PRIMARY KEY (`id`))")
+	db.Exec("INSERT INTO `users` VALUES ('6dba1d1a-2089-4f5c-af73-e1ce4909a7c3', 'John', ' John.Doe@Example.com', 'x')")
+
+	assert.NoError(t, Migrate(db, Migrations))
+
+	found, err := NewUserDB(db).FindByEmail("john.doe@example.com")
+	assert.NoError(t, err)
+	assert.Equal(t, "john.doe@example.com", found.Email)
+}
+
+func TestMigrateReportsEmailsThatCollideAfterNormalizing(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(testDBDSN), &gorm.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	db.Exec("CREATE TABLE `users` (`id` text,`name` text,`email` text,`password` text,PRIMARY KEY (`id`))")
+	db.Exec("INSERT INTO `users` VALUES ('6dba1d1a-2089-4f5c-af73-e1ce4909a7c3', 'John', 'John.Doe@example.com', 'x')")
+	db.Exec("INSERT INTO `users` VALUES ('0b0f3c4e-7a5e-4a47-9d55-8f3e2f8a1c11', 'John', 'john.doe@example.com', 'x')")
+
+	err = Migrate(db, Migrations)
+	assert.ErrorContains(t, err, "migration 4_normalize_users_email")
+	assert.ErrorContains(t, err, "john.doe@example.com")
+
+	var emails []string
+	db.Table("users").Order("email").Pluck("email", &emails)
+	assert.Equal(t, []string{"John.Doe@example.com", "john.doe@example.com"}, emails)
+	assert.False(t, db.Migrator().HasIndex(&entity.User{}, "idx_users_email"))
+}
+
+func TestMigrateRejectsDuplicatedVersions(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(testDBDSN), &gorm.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	noop := func(tx *gorm.DB) error { return nil }
+
+	err = Migrate(db, []Migration{{Version: 1, Name: "a", Up: noop}, {Version: 1, Name: "b", Up: noop}})
+	assert.Error(t, err)
+}
+
+func TestDialector(t *testing.T) {
+	for _, driver := range []string{"sqlite", "postgres", "mysql"} {
+		dialector, err := Dialector(ConnectionConfig{Driver: driver, Host: "localhost", Port: "5432", Name: "fullcycle"})
+		assert.NoError(t, err)
+		assert.NotNil(t, dialector)
+	}
+
+	_, err := Dialector(ConnectionConfig{Driver: "oracle"})
+	assert.Error(t, err)
+}
+
+func TestDSNsEscapeCredentials(t *testing.T) {
+	cfg := ConnectionConfig{Host: "db.local", Port: "5432", User: "app user", Password: `p@ss/w?rd ' \ sslmode=disable`, Name: "fullcycle"}
+
+	pg, err := pgconn.ParseConfig(postgresDSN(cfg))
+	assert.NoError(t, err)
+	assert.Equal(t, cfg.User, pg.User)
+	assert.Equal(t, cfg.Password, pg.Password)
+	assert.Equal(t, cfg.Name, pg.Database)
+	assert.Equal(t, "db.local", pg.Host)
+	assert.Equal(t, uint16(5432), pg.Port)
+	assert.Equal(t, "UTC", pg.RuntimeParams["TimeZone"])
+	assert.Nil(t, pg.TLSConfig)
+
+	cfg.Port = "3306"
+	my, err := mysqldriver.ParseDSN(mysqlDSN(cfg))
+	assert.NoError(t, err)
+	assert.Equal(t, cfg.User, my.User)
+	assert.Equal(t, cfg.Password, my.Passwd)
+	assert.Equal(t, "db.local:3306", my.Addr)
+	assert.Equal(t, cfg.Name, my.DBName)
+	assert.True(t, my.ParseTime)
+}
+
+func TestOpenSQLite(t *testing.T) {
+	db, err := Open(ConnectionConfig{Driver: "sqlite", Name: testDBDSN, MaxOpenConns: 1})
+	assert.NoError(t, err)
+
+	sqlDB, _ := db.DB()
+	assert.Equal(t, 1, sqlDB.Stats().MaxOpenConnections)
+}
@@ -0,0 +1,103 @@
+package database
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Cada migration usa sua própria cópia das structs, com o schema daquele momento,
+// para que mudanças futuras nas entidades não alterem migrations já aplicadas.
+// As verificações HasTable/HasColumn/HasIndex permitem adotar bancos criados
+// pelo antigo AutoMigrate.
+var Migrations = []Migration{
+	{Version: 1, Name: "create_users", Up: createUsers},
+	{Version: 2, Name: "create_products", Up: createProducts},
+	{Version: 3, Name: "add_products_version", Up: addProductsVersion},
+	{Version: 4, Name: "normalize_users_email", Up: normalizeUsersEmail},
+	{Version: 5, Name: "add_users_email_verification", Up: addUsersEmailVerification},
+}
+
+type userV1 struct {
+	ID       string `gorm:"type:varchar(36);primaryKey"`
+	Name     string `gorm:"type:varchar(255)"`
+	Email    string `gorm:"type:varchar(255)"`
+	Password string `gorm:"type:varchar(255)"`
+}
+
+func (userV1) TableName() string { return "users" }
+
+func createUsers(tx *gorm.DB) error {
+	if tx.Migrator().HasTable(&userV1{}) {
+		return nil
+	}
+	return tx.Migrator().CreateTable(&userV1{})
+}
+
+type productV2 struct {
+	ID        string `gorm:"type:varchar(36);primaryKey"`
+	Name      string `gorm:"type:varchar(255)"`
+	Price     float64
+	CreatedAt time.Time
+}
+
+func (productV2) TableName() string { return "products" }
+
+func createProducts(tx *gorm.DB) error {
+	if tx.Migrator().HasTable(&productV2{}) {
+		return nil
+	}
+	return tx.Migrator().CreateTable(&productV2{})
+}
+
+type productV3 struct {
+	Version int `gorm:"not null;default:1"`
+}
+
+func (productV3) TableName() string { return "products" }
+
+func addProductsVersion(tx *gorm.DB) error {
+	if tx.Migrator().HasColumn(&productV3{}, "Version") {
+		return nil
+	}
+	return tx.Migrator().AddColumn(&productV3{}, "Version")
+}
+
+// normalizeUsersEmail grava os emails como o FindByEmail os busca (minúsculos e
+// sem espaços) e precisa rodar antes do índice único da versão 5. Emails que só
+// diferem na caixa viram duplicados: a migration falha listando-os, para que
+// as contas sejam unificadas à mão.
+func normalizeUsersEmail(tx *gorm.DB) error {
+	var collisions []string
+	err := tx.Raw("SELECT LOWER(TRIM(email)) FROM users GROUP BY LOWER(TRIM(email)) HAVING COUNT(*) > 1").
+		Scan(&collisions).Error
+	if err != nil {
+		return err
+	}
+	if len(collisions) > 0 {
+		return fmt.Errorf("users share the same normalized email: %s", strings.Join(collisions, ", "))
+	}
+	return tx.Exec("UPDATE users SET email = LOWER(TRIM(email)) WHERE email <> LOWER(TRIM(email))").Error
+}
+
+type userV5 struct {
+	Email         string `gorm:"type:varchar(255);uniqueIndex:idx_users_email"`
+	EmailVerified bool   `gorm:"not null;default:false"`
+}
+
+func (userV5) TableName() string { return "users" }
+
+func addUsersEmailVerification(tx *gorm.DB) error {
+	m := tx.Migrator()
+	if !m.HasColumn(&userV5{}, "EmailVerified") {
+		if err := m.AddColumn(&userV5{}, "EmailVerified"); err != nil {
+			return err
+		}
+	}
+	if m.HasIndex(&userV5{}, "idx_users_email") {
+		return nil
+	}
+	return m.CreateIndex(&userV5{}, "idx_users_email")
+}
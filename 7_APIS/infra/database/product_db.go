@@ -1,10 +1,16 @@
 package database
 
 import (
+	"errors"
+
 	"github.com/ElizCarvalho/FC_PosGolang/7_APIS/internal/entity"
 	"gorm.io/gorm"
 )
 
+// ErrVersionConflict indica que o produto foi alterado por outra requisição
+// depois que a versão informada foi lida.
+var ErrVersionConflict = errors.New("product version conflict")
+
 type ProductDB struct {
 	db *gorm.DB
 }
@@ -23,20 +29,58 @@ func (p *ProductDB) FindById(id string) (*entity.Product, error) {
 	return &product, err
 }
 
+// CreateInBatches insere os produtos em lotes de batchSize linhas por INSERT.
+func (p *ProductDB) CreateInBatches(products []*entity.Product, batchSize int) error {
+	return p.db.CreateInBatches(products, batchSize).Error
+}
+
+// Transaction executa fn com um repositório ligado a uma única transação,
+// fazendo commit se fn retornar nil e rollback caso contrário.
+func (p *ProductDB) Transaction(fn func(repo ProductInterface) error) error {
+	return p.db.Transaction(func(tx *gorm.DB) error {
+		return fn(NewProductDB(tx))
+	})
+}
+
+// Update grava o produto somente se a versão no banco ainda for product.Version,
+// incrementando a versão em caso de sucesso.
 func (p *ProductDB) Update(product *entity.Product) error {
-	_, err := p.FindById(product.ID.String())
-	if err != nil {
-		return err
+	result := p.db.Model(&entity.Product{}).
+		Where("id = ? AND version = ?", product.ID.String(), product.Version).
+		Updates(map[string]interface{}{
+			"name":    product.Name,
+			"price":   product.Price,
+			"version": gorm.Expr("version + 1"),
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return p.conflictOrNotFound(product.ID.String())
+	}
+	product.Version++
+	return nil
+}
+
+// Delete remove o produto somente se a versão no banco ainda for version.
+func (p *ProductDB) Delete(id string, version int) error {
+	result := p.db.Where("id = ? AND version = ?", id, version).Delete(&entity.Product{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return p.conflictOrNotFound(id)
 	}
-	return p.db.Save(product).Error
+	return nil
 }
 
-func (p *ProductDB) Delete(id string) error {
-	_, err := p.FindById(id)
-	if err != nil {
+// conflictOrNotFound diferencia um produto inexistente de um produto com versão divergente
+// quando uma operação condicional não afeta nenhuma linha.
+func (p *ProductDB) conflictOrNotFound(id string) error {
+	if _, err := p.FindById(id); err != nil {
 		return err
 	}
-	return p.db.Delete(&entity.Product{}, "id = ?", id).Error
+	return ErrVersionConflict
 }
 
 func (p *ProductDB) FindAll(page, limit int, sort string) ([]entity.Product, error) {
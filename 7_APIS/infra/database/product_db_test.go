@@ -108,6 +108,53 @@ func TestUpdateProduct(t *testing.T) {
 	productDB := NewProductDB(db)
 	err = productDB.Update(product)
 	assert.NoError(t, err)
+	assert.Equal(t, 2, product.Version)
+
+	productFound, err := productDB.FindById(product.ID.String())
+	assert.NoError(t, err)
+	assert.Equal(t, testProduct2, productFound.Name)
+	assert.Equal(t, 2, productFound.Version)
+}
+
+func TestUpdateProductWithStaleVersion(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(testDBDSN), &gorm.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	db.AutoMigrate(&entity.Product{})
+
+	product, _ := entity.NewProduct(testProduct1, testPrice10)
+	db.Create(product)
+
+	productDB := NewProductDB(db)
+	first, _ := productDB.FindById(product.ID.String())
+	second, _ := productDB.FindById(product.ID.String())
+
+	first.Name = testProduct2
+	err = productDB.Update(first)
+	assert.NoError(t, err)
+
+	second.Price = testPrice20
+	err = productDB.Update(second)
+	assert.ErrorIs(t, err, ErrVersionConflict)
+
+	productFound, err := productDB.FindById(product.ID.String())
+	assert.NoError(t, err)
+	assert.Equal(t, testProduct2, productFound.Name)
+	assert.Equal(t, testPrice10, productFound.Price)
+}
+
+func TestUpdateProductNotFound(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(testDBDSN), &gorm.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	db.AutoMigrate(&entity.Product{})
+
+	product, _ := entity.NewProduct(testProduct1, testPrice10)
+	productDB := NewProductDB(db)
+	err = productDB.Update(product)
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
 }
 
 func TestDeleteProduct(t *testing.T) {
@@ -120,10 +167,74 @@ func TestDeleteProduct(t *testing.T) {
 	db.Create(product)
 
 	productDB := NewProductDB(db)
-	err = productDB.Delete(product.ID.String())
+	err = productDB.Delete(product.ID.String(), product.Version)
 	assert.NoError(t, err)
 
 	productFound, err := productDB.FindById(product.ID.String())
 	assert.Error(t, err)
 	assert.Empty(t, productFound)
 }
+
+func TestDeleteProductWithStaleVersion(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(testDBDSN), &gorm.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	db.AutoMigrate(&entity.Product{})
+	product, _ := entity.NewProduct(testProduct1, testPrice10)
+	db.Create(product)
+
+	productDB := NewProductDB(db)
+	err = productDB.Delete(product.ID.String(), product.Version+1)
+	assert.ErrorIs(t, err, ErrVersionConflict)
+
+	_, err = productDB.FindById(product.ID.String())
+	assert.NoError(t, err)
+}
+
+func TestCreateProductsInBatches(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(testDBDSN), &gorm.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	db.AutoMigrate(&entity.Product{})
+
+	products := make([]*entity.Product, 25)
+	for i := range products {
+		products[i], _ = entity.NewProduct(fmt.Sprintf("Product %d", i), testPrice10)
+	}
+
+	productDB := NewProductDB(db)
+	err = productDB.CreateInBatches(products, 10)
+	assert.NoError(t, err)
+
+	found, err := productDB.FindAll(0, 0, "asc")
+	assert.NoError(t, err)
+	assert.Len(t, found, 25)
+}
+
+func TestTransactionRollsBackOnError(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(testDBDSN), &gorm.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	db.AutoMigrate(&entity.Product{})
+
+	product, _ := entity.NewProduct(testProduct1, testPrice10)
+	db.Create(product)
+
+	productDB := NewProductDB(db)
+	err = productDB.Transaction(func(repo ProductInterface) error {
+		product.Name = testProduct2
+		if err := repo.Update(product); err != nil {
+			return err
+		}
+		return repo.Delete(product.ID.String(), 1)
+	})
+	assert.ErrorIs(t, err, ErrVersionConflict)
+
+	productFound, err := productDB.FindById(product.ID.String())
+	assert.NoError(t, err)
+	assert.Equal(t, testProduct1, productFound.Name)
+	assert.Equal(t, 1, productFound.Version)
+}
@@ -1,10 +1,15 @@
 package database
 
 import (
+	"errors"
+
 	"github.com/ElizCarvalho/FC_PosGolang/7_APIS/internal/entity"
 	"gorm.io/gorm"
 )
 
+// ErrEmailAlreadyExists indica que já existe outro usuário com o mesmo email.
+var ErrEmailAlreadyExists = errors.New("email already registered")
+
 type UserDB struct {
 	db *gorm.DB
 }
@@ -14,13 +19,54 @@ func NewUserDB(db *gorm.DB) *UserDB {
 }
 
 func (u *UserDB) Create(user *entity.User) error {
-	return u.db.Create(user).Error
+	if err := u.checkEmailAvailable(user); err != nil {
+		return err
+	}
+	return translateUserError(u.db.Create(user).Error)
 }
 
 func (u *UserDB) FindByEmail(email string) (*entity.User, error) {
 	var user entity.User
-	if err := u.db.Where("email = ?", email).First(&user).Error; err != nil {
+	if err := u.db.Where("email = ?", entity.NormalizeEmail(email)).First(&user).Error; err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+func (u *UserDB) FindByID(id string) (*entity.User, error) {
+	var user entity.User
+	if err := u.db.Where("id = ?", id).First(&user).Error; err != nil {
 		return nil, err
 	}
 	return &user, nil
 }
+
+func (u *UserDB) Update(user *entity.User) error {
+	if err := u.checkEmailAvailable(user); err != nil {
+		return err
+	}
+	return translateUserError(u.db.Save(user).Error)
+}
+
+// checkEmailAvailable antecipa o erro de email duplicado; o índice único
+// continua sendo a garantia final em caso de concorrência.
+func (u *UserDB) checkEmailAvailable(user *entity.User) error {
+	var count int64
+	err := u.db.Model(&entity.User{}).
+		Where("email = ? AND id <> ?", user.Email, user.ID.String()).
+		Count(&count).Error
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return ErrEmailAlreadyExists
+	}
+	return nil
+}
+
+func translateUserError(err error) error {
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		return ErrEmailAlreadyExists
+	}
+	return err
+}
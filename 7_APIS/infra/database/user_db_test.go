@@ -9,6 +9,8 @@ import (
 	"gorm.io/gorm"
 )
 
+const testUserPassword = "secret123"
+
 func TestCreateUser(t *testing.T) {
 	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{})
 	if err != nil {
@@ -16,7 +18,7 @@ func TestCreateUser(t *testing.T) {
 	}
 	db.AutoMigrate(&entity.User{})
 
-	user, _ := entity.NewUser("John Doe", "john.doe@example.com", "123456")
+	user, _ := entity.NewUser("John Doe", "john.doe@example.com", testUserPassword)
 	userDB := NewUserDB(db)
 
 	err = userDB.Create(user)
@@ -39,7 +41,7 @@ func TestFindByEmail(t *testing.T) {
 	}
 	db.AutoMigrate(&entity.User{})
 
-	user, _ := entity.NewUser("John Doe", "john.doe@example.com", "123456")
+	user, _ := entity.NewUser("John Doe", "john.doe@example.com", testUserPassword)
 	userDB := NewUserDB(db)
 	err = userDB.Create(user)
 	assert.Nil(t, err)
@@ -51,3 +53,67 @@ func TestFindByEmail(t *testing.T) {
 	assert.Equal(t, user.Email, userFound.Email)
 	assert.NotEmpty(t, userFound.Password)
 }
+
+func TestCreateUserWithDuplicatedEmail(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{TranslateError: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	db.AutoMigrate(&entity.User{})
+
+	user, _ := entity.NewUser("John Doe", "john.doe@example.com", testUserPassword)
+	userDB := NewUserDB(db)
+	err = userDB.Create(user)
+	assert.Nil(t, err)
+
+	other, _ := entity.NewUser("Johnny", "John.Doe@example.com", testUserPassword)
+	err = userDB.Create(other)
+	assert.ErrorIs(t, err, ErrEmailAlreadyExists)
+}
+
+func TestFindUserByID(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	db.AutoMigrate(&entity.User{})
+
+	user, _ := entity.NewUser("John Doe", "john.doe@example.com", testUserPassword)
+	userDB := NewUserDB(db)
+	userDB.Create(user)
+
+	userFound, err := userDB.FindByID(user.ID.String())
+	assert.Nil(t, err)
+	assert.Equal(t, user.Email, userFound.Email)
+
+	missing, _ := entity.NewUser("Jane Doe", "jane.doe@example.com", testUserPassword)
+	_, err = userDB.FindByID(missing.ID.String())
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+}
+
+func TestUpdateUser(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	db.AutoMigrate(&entity.User{})
+
+	john, _ := entity.NewUser("John Doe", "john.doe@example.com", testUserPassword)
+	jane, _ := entity.NewUser("Jane Doe", "jane.doe@example.com", testUserPassword)
+	userDB := NewUserDB(db)
+	userDB.Create(john)
+	userDB.Create(jane)
+
+	john.Name = "John Smith"
+	john.VerifyEmail()
+	err = userDB.Update(john)
+	assert.Nil(t, err)
+
+	userFound, _ := userDB.FindByID(john.ID.String())
+	assert.Equal(t, "John Smith", userFound.Name)
+	assert.True(t, userFound.EmailVerified)
+
+	john.ChangeEmail(jane.Email)
+	err = userDB.Update(john)
+	assert.ErrorIs(t, err, ErrEmailAlreadyExists)
+}
@@ -5,12 +5,57 @@ type CreateProductInput struct {
 	Price float64 `json:"price"`
 }
 
+type UpdateProductBatchInput struct {
+	ID      string  `json:"id"`
+	Version int     `json:"version"`
+	Name    string  `json:"name"`
+	Price   float64 `json:"price"`
+}
+
+type DeleteProductBatchInput struct {
+	ID      string `json:"id"`
+	Version int    `json:"version"`
+}
+
+type BatchItemResult struct {
+	Index   int    `json:"index"`
+	ID      string `json:"id,omitempty"`
+	Version int    `json:"version,omitempty"`
+	Status  string `json:"status"`
+	Error   string `json:"error,omitempty"`
+}
+
+type BatchOutput struct {
+	Mode      string            `json:"mode"`
+	Succeeded int               `json:"succeeded"`
+	Failed    int               `json:"failed"`
+	Results   []BatchItemResult `json:"results"`
+}
+
 type CreateUserInput struct {
 	Name     string `json:"name"`
 	Email    string `json:"email"`
 	Password string `json:"password"`
 }
 
+type UpdateProfileInput struct {
+	Name  string `json:"name"`
+	Email string `json:"email"`
+}
+
+type EmailInput struct {
+	Email string `json:"email"`
+}
+
+type VerifyEmailInput struct {
+	Token string `json:"token"`
+}
+
+type ResetPasswordInput struct {
+	Token    string `json:"token"`
+	Password string `json:"password"`
+}
+
 type LoginInput struct {
 	Email    string `json:"email"`
 	Password string `json:"password"`
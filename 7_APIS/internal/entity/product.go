@@ -19,6 +19,7 @@ type Product struct {
 	ID        entity.ID `json:"id"`
 	Name      string    `json:"name"`
 	Price     float64   `json:"price"`
+	Version   int       `json:"version" gorm:"not null;default:1"`
 	CreatedAt time.Time `json:"created_at"`
 }
 
@@ -27,6 +28,7 @@ func NewProduct(name string, price float64) (*Product, error) {
 		ID:        entity.NewID(),
 		Name:      name,
 		Price:     price,
+		Version:   1,
 		CreatedAt: time.Now(),
 	}
 	err := product.Validate()
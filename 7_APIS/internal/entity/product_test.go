@@ -16,6 +16,7 @@ func TestNewProduct(t *testing.T) {
 	assert.Equal(t, testProductName, p.Name)
 	assert.Equal(t, testProductPrice, p.Price)
 	assert.NotEmpty(t, p.ID)
+	assert.Equal(t, 1, p.Version)
 	assert.NotEmpty(t, p.CreatedAt)
 	assert.False(t, p.CreatedAt.IsZero())
 }
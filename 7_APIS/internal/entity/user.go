@@ -1,32 +1,124 @@
 package entity
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/mail"
+	"strings"
+	"unicode"
+
 	"github.com/ElizCarvalho/FC_PosGolang/7_APIS/pkg/entity"
 	"golang.org/x/crypto/bcrypt"
 )
 
+const MinPasswordLength = 8
+
+var (
+	ErrEmailIsRequired = errors.New("email is required")
+	ErrInvalidEmail    = errors.New("invalid email")
+	ErrWeakPassword    = errors.New("password must have at least 8 characters, including letters and numbers")
+)
+
 // VO - Value Object
 type User struct {
-	ID       entity.ID `json:"id"`
-	Name     string    `json:"name"`
-	Email    string    `json:"email"`
-	Password string    `json:"-"`
+	ID            entity.ID `json:"id"`
+	Name          string    `json:"name"`
+	Email         string    `json:"email" gorm:"uniqueIndex"`
+	EmailVerified bool      `json:"email_verified"`
+	Password      string    `json:"-"`
 }
 
 func NewUser(name, email, password string) (*User, error) {
-	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
-	if err != nil {
+	user := &User{
+		ID:    entity.NewID(),
+		Name:  name,
+		Email: NormalizeEmail(email),
+	}
+	if err := user.Validate(); err != nil {
+		return nil, err
+	}
+	if err := user.ChangePassword(password); err != nil {
 		return nil, err
 	}
-	return &User{
-		ID:       entity.NewID(),
-		Name:     name,
-		Email:    email,
-		Password: string(hash),
-	}, nil
+	return user, nil
+}
+
+func (u *User) Validate() error {
+	if u.Name == "" {
+		return ErrNameIsRequired
+	}
+	if u.Email == "" {
+		return ErrEmailIsRequired
+	}
+	addr, err := mail.ParseAddress(u.Email)
+	if err != nil || addr.Address != u.Email {
+		return ErrInvalidEmail
+	}
+	return nil
 }
 
 func (u *User) ValidatePassword(password string) bool {
 	err := bcrypt.CompareHashAndPassword([]byte(u.Password), []byte(password))
 	return err == nil
 }
+
+// ChangePassword valida a força da nova senha e grava o seu hash
+func (u *User) ChangePassword(password string) error {
+	if !isStrongPassword(password) {
+		return ErrWeakPassword
+	}
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return err
+	}
+	u.Password = string(hash)
+	return nil
+}
+
+// ChangeEmail troca o email do usuário, que volta a ficar não verificado
+func (u *User) ChangeEmail(email string) error {
+	email = NormalizeEmail(email)
+	if email == u.Email {
+		return nil
+	}
+	previous := u.Email
+	u.Email = email
+	if err := u.Validate(); err != nil {
+		u.Email = previous
+		return err
+	}
+	u.EmailVerified = false
+	return nil
+}
+
+func (u *User) VerifyEmail() {
+	u.EmailVerified = true
+}
+
+// Fingerprint resume o email e o hash da senha atuais. Tokens de conta carregam
+// esse valor para deixarem de valer assim que o email ou a senha mudarem.
+func (u *User) Fingerprint() string {
+	sum := sha256.Sum256([]byte(u.Email + ":" + u.Password))
+	return hex.EncodeToString(sum[:16])
+}
+
+func NormalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+func isStrongPassword(password string) bool {
+	if len(password) < MinPasswordLength {
+		return false
+	}
+	var hasLetter, hasDigit bool
+	for _, r := range password {
+		switch {
+		case unicode.IsLetter(r):
+			hasLetter = true
+		case unicode.IsDigit(r):
+			hasDigit = true
+		}
+	}
+	return hasLetter && hasDigit
+}
@@ -9,17 +9,18 @@ import (
 const (
 	testUserName  = "John Doe"
 	testUserEmail = "john.doe@example.com"
-	testPassword  = "123456"
+	testPassword  = "secret123"
 )
 
 func TestNewUser(t *testing.T) {
-	user, err := NewUser(testUserName, testUserEmail, "password")
+	user, err := NewUser(testUserName, testUserEmail, "password1")
 	assert.Nil(t, err)
 	assert.NotNil(t, user)
 	assert.Equal(t, testUserName, user.Name)
 	assert.Equal(t, testUserEmail, user.Email)
 	assert.NotEmpty(t, user.ID)
 	assert.NotEmpty(t, user.Password)
+	assert.False(t, user.EmailVerified)
 }
 
 func TestUserValidatePassword(t *testing.T) {
@@ -29,3 +30,61 @@ func TestUserValidatePassword(t *testing.T) {
 	assert.False(t, user.ValidatePassword("123456789"))
 	assert.NotEqual(t, testPassword, user.Password)
 }
+
+func TestNewUserNormalizesEmail(t *testing.T) {
+	user, err := NewUser(testUserName, "  John.Doe@Example.COM ", testPassword)
+	assert.Nil(t, err)
+	assert.Equal(t, testUserEmail, user.Email)
+}
+
+func TestNewUserWhenEmailIsInvalid(t *testing.T) {
+	for _, email := range []string{"john.doe", "john.doe@", "John <john.doe@example.com>"} {
+		user, err := NewUser(testUserName, email, testPassword)
+		assert.Nil(t, user)
+		assert.Equal(t, ErrInvalidEmail, err, email)
+	}
+
+	user, err := NewUser(testUserName, "", testPassword)
+	assert.Nil(t, user)
+	assert.Equal(t, ErrEmailIsRequired, err)
+}
+
+func TestNewUserWhenNameIsRequired(t *testing.T) {
+	user, err := NewUser("", testUserEmail, testPassword)
+	assert.Nil(t, user)
+	assert.Equal(t, ErrNameIsRequired, err)
+}
+
+func TestNewUserWhenPasswordIsWeak(t *testing.T) {
+	for _, password := range []string{"123456", "abc12", "password", "12345678"} {
+		user, err := NewUser(testUserName, testUserEmail, password)
+		assert.Nil(t, user)
+		assert.Equal(t, ErrWeakPassword, err, password)
+	}
+}
+
+func TestUserChangeEmail(t *testing.T) {
+	user, _ := NewUser(testUserName, testUserEmail, testPassword)
+	user.VerifyEmail()
+
+	err := user.ChangeEmail("invalid")
+	assert.Equal(t, ErrInvalidEmail, err)
+	assert.Equal(t, testUserEmail, user.Email)
+	assert.True(t, user.EmailVerified)
+
+	err = user.ChangeEmail("jane.doe@example.com")
+	assert.Nil(t, err)
+	assert.Equal(t, "jane.doe@example.com", user.Email)
+	assert.False(t, user.EmailVerified)
+}
+
+func TestUserFingerprintChangesWithPassword(t *testing.T) {
+	user, _ := NewUser(testUserName, testUserEmail, testPassword)
+	fingerprint := user.Fingerprint()
+	assert.Equal(t, fingerprint, user.Fingerprint())
+
+	err := user.ChangePassword("another123")
+	assert.Nil(t, err)
+	assert.NotEqual(t, fingerprint, user.Fingerprint())
+	assert.True(t, user.ValidatePassword("another123"))
+}
@@ -0,0 +1,357 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+
+	"github.com/ElizCarvalho/FC_PosGolang/7_APIS/infra/database"
+	"github.com/ElizCarvalho/FC_PosGolang/7_APIS/internal/dto"
+	"github.com/ElizCarvalho/FC_PosGolang/7_APIS/internal/entity"
+	pkgEntity "github.com/ElizCarvalho/FC_PosGolang/7_APIS/pkg/entity"
+)
+
+const (
+	BatchModeAtomic     = "atomic"
+	BatchModeBestEffort = "best_effort"
+
+	ErrInvalidBatchMode = "mode must be atomic or best_effort"
+	ErrEmptyBatch       = "batch must contain at least one item"
+
+	maxBatchItems   = 10000
+	maxBatchBytes   = 8 << 20
+	createBatchSize = 100
+)
+
+var (
+	errBatchRolledBack = errors.New("rolled back: another item in the batch failed")
+	errBatchTooLarge   = fmt.Errorf("batch must contain at most %d items", maxBatchItems)
+	errBatchNotArray   = errors.New("batch must be a JSON array")
+)
+
+// Create products batch godoc
+// @Summary Create products in batch
+// @Description Create products from a JSON array or NDJSON body. In atomic mode (default) nothing is written if any item fails; in best_effort mode every valid item is written.
+// @Tags products
+// @Accept json
+// @Accept application/x-ndjson
+// @Produce json
+// @Param mode query string false "atomic or best_effort" Enums(atomic, best_effort)
+// @Param  request body []dto.CreateProductInput true "products request"
+// @Success 201 {object} dto.BatchOutput
+// @Success 207 {object} dto.BatchOutput
+// @Failure 400 {object} dto.Error
+// @Failure 413 {object} dto.Error
+// @Failure 422 {object} dto.BatchOutput
+// @Router /products/batch [post]
+// @Security ApiKeyAuth
+func (h *ProductHandler) CreateProductsBatch(w http.ResponseWriter, r *http.Request) {
+	mode, items, ok := decodeBatchRequest[dto.CreateProductInput](w, r)
+	if !ok {
+		return
+	}
+
+	products := make([]*entity.Product, len(items))
+	errs := make([]error, len(items))
+	valid := make([]*entity.Product, 0, len(items))
+	for i, item := range items {
+		products[i], errs[i] = entity.NewProduct(item.Name, item.Price)
+		if errs[i] == nil {
+			valid = append(valid, products[i])
+		}
+	}
+
+	if mode == BatchModeAtomic && len(valid) < len(items) {
+		markRolledBack(errs, nil)
+		writeBatchOutput(w, mode, http.StatusCreated, "created", products, errs)
+		return
+	}
+
+	err := h.ProductDB.Transaction(func(tx database.ProductInterface) error {
+		return tx.CreateInBatches(valid, createBatchSize)
+	})
+	if err != nil {
+		if mode == BatchModeAtomic {
+			markRolledBack(errs, err)
+		} else {
+			// o lote falhou inteiro, então cada item é reenviado isoladamente
+			// para descobrir quais deles são de fato inválidos
+			for i, p := range products {
+				if p != nil {
+					errs[i] = h.ProductDB.Create(p)
+				}
+			}
+		}
+	}
+
+	writeBatchOutput(w, mode, http.StatusCreated, "created", products, errs)
+}
+
+// Update products batch godoc
+// @Summary Update products in batch
+// @Description Update products from a JSON array or NDJSON body. Each item carries the version it was read at; stale versions fail with a conflict.
+// @Tags products
+// @Accept json
+// @Accept application/x-ndjson
+// @Produce json
+// @Param mode query string false "atomic or best_effort" Enums(atomic, best_effort)
+// @Param  request body []dto.UpdateProductBatchInput true "products request"
+// @Success 200 {object} dto.BatchOutput
+// @Success 207 {object} dto.BatchOutput
+// @Failure 400 {object} dto.Error
+// @Failure 413 {object} dto.Error
+// @Failure 422 {object} dto.BatchOutput
+// @Router /products/batch [put]
+// @Security ApiKeyAuth
+func (h *ProductHandler) UpdateProductsBatch(w http.ResponseWriter, r *http.Request) {
+	mode, items, ok := decodeBatchRequest[dto.UpdateProductBatchInput](w, r)
+	if !ok {
+		return
+	}
+
+	products := make([]*entity.Product, len(items))
+	errs := make([]error, len(items))
+	for i, item := range items {
+		id, err := pkgEntity.ParseID(item.ID)
+		if err != nil {
+			errs[i] = entity.ErrInvalidId
+			continue
+		}
+		products[i] = &entity.Product{ID: id, Name: item.Name, Price: item.Price, Version: item.Version}
+		errs[i] = products[i].Validate()
+	}
+
+	h.applyBatch(mode, products, errs, func(repo database.ProductInterface, p *entity.Product) error {
+		return repo.Update(p)
+	})
+	writeBatchOutput(w, mode, http.StatusOK, "updated", products, errs)
+}
+
+// Delete products batch godoc
+// @Summary Delete products in batch
+// @Description Delete products from a JSON array or NDJSON body. Each item carries the version it was read at; stale versions fail with a conflict.
+// @Tags products
+// @Accept json
+// @Accept application/x-ndjson
+// @Produce json
+// @Param mode query string false "atomic or best_effort" Enums(atomic, best_effort)
+// @Param  request body []dto.DeleteProductBatchInput true "products request"
+// @Success 200 {object} dto.BatchOutput
+// @Success 207 {object} dto.BatchOutput
+// @Failure 400 {object} dto.Error
+// @Failure 413 {object} dto.Error
+// @Failure 422 {object} dto.BatchOutput
+// @Router /products/batch [delete]
+// @Security ApiKeyAuth
+func (h *ProductHandler) DeleteProductsBatch(w http.ResponseWriter, r *http.Request) {
+	mode, items, ok := decodeBatchRequest[dto.DeleteProductBatchInput](w, r)
+	if !ok {
+		return
+	}
+
+	products := make([]*entity.Product, len(items))
+	errs := make([]error, len(items))
+	for i, item := range items {
+		id, err := pkgEntity.ParseID(item.ID)
+		if err != nil {
+			errs[i] = entity.ErrInvalidId
+			continue
+		}
+		products[i] = &entity.Product{ID: id, Version: item.Version}
+	}
+
+	h.applyBatch(mode, products, errs, func(repo database.ProductInterface, p *entity.Product) error {
+		return repo.Delete(p.ID.String(), p.Version)
+	})
+	writeBatchOutput(w, mode, http.StatusOK, "deleted", products, errs)
+}
+
+// applyBatch executa op para cada produto que passou na validação. No modo atomic
+// todas as operações rodam na mesma transação e a primeira falha desfaz o lote;
+// no modo best_effort cada operação é independente.
+func (h *ProductHandler) applyBatch(mode string, products []*entity.Product, errs []error, op func(database.ProductInterface, *entity.Product) error) {
+	if mode == BatchModeBestEffort {
+		for i, p := range products {
+			if errs[i] == nil {
+				errs[i] = op(h.ProductDB, p)
+			}
+		}
+		return
+	}
+
+	for _, err := range errs {
+		if err != nil {
+			markRolledBack(errs, nil)
+			return
+		}
+	}
+
+	err := h.ProductDB.Transaction(func(tx database.ProductInterface) error {
+		for i, p := range products {
+			if err := op(tx, p); err != nil {
+				errs[i] = err
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		markRolledBack(errs, err)
+	}
+}
+
+// markRolledBack marca como desfeitos os itens que não falharam. Se nenhum item
+// tiver falhado por conta própria, todos recebem cause.
+func markRolledBack(errs []error, cause error) {
+	failed := false
+	for _, err := range errs {
+		if err != nil {
+			failed = true
+			break
+		}
+	}
+	for i := range errs {
+		if errs[i] != nil {
+			continue
+		}
+		if failed || cause == nil {
+			errs[i] = errBatchRolledBack
+		} else {
+			errs[i] = cause
+		}
+	}
+}
+
+// decodeBatchRequest lê o modo da query string e os itens do corpo, que pode ser
+// um array JSON ou NDJSON (um objeto por linha).
+func decodeBatchRequest[T any](w http.ResponseWriter, r *http.Request) (string, []T, bool) {
+	mode := r.URL.Query().Get("mode")
+	if mode == "" {
+		mode = BatchModeAtomic
+	}
+	if mode != BatchModeAtomic && mode != BatchModeBestEffort {
+		w.WriteHeader(http.StatusBadRequest)
+		error := dto.Error{Message: ErrInvalidBatchMode}
+		json.NewEncoder(w).Encode(error)
+		return "", nil, false
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxBatchBytes)
+	items, err := decodeBatchItems[T](r)
+	var bodyTooLarge *http.MaxBytesError
+	if errors.As(err, &bodyTooLarge) {
+		w.WriteHeader(http.StatusRequestEntityTooLarge)
+		error := dto.Error{Message: fmt.Sprintf("batch body must be at most %d bytes", bodyTooLarge.Limit)}
+		json.NewEncoder(w).Encode(error)
+		return "", nil, false
+	}
+	if errors.Is(err, errBatchTooLarge) {
+		w.WriteHeader(http.StatusRequestEntityTooLarge)
+		error := dto.Error{Message: err.Error()}
+		json.NewEncoder(w).Encode(error)
+		return "", nil, false
+	}
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		error := dto.Error{Message: err.Error()}
+		json.NewEncoder(w).Encode(error)
+		return "", nil, false
+	}
+	if len(items) == 0 {
+		w.WriteHeader(http.StatusBadRequest)
+		error := dto.Error{Message: ErrEmptyBatch}
+		json.NewEncoder(w).Encode(error)
+		return "", nil, false
+	}
+	return mode, items, true
+}
+
+// decodeBatchItems lê um item por vez, tanto no array JSON quanto no NDJSON,
+// e para assim que o lote passa de maxBatchItems.
+func decodeBatchItems[T any](r *http.Request) ([]T, error) {
+	var items []T
+	decoder := json.NewDecoder(r.Body)
+
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if mediaType != "application/x-ndjson" && mediaType != "application/ndjson" {
+		token, err := decoder.Token()
+		if err != nil {
+			return nil, err
+		}
+		if delim, ok := token.(json.Delim); !ok || delim != '[' {
+			return nil, errBatchNotArray
+		}
+		for decoder.More() {
+			var item T
+			if err := decoder.Decode(&item); err != nil {
+				return nil, fmt.Errorf("item %d: %w", len(items), err)
+			}
+			items = append(items, item)
+			if len(items) > maxBatchItems {
+				return nil, errBatchTooLarge
+			}
+		}
+		if _, err := decoder.Token(); err != nil {
+			return nil, err
+		}
+		return items, nil
+	}
+
+	for {
+		var item T
+		err := decoder.Decode(&item)
+		if err == io.EOF {
+			return items, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("item %d: %w", len(items), err)
+		}
+		items = append(items, item)
+		if len(items) > maxBatchItems {
+			return nil, errBatchTooLarge
+		}
+	}
+}
+
+// writeBatchOutput responde successStatus quando todos os itens foram aplicados,
+// 422 quando um lote atomic foi desfeito e 207 quando um lote best_effort falhou em parte.
+func writeBatchOutput(w http.ResponseWriter, mode string, successStatus int, doneStatus string, products []*entity.Product, errs []error) {
+	output := dto.BatchOutput{Mode: mode, Results: make([]dto.BatchItemResult, len(errs))}
+	for i, err := range errs {
+		result := dto.BatchItemResult{Index: i, Status: doneStatus}
+		if products[i] != nil {
+			result.ID = products[i].ID.String()
+		}
+		switch {
+		case err == nil:
+			output.Succeeded++
+			if doneStatus != "deleted" {
+				result.Version = products[i].Version
+			}
+		case errors.Is(err, errBatchRolledBack):
+			output.Failed++
+			result.Status = "rolled_back"
+			result.Error = err.Error()
+		default:
+			output.Failed++
+			result.Status = "failed"
+			result.Error = err.Error()
+		}
+		output.Results[i] = result
+	}
+
+	status := successStatus
+	if output.Failed > 0 {
+		status = http.StatusMultiStatus
+		if mode == BatchModeAtomic {
+			status = http.StatusUnprocessableEntity
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(output)
+}
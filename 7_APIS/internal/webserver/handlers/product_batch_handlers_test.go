@@ -0,0 +1,153 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ElizCarvalho/FC_PosGolang/7_APIS/infra/database"
+	"github.com/ElizCarvalho/FC_PosGolang/7_APIS/internal/dto"
+	"github.com/ElizCarvalho/FC_PosGolang/7_APIS/internal/entity"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func newTestProductHandler(t *testing.T) (*ProductHandler, *gorm.DB) {
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sqlDB, _ := db.DB()
+	sqlDB.SetMaxOpenConns(1)
+	db.AutoMigrate(&entity.Product{})
+	return NewProductHandler(database.NewProductDB(db)), db
+}
+
+func doBatch(handler http.HandlerFunc, method, target, contentType, body string) (*httptest.ResponseRecorder, dto.BatchOutput) {
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	req.Header.Set("Content-Type", contentType)
+	rec := httptest.NewRecorder()
+	handler(rec, req)
+
+	var output dto.BatchOutput
+	json.NewDecoder(rec.Body).Decode(&output)
+	return rec, output
+}
+
+func TestCreateProductsBatchAtomic(t *testing.T) {
+	h, db := newTestProductHandler(t)
+
+	rec, output := doBatch(h.CreateProductsBatch, http.MethodPost, "/products/batch", "application/json",
+		`[{"name":"Product 1","price":10},{"name":"Product 2","price":20}]`)
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	assert.Equal(t, 2, output.Succeeded)
+	assert.Equal(t, "created", output.Results[0].Status)
+	assert.Equal(t, 1, output.Results[0].Version)
+
+	rec, output = doBatch(h.CreateProductsBatch, http.MethodPost, "/products/batch", "application/json",
+		`[{"name":"Product 3","price":30},{"name":"","price":40}]`)
+	assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+	assert.Equal(t, "rolled_back", output.Results[0].Status)
+	assert.Equal(t, "failed", output.Results[1].Status)
+	assert.Equal(t, entity.ErrNameIsRequired.Error(), output.Results[1].Error)
+
+	var count int64
+	db.Model(&entity.Product{}).Count(&count)
+	assert.Equal(t, int64(2), count)
+}
+
+func TestCreateProductsBatchBestEffortNDJSON(t *testing.T) {
+	h, db := newTestProductHandler(t)
+
+	body := `{"name":"Product 1","price":10}
+{"name":"Product 2","price":-1}
+{"name":"Product 3","price":30}
+`
+	rec, output := doBatch(h.CreateProductsBatch, http.MethodPost, "/products/batch?mode=best_effort", "application/x-ndjson", body)
+	assert.Equal(t, http.StatusMultiStatus, rec.Code)
+	assert.Equal(t, 2, output.Succeeded)
+	assert.Equal(t, 1, output.Failed)
+	assert.Equal(t, entity.ErrInvalidPrice.Error(), output.Results[1].Error)
+
+	var count int64
+	db.Model(&entity.Product{}).Count(&count)
+	assert.Equal(t, int64(2), count)
+}
+
+func TestUpdateProductsBatchAtomicRollsBackOnConflict(t *testing.T) {
+	h, db := newTestProductHandler(t)
+	p1, _ := entity.NewProduct("Product 1", 10)
+	p2, _ := entity.NewProduct("Product 2", 20)
+	db.Create(p1)
+	db.Create(p2)
+
+	body := `[{"id":"` + p1.ID.String() + `","version":1,"name":"Product 1b","price":11},` +
+		`{"id":"` + p2.ID.String() + `","version":2,"name":"Product 2b","price":22}]`
+	rec, output := doBatch(h.UpdateProductsBatch, http.MethodPut, "/products/batch", "application/json", body)
+	assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+	assert.Equal(t, "rolled_back", output.Results[0].Status)
+	assert.Equal(t, database.ErrVersionConflict.Error(), output.Results[1].Error)
+
+	var found entity.Product
+	db.First(&found, "id = ?", p1.ID.String())
+	assert.Equal(t, "Product 1", found.Name)
+	assert.Equal(t, 1, found.Version)
+}
+
+func TestDeleteProductsBatchBestEffort(t *testing.T) {
+	h, db := newTestProductHandler(t)
+	p1, _ := entity.NewProduct("Product 1", 10)
+	db.Create(p1)
+
+	body := `[{"id":"` + p1.ID.String() + `","version":1},{"id":"not-a-uuid","version":1}]`
+	rec, output := doBatch(h.DeleteProductsBatch, http.MethodDelete, "/products/batch?mode=best_effort", "application/json", body)
+	assert.Equal(t, http.StatusMultiStatus, rec.Code)
+	assert.Equal(t, "deleted", output.Results[0].Status)
+	assert.Equal(t, entity.ErrInvalidId.Error(), output.Results[1].Error)
+
+	var count int64
+	db.Model(&entity.Product{}).Count(&count)
+	assert.Equal(t, int64(0), count)
+}
+
+func TestProductsBatchRejectsInvalidMode(t *testing.T) {
+	h, _ := newTestProductHandler(t)
+
+	req := httptest.NewRequest(http.MethodPost, "/products/batch?mode=sometimes", strings.NewReader(`[]`))
+	rec := httptest.NewRecorder()
+	h.CreateProductsBatch(rec, req)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestProductsBatchStopsAtMaxItems(t *testing.T) {
+	h, db := newTestProductHandler(t)
+
+	items := strings.Repeat(`{"name":"Product","price":1},`, maxBatchItems) + `{"name":"Product","price":1},` + `not json`
+	rec, _ := doBatch(h.CreateProductsBatch, http.MethodPost, "/products/batch", "application/json", "["+items+"]")
+	assert.Equal(t, http.StatusRequestEntityTooLarge, rec.Code)
+
+	var count int64
+	db.Model(&entity.Product{}).Count(&count)
+	assert.Equal(t, int64(0), count)
+}
+
+func TestProductsBatchLimitsBodySize(t *testing.T) {
+	h, _ := newTestProductHandler(t)
+
+	body := `[{"name":"` + strings.Repeat("a", maxBatchBytes) + `","price":1}]`
+	rec, _ := doBatch(h.CreateProductsBatch, http.MethodPost, "/products/batch", "application/json", body)
+	assert.Equal(t, http.StatusRequestEntityTooLarge, rec.Code)
+}
+
+func TestProductsBatchRejectsNonArrayJSON(t *testing.T) {
+	h, _ := newTestProductHandler(t)
+
+	req := httptest.NewRequest(http.MethodPost, "/products/batch", strings.NewReader(`{"name":"Product","price":1}`))
+	rec := httptest.NewRecorder()
+	h.CreateProductsBatch(rec, req)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Contains(t, rec.Body.String(), errBatchNotArray.Error())
+}
@@ -2,8 +2,12 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
+	"slices"
 	"strconv"
+	"strings"
 
 	"github.com/ElizCarvalho/FC_PosGolang/7_APIS/infra/database"
 	"github.com/ElizCarvalho/FC_PosGolang/7_APIS/internal/dto"
@@ -12,7 +16,11 @@ import (
 )
 
 const (
-	ErrIDRequired = "id is required"
+	ErrIDRequired         = "id is required"
+	ErrIfMatchRequired    = "If-Match header is required"
+	ErrInvalidIfMatch     = "invalid If-Match header"
+	ErrWeakIfMatch        = "If-Match requires a strong ETag"
+	ErrPreconditionFailed = "product was modified by another request"
 )
 
 type ProductHandler struct {
@@ -31,6 +39,7 @@ func NewProductHandler(db database.ProductInterface) *ProductHandler {
 // @Produce json
 // @Param  request body dto.CreateProductInput true "product request"
 // @Success 201 {string} string "Product created successfully"
+// @Header 201 {string} ETag "product version"
 // @Failure 400 {object} dto.Error
 // @Failure 500 {object} dto.Error
 // @Router /products [post]
@@ -60,6 +69,7 @@ func (h *ProductHandler) CreateProduct(w http.ResponseWriter, r *http.Request) {
 		json.NewEncoder(w).Encode(error)
 		return
 	}
+	w.Header().Set("ETag", etag(p.Version))
 	w.WriteHeader(http.StatusCreated)
 	w.Write([]byte("Product created successfully"))
 }
@@ -112,6 +122,7 @@ func (h *ProductHandler) GetProducts(w http.ResponseWriter, r *http.Request) {
 // @Produce json
 // @Param id path string true "product id" Format(uuid)
 // @Success 200 {object} entity.Product
+// @Header 200 {string} ETag "product version"
 // @Failure 400 {object} dto.Error
 // @Failure 404 {object} dto.Error
 // @Failure 500 {object} dto.Error
@@ -135,6 +146,7 @@ func (h *ProductHandler) GetProduct(w http.ResponseWriter, r *http.Request) {
 	}
 
 	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("ETag", etag(product.Version))
 	w.WriteHeader(http.StatusOK)
 	json.NewEncoder(w).Encode(product)
 }
@@ -146,10 +158,14 @@ func (h *ProductHandler) GetProduct(w http.ResponseWriter, r *http.Request) {
 // @Accept json
 // @Produce json
 // @Param id path string true "product id" Format(uuid)
+// @Param If-Match header string true "product ETag"
 // @Param  request body dto.CreateProductInput true "product request"
 // @Success 200 {string} string "Product updated successfully"
+// @Header 200 {string} ETag "product version"
 // @Failure 400 {object} dto.Error
 // @Failure 404 {object} dto.Error
+// @Failure 412 {object} dto.Error
+// @Failure 428 {object} dto.Error
 // @Failure 500 {object} dto.Error
 // @Router /products/{id} [put]
 // @Security ApiKeyAuth
@@ -162,6 +178,11 @@ func (h *ProductHandler) UpdateProduct(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	match, ok := parseIfMatch(w, r)
+	if !ok {
+		return
+	}
+
 	var product dto.CreateProductInput
 	err := json.NewDecoder(r.Body).Decode(&product)
 	if err != nil {
@@ -180,6 +201,13 @@ func (h *ProductHandler) UpdateProduct(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if !match.matches(existingProduct.Version) {
+		w.WriteHeader(http.StatusPreconditionFailed)
+		error := dto.Error{Message: ErrPreconditionFailed}
+		json.NewEncoder(w).Encode(error)
+		return
+	}
+
 	// Atualizar apenas os campos necessários mantendo o ID original
 	existingProduct.Name = product.Name
 	existingProduct.Price = product.Price
@@ -194,6 +222,12 @@ func (h *ProductHandler) UpdateProduct(w http.ResponseWriter, r *http.Request) {
 	}
 
 	err = h.ProductDB.Update(existingProduct)
+	if errors.Is(err, database.ErrVersionConflict) {
+		w.WriteHeader(http.StatusPreconditionFailed)
+		error := dto.Error{Message: ErrPreconditionFailed}
+		json.NewEncoder(w).Encode(error)
+		return
+	}
 	if err != nil {
 		w.WriteHeader(http.StatusInternalServerError)
 		error := dto.Error{Message: err.Error()}
@@ -201,6 +235,7 @@ func (h *ProductHandler) UpdateProduct(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	w.Header().Set("ETag", etag(existingProduct.Version))
 	w.WriteHeader(http.StatusOK)
 	w.Write([]byte("Product updated successfully"))
 }
@@ -212,9 +247,12 @@ func (h *ProductHandler) UpdateProduct(w http.ResponseWriter, r *http.Request) {
 // @Accept json
 // @Produce json
 // @Param id path string true "product id" Format(uuid)
+// @Param If-Match header string true "product ETag"
 // @Success 200 {string} string "Product deleted successfully"
 // @Failure 400 {object} dto.Error
 // @Failure 404 {object} dto.Error
+// @Failure 412 {object} dto.Error
+// @Failure 428 {object} dto.Error
 // @Failure 500 {object} dto.Error
 // @Router /products/{id} [delete]
 // @Security ApiKeyAuth
@@ -227,7 +265,12 @@ func (h *ProductHandler) DeleteProduct(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	_, err := h.ProductDB.FindById(id)
+	match, ok := parseIfMatch(w, r)
+	if !ok {
+		return
+	}
+
+	product, err := h.ProductDB.FindById(id)
 	if err != nil {
 		w.WriteHeader(http.StatusNotFound)
 		error := dto.Error{Message: err.Error()}
@@ -235,7 +278,21 @@ func (h *ProductHandler) DeleteProduct(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	err = h.ProductDB.Delete(id)
+	if !match.matches(product.Version) {
+		w.WriteHeader(http.StatusPreconditionFailed)
+		error := dto.Error{Message: ErrPreconditionFailed}
+		json.NewEncoder(w).Encode(error)
+		return
+	}
+
+	// a versão lida continua condicionando o DELETE, mesmo com "*" ou uma lista
+	err = h.ProductDB.Delete(id, product.Version)
+	if errors.Is(err, database.ErrVersionConflict) {
+		w.WriteHeader(http.StatusPreconditionFailed)
+		error := dto.Error{Message: ErrPreconditionFailed}
+		json.NewEncoder(w).Encode(error)
+		return
+	}
 	if err != nil {
 		w.WriteHeader(http.StatusInternalServerError)
 		error := dto.Error{Message: err.Error()}
@@ -246,3 +303,74 @@ func (h *ProductHandler) DeleteProduct(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusOK)
 	w.Write([]byte("Product deleted successfully"))
 }
+
+// etag formata a versão do produto como uma entity tag forte
+func etag(version int) string {
+	return fmt.Sprintf(`"%d"`, version)
+}
+
+// ifMatch é o header If-Match já interpretado: "*" ou a lista de versões aceitas
+type ifMatch struct {
+	any      bool
+	versions []int
+	weak     bool // havia alguma ETag fraca, ignorada na comparação
+}
+
+// matches informa se a versão atual do produto satisfaz o If-Match
+func (m ifMatch) matches(version int) bool {
+	return m.any || slices.Contains(m.versions, version)
+}
+
+// parseIfMatch interpreta o header If-Match, respondendo 428 quando ele está
+// ausente e 400 quando alguma entrada não é "*" nem uma ETag de produto.
+// "*" aceita qualquer versão de um produto que exista; numa lista basta uma
+// entrada casar. If-Match usa comparação forte (RFC 7232), então ETags fracas
+// (W/) nunca casam e uma lista só com elas responde 412.
+func parseIfMatch(w http.ResponseWriter, r *http.Request) (ifMatch, bool) {
+	header := strings.TrimSpace(r.Header.Get("If-Match"))
+	if header == "" {
+		w.WriteHeader(http.StatusPreconditionRequired)
+		error := dto.Error{Message: ErrIfMatchRequired}
+		json.NewEncoder(w).Encode(error)
+		return ifMatch{}, false
+	}
+	if header == "*" {
+		return ifMatch{any: true}, true
+	}
+
+	var match ifMatch
+	for _, entry := range strings.Split(header, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		tag, weak := strings.CutPrefix(entry, "W/")
+		quoted := len(tag) >= 2 && strings.HasPrefix(tag, `"`) && strings.HasSuffix(tag, `"`)
+		version, err := strconv.Atoi(strings.TrimSuffix(strings.TrimPrefix(tag, `"`), `"`))
+		if !quoted || err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			error := dto.Error{Message: ErrInvalidIfMatch}
+			json.NewEncoder(w).Encode(error)
+			return ifMatch{}, false
+		}
+		if weak {
+			match.weak = true
+			continue
+		}
+		match.versions = append(match.versions, version)
+	}
+
+	if len(match.versions) == 0 && !match.weak {
+		w.WriteHeader(http.StatusBadRequest)
+		error := dto.Error{Message: ErrInvalidIfMatch}
+		json.NewEncoder(w).Encode(error)
+		return ifMatch{}, false
+	}
+	if len(match.versions) == 0 {
+		w.WriteHeader(http.StatusPreconditionFailed)
+		error := dto.Error{Message: ErrWeakIfMatch}
+		json.NewEncoder(w).Encode(error)
+		return ifMatch{}, false
+	}
+	return match, true
+}
@@ -0,0 +1,154 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ElizCarvalho/FC_PosGolang/7_APIS/internal/dto"
+	"github.com/ElizCarvalho/FC_PosGolang/7_APIS/internal/entity"
+	pkgEntity "github.com/ElizCarvalho/FC_PosGolang/7_APIS/pkg/entity"
+	"github.com/go-chi/chi"
+	"github.com/stretchr/testify/assert"
+)
+
+func doWithID(handler http.HandlerFunc, method, id, ifMatch, body string) (*httptest.ResponseRecorder, dto.Error) {
+	req := httptest.NewRequest(method, "/products/"+id, strings.NewReader(body))
+	if ifMatch != "" {
+		req.Header.Set("If-Match", ifMatch)
+	}
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("id", id)
+	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+	rec := httptest.NewRecorder()
+	handler(rec, req)
+
+	var output dto.Error
+	json.Unmarshal(rec.Body.Bytes(), &output)
+	return rec, output
+}
+
+func TestUpdateProductIfMatch(t *testing.T) {
+	h, db := newTestProductHandler(t)
+	p, _ := entity.NewProduct("Product 1", 10)
+	db.Create(p)
+	id := p.ID.String()
+	body := `{"name":"Product 1b","price":11}`
+
+	tests := []struct {
+		name    string
+		ifMatch string
+		code    int
+		message string
+	}{
+		{"missing", "", http.StatusPreconditionRequired, ErrIfMatchRequired},
+		{"malformed", "abc", http.StatusBadRequest, ErrInvalidIfMatch},
+		{"unquoted", "1", http.StatusBadRequest, ErrInvalidIfMatch},
+		{"weak", `W/"1"`, http.StatusPreconditionFailed, ErrWeakIfMatch},
+		{"stale", `"2"`, http.StatusPreconditionFailed, ErrPreconditionFailed},
+		{"stale list", `"2", "3"`, http.StatusPreconditionFailed, ErrPreconditionFailed},
+		{"weak list", `W/"1", W/"2"`, http.StatusPreconditionFailed, ErrWeakIfMatch},
+		{"malformed list entry", `"1", abc`, http.StatusBadRequest, ErrInvalidIfMatch},
+		{"empty list", ` , `, http.StatusBadRequest, ErrInvalidIfMatch},
+		{"star in list", `"1", *`, http.StatusBadRequest, ErrInvalidIfMatch},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec, output := doWithID(h.UpdateProduct, http.MethodPut, id, tt.ifMatch, body)
+			assert.Equal(t, tt.code, rec.Code)
+			assert.Equal(t, tt.message, output.Message)
+			assert.Empty(t, rec.Header().Get("ETag"))
+		})
+	}
+
+	var found entity.Product
+	db.First(&found, "id = ?", id)
+	assert.Equal(t, "Product 1", found.Name)
+	assert.Equal(t, 1, found.Version)
+}
+
+func TestUpdateProductReturnsNewETag(t *testing.T) {
+	h, db := newTestProductHandler(t)
+	p, _ := entity.NewProduct("Product 1", 10)
+	db.Create(p)
+
+	rec, _ := doWithID(h.UpdateProduct, http.MethodPut, p.ID.String(), `"1"`, `{"name":"Product 1b","price":11}`)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, `"2"`, rec.Header().Get("ETag"))
+
+	// a ETag antiga não vale mais
+	rec, _ = doWithID(h.UpdateProduct, http.MethodPut, p.ID.String(), `"1"`, `{"name":"Product 1c","price":12}`)
+	assert.Equal(t, http.StatusPreconditionFailed, rec.Code)
+
+	var found entity.Product
+	db.First(&found, "id = ?", p.ID.String())
+	assert.Equal(t, "Product 1b", found.Name)
+	assert.Equal(t, 2, found.Version)
+}
+
+func TestUpdateProductIfMatchListAndStar(t *testing.T) {
+	h, db := newTestProductHandler(t)
+	p, _ := entity.NewProduct("Product 1", 10)
+	db.Create(p)
+	id := p.ID.String()
+
+	// basta uma entrada da lista casar; a fraca é ignorada
+	rec, _ := doWithID(h.UpdateProduct, http.MethodPut, id, `W/"1", "3", "1"`, `{"name":"Product 1b","price":11}`)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, `"2"`, rec.Header().Get("ETag"))
+
+	// "*" aceita a versão atual, qualquer que seja
+	rec, _ = doWithID(h.UpdateProduct, http.MethodPut, id, "*", `{"name":"Product 1c","price":12}`)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, `"3"`, rec.Header().Get("ETag"))
+
+	var found entity.Product
+	db.First(&found, "id = ?", id)
+	assert.Equal(t, "Product 1c", found.Name)
+	assert.Equal(t, 3, found.Version)
+
+	// mas o produto precisa existir
+	rec, _ = doWithID(h.UpdateProduct, http.MethodPut, pkgEntity.NewID().String(), "*", `{"name":"Product 2","price":1}`)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestDeleteProductIfMatch(t *testing.T) {
+	h, db := newTestProductHandler(t)
+	p, _ := entity.NewProduct("Product 1", 10)
+	db.Create(p)
+
+	rec, output := doWithID(h.DeleteProduct, http.MethodDelete, p.ID.String(), "", "")
+	assert.Equal(t, http.StatusPreconditionRequired, rec.Code)
+	assert.Equal(t, ErrIfMatchRequired, output.Message)
+
+	rec, output = doWithID(h.DeleteProduct, http.MethodDelete, p.ID.String(), `W/"1"`, "")
+	assert.Equal(t, http.StatusPreconditionFailed, rec.Code)
+	assert.Equal(t, ErrWeakIfMatch, output.Message)
+
+	rec, output = doWithID(h.DeleteProduct, http.MethodDelete, p.ID.String(), `"2", "3"`, "")
+	assert.Equal(t, http.StatusPreconditionFailed, rec.Code)
+	assert.Equal(t, ErrPreconditionFailed, output.Message)
+
+	rec, _ = doWithID(h.DeleteProduct, http.MethodDelete, p.ID.String(), `"2", "1"`, "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	var count int64
+	db.Model(&entity.Product{}).Count(&count)
+	assert.Equal(t, int64(0), count)
+}
+
+func TestDeleteProductIfMatchStar(t *testing.T) {
+	h, db := newTestProductHandler(t)
+	p, _ := entity.NewProduct("Product 1", 10)
+	db.Create(p)
+
+	rec, _ := doWithID(h.DeleteProduct, http.MethodDelete, p.ID.String(), "*", "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	// sem produto, "*" não casa
+	rec, _ = doWithID(h.DeleteProduct, http.MethodDelete, p.ID.String(), "*", "")
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
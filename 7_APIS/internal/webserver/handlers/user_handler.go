@@ -2,22 +2,53 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
 	"net/http"
+	"net/url"
+	"sync"
 	"time"
 
 	"github.com/ElizCarvalho/FC_PosGolang/7_APIS/infra/database"
 	"github.com/ElizCarvalho/FC_PosGolang/7_APIS/internal/dto"
 	"github.com/ElizCarvalho/FC_PosGolang/7_APIS/internal/entity"
+	"github.com/ElizCarvalho/FC_PosGolang/7_APIS/pkg/mailer"
+	"github.com/ElizCarvalho/FC_PosGolang/7_APIS/pkg/ratelimit"
+	"github.com/ElizCarvalho/FC_PosGolang/7_APIS/pkg/token"
 	"github.com/go-chi/jwtauth"
+	"gorm.io/gorm"
 )
 
+const (
+	ErrUserNotFound       = "user not found"
+	ErrInvalidUserToken   = "access token has no user"
+	ErrInvalidCredentials = "invalid credentials"
+	ErrTooManyAttempts    = "too many login attempts, try again later"
+)
+
+// dummyPassword é comparado quando o email não existe, para que a resposta
+// leve o mesmo tempo de um login com senha errada
+var dummyPassword = sync.OnceValue(func() *entity.User {
+	u, _ := entity.NewUser("dummy", "dummy@example.com", "dummy-password-1")
+	return u
+})
+
 type UserHandler struct {
-	UserDB database.UserInterface
+	UserDB        database.UserInterface
+	Mailer        mailer.Mailer
+	AccountTokens *token.Issuer
+	AppURL        string
+	LoginLimiter  *ratelimit.LoginLimiter
 }
 
-func NewUserHandler(db database.UserInterface) *UserHandler {
+func NewUserHandler(db database.UserInterface, m mailer.Mailer, accountTokens *token.Issuer, appURL string, loginLimiter *ratelimit.LoginLimiter) *UserHandler {
 	return &UserHandler{
-		UserDB: db,
+		UserDB:        db,
+		Mailer:        m,
+		AccountTokens: accountTokens,
+		AppURL:        appURL,
+		LoginLimiter:  loginLimiter,
 	}
 }
 
@@ -31,7 +62,8 @@ func NewUserHandler(db database.UserInterface) *UserHandler {
 // @Success 200 {object} dto.GetJWTOutput
 // @Failure 400 {object} dto.Error
 // @Failure 401 {object} dto.Error
-// @Failure 404 {object} dto.Error
+// @Failure 429 {object} dto.Error
+// @Header 429 {integer} Retry-After "seconds until a new attempt is accepted"
 // @Failure 500 {object} dto.Error
 // @Router /users/generate_token [post]
 func (h *UserHandler) GetJWT(w http.ResponseWriter, r *http.Request) {
@@ -47,20 +79,29 @@ func (h *UserHandler) GetJWT(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	user, err := h.UserDB.FindByEmail(login.Email)
-	if err != nil {
-		w.WriteHeader(http.StatusUnauthorized)
-		error := dto.Error{Message: "Invalid email"}
+	account := entity.NormalizeEmail(login.Email)
+	ip := ratelimit.ClientIP(r)
+	if locked, retryAfter := h.LoginLimiter.Check(account, ip); locked {
+		ratelimit.SetRetryAfter(w, retryAfter)
+		w.WriteHeader(http.StatusTooManyRequests)
+		error := dto.Error{Message: ErrTooManyAttempts}
 		json.NewEncoder(w).Encode(error)
 		return
 	}
 
-	if !user.ValidatePassword(login.Password) {
+	// email inexistente e senha errada recebem a mesma resposta para não revelar quais contas existem
+	user, err := h.UserDB.FindByEmail(account)
+	if err != nil {
+		dummyPassword().ValidatePassword(login.Password)
+	}
+	if err != nil || !user.ValidatePassword(login.Password) {
+		h.LoginLimiter.Failure(account, ip)
 		w.WriteHeader(http.StatusUnauthorized)
-		error := dto.Error{Message: "Invalid password"}
+		error := dto.Error{Message: ErrInvalidCredentials}
 		json.NewEncoder(w).Encode(error)
 		return
 	}
+	h.LoginLimiter.Success(account)
 
 	_, tokenString, err := jwt.Encode(map[string]interface{}{
 		"user_id": user.ID,
@@ -91,6 +132,7 @@ func (h *UserHandler) GetJWT(w http.ResponseWriter, r *http.Request) {
 // @Param  request body dto.CreateUserInput true "user request"
 // @Success 201 {string} string "User created successfully"
 // @Failure 400 {object} dto.Error
+// @Failure 409 {object} dto.Error
 // @Failure 500 {object} dto.Error
 // @Router /users [post]
 func (h *UserHandler) CreateUser(w http.ResponseWriter, r *http.Request) {
@@ -112,12 +154,327 @@ func (h *UserHandler) CreateUser(w http.ResponseWriter, r *http.Request) {
 	}
 
 	err = h.UserDB.Create(u)
+	if errors.Is(err, database.ErrEmailAlreadyExists) {
+		w.WriteHeader(http.StatusConflict)
+		error := dto.Error{Message: err.Error()}
+		json.NewEncoder(w).Encode(error)
+		return
+	}
 	if err != nil {
 		w.WriteHeader(http.StatusInternalServerError)
 		error := dto.Error{Message: err.Error()}
 		json.NewEncoder(w).Encode(error)
 		return
 	}
+
+	h.sendVerificationEmail(u)
+
 	w.WriteHeader(http.StatusCreated)
 	w.Write([]byte("User created successfully"))
 }
+
+// Verify email godoc
+// @Summary Verify user email
+// @Description Confirm the user email with the token sent by email
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param  request body dto.VerifyEmailInput true "verification token"
+// @Success 200 {string} string "Email verified successfully"
+// @Failure 400 {object} dto.Error
+// @Failure 500 {object} dto.Error
+// @Router /users/verify_email [post]
+func (h *UserHandler) VerifyEmail(w http.ResponseWriter, r *http.Request) {
+	var input dto.VerifyEmailInput
+	err := json.NewDecoder(r.Body).Decode(&input)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		error := dto.Error{Message: err.Error()}
+		json.NewEncoder(w).Encode(error)
+		return
+	}
+
+	u, err := h.userFromAccountToken(input.Token, token.PurposeVerifyEmail)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		error := dto.Error{Message: err.Error()}
+		json.NewEncoder(w).Encode(error)
+		return
+	}
+
+	u.VerifyEmail()
+	err = h.UserDB.Update(u)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		error := dto.Error{Message: err.Error()}
+		json.NewEncoder(w).Encode(error)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte("Email verified successfully"))
+}
+
+// Resend verification godoc
+// @Summary Resend verification email
+// @Description Send a new verification email. The response is the same whether or not the email is registered.
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param  request body dto.EmailInput true "user email"
+// @Success 202 {string} string "Verification email sent if the account exists"
+// @Failure 400 {object} dto.Error
+// @Router /users/resend_verification [post]
+func (h *UserHandler) ResendVerification(w http.ResponseWriter, r *http.Request) {
+	var input dto.EmailInput
+	err := json.NewDecoder(r.Body).Decode(&input)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		error := dto.Error{Message: err.Error()}
+		json.NewEncoder(w).Encode(error)
+		return
+	}
+
+	u, err := h.UserDB.FindByEmail(input.Email)
+	if err == nil && !u.EmailVerified {
+		h.sendVerificationEmail(u)
+	}
+
+	w.WriteHeader(http.StatusAccepted)
+	w.Write([]byte("Verification email sent if the account exists"))
+}
+
+// Forgot password godoc
+// @Summary Request a password reset
+// @Description Send a password reset email. The response is the same whether or not the email is registered.
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param  request body dto.EmailInput true "user email"
+// @Success 202 {string} string "Password reset email sent if the account exists"
+// @Failure 400 {object} dto.Error
+// @Router /users/forgot_password [post]
+func (h *UserHandler) ForgotPassword(w http.ResponseWriter, r *http.Request) {
+	var input dto.EmailInput
+	err := json.NewDecoder(r.Body).Decode(&input)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		error := dto.Error{Message: err.Error()}
+		json.NewEncoder(w).Encode(error)
+		return
+	}
+
+	u, err := h.UserDB.FindByEmail(input.Email)
+	if err == nil {
+		h.sendAccountEmail(u, token.PurposeResetPassword, "Reset your password", "reset-password",
+			"We received a request to reset your password. If it was not you, ignore this email.")
+	}
+
+	w.WriteHeader(http.StatusAccepted)
+	w.Write([]byte("Password reset email sent if the account exists"))
+}
+
+// Reset password godoc
+// @Summary Reset password
+// @Description Set a new password with the token sent by email. Each token works only once.
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param  request body dto.ResetPasswordInput true "reset token and new password"
+// @Success 200 {string} string "Password reset successfully"
+// @Failure 400 {object} dto.Error
+// @Failure 500 {object} dto.Error
+// @Router /users/reset_password [post]
+func (h *UserHandler) ResetPassword(w http.ResponseWriter, r *http.Request) {
+	var input dto.ResetPasswordInput
+	err := json.NewDecoder(r.Body).Decode(&input)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		error := dto.Error{Message: err.Error()}
+		json.NewEncoder(w).Encode(error)
+		return
+	}
+
+	u, err := h.userFromAccountToken(input.Token, token.PurposeResetPassword)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		error := dto.Error{Message: err.Error()}
+		json.NewEncoder(w).Encode(error)
+		return
+	}
+
+	err = u.ChangePassword(input.Password)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		error := dto.Error{Message: err.Error()}
+		json.NewEncoder(w).Encode(error)
+		return
+	}
+
+	// quem recebeu o link provou ser dono do email
+	u.VerifyEmail()
+	err = h.UserDB.Update(u)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		error := dto.Error{Message: err.Error()}
+		json.NewEncoder(w).Encode(error)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte("Password reset successfully"))
+}
+
+// Get profile godoc
+// @Summary Get the authenticated user
+// @Description Get the profile of the user that owns the access token
+// @Tags users
+// @Accept json
+// @Produce json
+// @Success 200 {object} entity.User
+// @Failure 401 {object} dto.Error
+// @Failure 404 {object} dto.Error
+// @Router /users/me [get]
+// @Security ApiKeyAuth
+func (h *UserHandler) GetProfile(w http.ResponseWriter, r *http.Request) {
+	u, status, err := h.currentUser(r)
+	if err != nil {
+		w.WriteHeader(status)
+		error := dto.Error{Message: err.Error()}
+		json.NewEncoder(w).Encode(error)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(u)
+}
+
+// Update profile godoc
+// @Summary Update the authenticated user
+// @Description Update name and email of the user that owns the access token. Changing the email requires verifying it again.
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param  request body dto.UpdateProfileInput true "profile request"
+// @Success 200 {object} entity.User
+// @Failure 400 {object} dto.Error
+// @Failure 401 {object} dto.Error
+// @Failure 404 {object} dto.Error
+// @Failure 409 {object} dto.Error
+// @Failure 500 {object} dto.Error
+// @Router /users/me [put]
+// @Security ApiKeyAuth
+func (h *UserHandler) UpdateProfile(w http.ResponseWriter, r *http.Request) {
+	u, status, err := h.currentUser(r)
+	if err != nil {
+		w.WriteHeader(status)
+		error := dto.Error{Message: err.Error()}
+		json.NewEncoder(w).Encode(error)
+		return
+	}
+
+	var input dto.UpdateProfileInput
+	err = json.NewDecoder(r.Body).Decode(&input)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		error := dto.Error{Message: err.Error()}
+		json.NewEncoder(w).Encode(error)
+		return
+	}
+
+	previousEmail := u.Email
+	u.Name = input.Name
+	err = u.ChangeEmail(input.Email)
+	if err == nil {
+		err = u.Validate()
+	}
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		error := dto.Error{Message: err.Error()}
+		json.NewEncoder(w).Encode(error)
+		return
+	}
+
+	err = h.UserDB.Update(u)
+	if errors.Is(err, database.ErrEmailAlreadyExists) {
+		w.WriteHeader(http.StatusConflict)
+		error := dto.Error{Message: err.Error()}
+		json.NewEncoder(w).Encode(error)
+		return
+	}
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		error := dto.Error{Message: err.Error()}
+		json.NewEncoder(w).Encode(error)
+		return
+	}
+
+	if u.Email != previousEmail {
+		h.sendVerificationEmail(u)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(u)
+}
+
+// currentUser carrega o usuário dono do token de acesso verificado pelo jwtauth
+func (h *UserHandler) currentUser(r *http.Request) (*entity.User, int, error) {
+	_, claims, err := jwtauth.FromContext(r.Context())
+	if err != nil {
+		return nil, http.StatusUnauthorized, err
+	}
+	userID, ok := claims["user_id"].(string)
+	if !ok || userID == "" {
+		return nil, http.StatusUnauthorized, errors.New(ErrInvalidUserToken)
+	}
+
+	u, err := h.UserDB.FindByID(userID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, http.StatusNotFound, errors.New(ErrUserNotFound)
+	}
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+	return u, http.StatusOK, nil
+}
+
+// userFromAccountToken valida o token de conta e confere se ele ainda corresponde
+// ao email e à senha atuais do usuário
+func (h *UserHandler) userFromAccountToken(tokenString, purpose string) (*entity.User, error) {
+	claims, err := h.AccountTokens.Parse(tokenString, purpose)
+	if err != nil {
+		return nil, err
+	}
+	u, err := h.UserDB.FindByID(claims.Subject)
+	if err != nil || u.Fingerprint() != claims.Fingerprint {
+		return nil, token.ErrInvalidToken
+	}
+	return u, nil
+}
+
+func (h *UserHandler) sendVerificationEmail(u *entity.User) {
+	h.sendAccountEmail(u, token.PurposeVerifyEmail, "Confirm your email", "verify-email",
+		"Welcome! Please confirm your email address.")
+}
+
+// sendAccountEmail emite um token para purpose e envia o link correspondente.
+// Falhas só são logadas: o usuário pode pedir um novo email depois.
+func (h *UserHandler) sendAccountEmail(u *entity.User, purpose, subject, path, intro string) {
+	tokenString, err := h.AccountTokens.Issue(purpose, u.ID.String(), u.Fingerprint())
+	if err != nil {
+		log.Printf("could not issue %s token for user %s: %v", purpose, u.ID, err)
+		return
+	}
+
+	link := fmt.Sprintf("%s/%s?token=%s", h.AppURL, path, url.QueryEscape(tokenString))
+	err = h.Mailer.Send(mailer.Message{
+		To:      u.Email,
+		Subject: subject,
+		Body:    fmt.Sprintf("Hi %s,\n\n%s\n\n%s\n\nToken: %s\n", u.Name, intro, link, tokenString),
+	})
+	if err != nil {
+		log.Printf("could not send %s email to user %s: %v", purpose, u.ID, err)
+	}
+}
@@ -0,0 +1,208 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ElizCarvalho/FC_PosGolang/7_APIS/infra/database"
+	"github.com/ElizCarvalho/FC_PosGolang/7_APIS/internal/entity"
+	"github.com/ElizCarvalho/FC_PosGolang/7_APIS/pkg/mailer"
+	"github.com/ElizCarvalho/FC_PosGolang/7_APIS/pkg/ratelimit"
+	"github.com/ElizCarvalho/FC_PosGolang/7_APIS/pkg/token"
+	"github.com/go-chi/jwtauth"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+const testUserPassword = "secret123"
+
+type fakeMailer struct {
+	sent []mailer.Message
+}
+
+func (m *fakeMailer) Send(msg mailer.Message) error {
+	m.sent = append(m.sent, msg)
+	return nil
+}
+
+var tokenInBody = regexp.MustCompile(`Token: (\S+)`)
+
+func (m *fakeMailer) lastToken(t *testing.T) string {
+	if len(m.sent) == 0 {
+		t.Fatal("no email sent")
+	}
+	match := tokenInBody.FindStringSubmatch(m.sent[len(m.sent)-1].Body)
+	if match == nil {
+		t.Fatal("email has no token")
+	}
+	return match[1]
+}
+
+func newTestUserHandler(t *testing.T) (*UserHandler, *fakeMailer, database.UserInterface) {
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	db.AutoMigrate(&entity.User{})
+	userDB := database.NewUserDB(db)
+	m := &fakeMailer{}
+	issuer := token.NewIssuer(jwtauth.New("HS256", []byte("account-secret"), nil), time.Hour)
+	loginLimiter := ratelimit.NewLoginLimiter(
+		ratelimit.NewAttemptLimiter(3, time.Minute, time.Minute),
+		ratelimit.NewAttemptLimiter(10, time.Minute, time.Minute),
+	)
+	return NewUserHandler(userDB, m, issuer, "http://localhost:8000", loginLimiter), m, userDB
+}
+
+func doJSON(handler http.HandlerFunc, method, target, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	handler(rec, req)
+	return rec
+}
+
+func TestCreateUserValidationAndConflict(t *testing.T) {
+	h, m, _ := newTestUserHandler(t)
+
+	rec := doJSON(h.CreateUser, http.MethodPost, "/users", `{"name":"John","email":"john","password":"secret123"}`)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	rec = doJSON(h.CreateUser, http.MethodPost, "/users", `{"name":"John","email":"john@example.com","password":"123"}`)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	rec = doJSON(h.CreateUser, http.MethodPost, "/users", `{"name":"John","email":"john@example.com","password":"secret123"}`)
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	assert.Len(t, m.sent, 1)
+	assert.Equal(t, "john@example.com", m.sent[0].To)
+
+	rec = doJSON(h.CreateUser, http.MethodPost, "/users", `{"name":"John","email":"JOHN@example.com","password":"secret123"}`)
+	assert.Equal(t, http.StatusConflict, rec.Code)
+}
+
+func doLogin(h *UserHandler, email, password string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/users/generate_token",
+		strings.NewReader(`{"email":"`+email+`","password":"`+password+`"}`))
+	ctx := context.WithValue(req.Context(), "jwt", jwtauth.New("HS256", []byte("secret"), nil))
+	ctx = context.WithValue(ctx, "jwtExpiresIn", 300)
+	rec := httptest.NewRecorder()
+	h.GetJWT(rec, req.WithContext(ctx))
+	return rec
+}
+
+func TestGetJWTUsesGenericCredentialsError(t *testing.T) {
+	h, _, userDB := newTestUserHandler(t)
+	u, _ := entity.NewUser("John", "john@example.com", testUserPassword)
+	userDB.Create(u)
+
+	unknown := doLogin(h, "nobody@example.com", testUserPassword)
+	wrongPassword := doLogin(h, "john@example.com", "wrong-password1")
+	assert.Equal(t, http.StatusUnauthorized, unknown.Code)
+	assert.Equal(t, http.StatusUnauthorized, wrongPassword.Code)
+	assert.Equal(t, unknown.Body.String(), wrongPassword.Body.String())
+
+	rec := doLogin(h, "John@Example.com", testUserPassword)
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
+
+func TestGetJWTLocksAccountAfterFailures(t *testing.T) {
+	h, _, userDB := newTestUserHandler(t)
+	u, _ := entity.NewUser("John", "john@example.com", testUserPassword)
+	userDB.Create(u)
+
+	for i := 0; i < 3; i++ {
+		rec := doLogin(h, "john@example.com", "wrong-password1")
+		assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	}
+
+	rec := doLogin(h, "john@example.com", testUserPassword)
+	assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+	assert.Equal(t, "60", rec.Header().Get("Retry-After"))
+}
+
+func TestVerifyEmail(t *testing.T) {
+	h, m, userDB := newTestUserHandler(t)
+	doJSON(h.CreateUser, http.MethodPost, "/users", `{"name":"John","email":"john@example.com","password":"secret123"}`)
+
+	rec := doJSON(h.VerifyEmail, http.MethodPost, "/users/verify_email", `{"token":"garbage"}`)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	rec = doJSON(h.VerifyEmail, http.MethodPost, "/users/verify_email", `{"token":"`+m.lastToken(t)+`"}`)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	u, _ := userDB.FindByEmail("john@example.com")
+	assert.True(t, u.EmailVerified)
+}
+
+func TestResetPasswordTokenWorksOnce(t *testing.T) {
+	h, m, userDB := newTestUserHandler(t)
+	doJSON(h.CreateUser, http.MethodPost, "/users", `{"name":"John","email":"john@example.com","password":"secret123"}`)
+
+	rec := doJSON(h.ForgotPassword, http.MethodPost, "/users/forgot_password", `{"email":"nobody@example.com"}`)
+	assert.Equal(t, http.StatusAccepted, rec.Code)
+	assert.Len(t, m.sent, 1)
+
+	rec = doJSON(h.ForgotPassword, http.MethodPost, "/users/forgot_password", `{"email":"john@example.com"}`)
+	assert.Equal(t, http.StatusAccepted, rec.Code)
+	assert.Len(t, m.sent, 2)
+	resetToken := m.lastToken(t)
+
+	rec = doJSON(h.ResetPassword, http.MethodPost, "/users/reset_password", `{"token":"`+resetToken+`","password":"weak"}`)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	rec = doJSON(h.ResetPassword, http.MethodPost, "/users/reset_password", `{"token":"`+resetToken+`","password":"another123"}`)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	u, _ := userDB.FindByEmail("john@example.com")
+	assert.True(t, u.ValidatePassword("another123"))
+
+	rec = doJSON(h.ResetPassword, http.MethodPost, "/users/reset_password", `{"token":"`+resetToken+`","password":"third1234"}`)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestProfile(t *testing.T) {
+	h, m, userDB := newTestUserHandler(t)
+	john, _ := entity.NewUser("John", "john@example.com", testUserPassword)
+	jane, _ := entity.NewUser("Jane", "jane@example.com", testUserPassword)
+	john.VerifyEmail()
+	userDB.Create(john)
+	userDB.Create(jane)
+
+	auth := jwtauth.New("HS256", []byte("secret"), nil)
+	_, accessToken, _ := auth.Encode(map[string]interface{}{"user_id": john.ID.String()})
+	withAuth := func(handler http.HandlerFunc) http.HandlerFunc {
+		return jwtauth.Verifier(auth)(jwtauth.Authenticator(handler)).ServeHTTP
+	}
+	doAuth := func(handler http.HandlerFunc, method, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, "/users/me", strings.NewReader(body))
+		req.Header.Set("Authorization", "Bearer "+accessToken)
+		rec := httptest.NewRecorder()
+		withAuth(handler)(rec, req)
+		return rec
+	}
+
+	rec := doAuth(h.GetProfile, http.MethodGet, "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var profile entity.User
+	json.NewDecoder(rec.Body).Decode(&profile)
+	assert.Equal(t, "john@example.com", profile.Email)
+	assert.NotContains(t, rec.Body.String(), "password")
+
+	rec = doAuth(h.UpdateProfile, http.MethodPut, `{"name":"John","email":"jane@example.com"}`)
+	assert.Equal(t, http.StatusConflict, rec.Code)
+
+	rec = doAuth(h.UpdateProfile, http.MethodPut, `{"name":"John Smith","email":"john.smith@example.com"}`)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	json.NewDecoder(rec.Body).Decode(&profile)
+	assert.Equal(t, "John Smith", profile.Name)
+	assert.False(t, profile.EmailVerified)
+	assert.Len(t, m.sent, 1)
+	assert.Equal(t, "john.smith@example.com", m.sent[0].To)
+}
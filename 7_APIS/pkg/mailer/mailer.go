@@ -0,0 +1,32 @@
+package mailer
+
+import (
+	"io"
+	"log"
+)
+
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Mailer entrega mensagens para os usuários. Implementações reais (SMTP, SES...)
+// só precisam satisfazer esta interface.
+type Mailer interface {
+	Send(msg Message) error
+}
+
+// LogMailer escreve as mensagens em um io.Writer em vez de enviá-las (uso em desenvolvimento)
+type LogMailer struct {
+	logger *log.Logger
+}
+
+func NewLogMailer(w io.Writer) *LogMailer {
+	return &LogMailer{logger: log.New(w, "[mailer] ", log.LstdFlags)}
+}
+
+func (m *LogMailer) Send(msg Message) error {
+	m.logger.Printf("To: %s\nSubject: %s\n\n%s\n", msg.To, msg.Subject, msg.Body)
+	return nil
+}
@@ -0,0 +1,131 @@
+package ratelimit
+
+import (
+	"sync"
+	"time"
+)
+
+type attempts struct {
+	count       int
+	first       time.Time
+	lockedUntil time.Time
+}
+
+// AttemptLimiter conta falhas por chave dentro de uma janela de tempo e bloqueia
+// a chave por lockout quando maxAttempts falhas acontecem nessa janela.
+type AttemptLimiter struct {
+	mu          sync.Mutex
+	maxAttempts int
+	window      time.Duration
+	lockout     time.Duration
+	entries     map[string]*attempts
+	lastSweep   time.Time
+	now         func() time.Time
+}
+
+func NewAttemptLimiter(maxAttempts int, window, lockout time.Duration) *AttemptLimiter {
+	return &AttemptLimiter{
+		maxAttempts: maxAttempts,
+		window:      window,
+		lockout:     lockout,
+		entries:     make(map[string]*attempts),
+		now:         time.Now,
+	}
+}
+
+// Locked informa se key está bloqueada e por quanto tempo ainda
+func (l *AttemptLimiter) Locked(key string) (bool, time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	l.sweep(now)
+
+	a, ok := l.entries[key]
+	if !ok {
+		return false, 0
+	}
+	if now.Before(a.lockedUntil) {
+		return true, a.lockedUntil.Sub(now)
+	}
+	if now.Sub(a.first) > l.window {
+		delete(l.entries, key)
+	}
+	return false, 0
+}
+
+// Fail registra uma falha para key, bloqueando-a ao atingir o limite
+func (l *AttemptLimiter) Fail(key string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	l.sweep(now)
+
+	a, ok := l.entries[key]
+	if !ok || l.expired(a, now) {
+		a = &attempts{first: now}
+		l.entries[key] = a
+	}
+	a.count++
+	if a.count >= l.maxAttempts {
+		a.lockedUntil = now.Add(l.lockout)
+		a.count = 0
+		a.first = now
+	}
+}
+
+// expired informa se a janela das falhas já passou e a chave não está bloqueada
+func (l *AttemptLimiter) expired(a *attempts, now time.Time) bool {
+	return now.Sub(a.first) > l.window && !now.Before(a.lockedUntil)
+}
+
+// sweep descarta as chaves expiradas, para o mapa não crescer indefinidamente
+// com falhas de emails ou IPs que não voltam mais
+func (l *AttemptLimiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < sweepInterval {
+		return
+	}
+	l.lastSweep = now
+	for key, a := range l.entries {
+		if l.expired(a, now) {
+			delete(l.entries, key)
+		}
+	}
+}
+
+// Reset esquece as falhas de key
+func (l *AttemptLimiter) Reset(key string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	delete(l.entries, key)
+}
+
+// LoginLimiter combina os limites de tentativas de login por conta e por IP
+type LoginLimiter struct {
+	Account *AttemptLimiter
+	IP      *AttemptLimiter
+}
+
+func NewLoginLimiter(account, ip *AttemptLimiter) *LoginLimiter {
+	return &LoginLimiter{Account: account, IP: ip}
+}
+
+// Check informa se a conta ou o IP estão bloqueados e por quanto tempo ainda
+func (l *LoginLimiter) Check(account, ip string) (bool, time.Duration) {
+	if locked, retryAfter := l.IP.Locked(ip); locked {
+		return true, retryAfter
+	}
+	return l.Account.Locked(account)
+}
+
+func (l *LoginLimiter) Failure(account, ip string) {
+	l.Account.Fail(account)
+	l.IP.Fail(ip)
+}
+
+// Success zera apenas o contador da conta: o do IP continua valendo para que
+// um login válido não libere novas tentativas contra outras contas
+func (l *LoginLimiter) Success(account string) {
+	l.Account.Reset(account)
+}
@@ -0,0 +1,136 @@
+package ratelimit
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	return c.now
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.now = c.now.Add(d)
+}
+
+func TestTokenBucketAllowsBurstThenRefills(t *testing.T) {
+	clock := &fakeClock{now: time.Unix(0, 0)}
+	tb := NewTokenBucket(2, 3)
+	tb.now = clock.Now
+
+	for i := 0; i < 3; i++ {
+		allowed, _ := tb.Allow("client")
+		assert.True(t, allowed)
+	}
+	allowed, retryAfter := tb.Allow("client")
+	assert.False(t, allowed)
+	assert.Equal(t, 500*time.Millisecond, retryAfter)
+
+	allowed, _ = tb.Allow("other")
+	assert.True(t, allowed)
+
+	clock.Advance(500 * time.Millisecond)
+	allowed, _ = tb.Allow("client")
+	assert.True(t, allowed)
+}
+
+func TestMiddlewareSetsRetryAfter(t *testing.T) {
+	tb := NewTokenBucket(0.5, 1)
+	handler := Middleware(tb, nil)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.RemoteAddr = "10.0.0.1:1234"
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+	assert.Equal(t, "2", rec.Header().Get("Retry-After"))
+}
+
+func TestAttemptLimiterLocksAndUnlocks(t *testing.T) {
+	clock := &fakeClock{now: time.Unix(0, 0)}
+	l := NewAttemptLimiter(3, time.Minute, 5*time.Minute)
+	l.now = clock.Now
+
+	l.Fail("john@example.com")
+	l.Fail("john@example.com")
+	locked, _ := l.Locked("john@example.com")
+	assert.False(t, locked)
+
+	l.Fail("john@example.com")
+	locked, retryAfter := l.Locked("john@example.com")
+	assert.True(t, locked)
+	assert.Equal(t, 5*time.Minute, retryAfter)
+
+	clock.Advance(5 * time.Minute)
+	locked, _ = l.Locked("john@example.com")
+	assert.False(t, locked)
+}
+
+func TestAttemptLimiterForgetsOldFailures(t *testing.T) {
+	clock := &fakeClock{now: time.Unix(0, 0)}
+	l := NewAttemptLimiter(3, time.Minute, 5*time.Minute)
+	l.now = clock.Now
+
+	l.Fail("john@example.com")
+	l.Fail("john@example.com")
+	clock.Advance(2 * time.Minute)
+	l.Fail("john@example.com")
+
+	locked, _ := l.Locked("john@example.com")
+	assert.False(t, locked)
+}
+
+func TestAttemptLimiterSweepsExpiredKeys(t *testing.T) {
+	clock := &fakeClock{now: time.Unix(0, 0)}
+	l := NewAttemptLimiter(2, time.Minute, 5*time.Minute)
+	l.now = clock.Now
+
+	l.Fail("locked@example.com")
+	l.Fail("locked@example.com")
+	for _, key := range []string{"a@example.com", "b@example.com", "c@example.com"} {
+		l.Fail(key)
+	}
+	assert.Len(t, l.entries, 4)
+
+	// falhas com emails que nunca voltam saem do mapa; a chave bloqueada fica
+	clock.Advance(2 * time.Minute)
+	l.Fail("d@example.com")
+	assert.Len(t, l.entries, 2)
+	locked, _ := l.Locked("locked@example.com")
+	assert.True(t, locked)
+}
+
+func TestLoginLimiterSuccessKeepsIPCounter(t *testing.T) {
+	l := NewLoginLimiter(
+		NewAttemptLimiter(2, time.Minute, time.Minute),
+		NewAttemptLimiter(3, time.Minute, time.Minute),
+	)
+
+	l.Failure("john@example.com", "10.0.0.1")
+	l.Success("john@example.com")
+	l.Failure("jane@example.com", "10.0.0.1")
+	locked, _ := l.Check("mary@example.com", "10.0.0.1")
+	assert.False(t, locked)
+
+	l.Failure("mary@example.com", "10.0.0.1")
+	locked, _ = l.Check("mary@example.com", "10.0.0.1")
+	assert.True(t, locked)
+
+	locked, _ = l.Check("mary@example.com", "10.0.0.2")
+	assert.False(t, locked)
+}
@@ -0,0 +1,55 @@
+package token
+
+import (
+	"errors"
+	"time"
+
+	"github.com/go-chi/jwtauth"
+)
+
+const (
+	PurposeVerifyEmail   = "verify_email"
+	PurposeResetPassword = "reset_password"
+)
+
+var ErrInvalidToken = errors.New("invalid or expired token")
+
+type Claims struct {
+	Subject     string
+	Fingerprint string
+}
+
+// Issuer emite tokens assinados e com validade para fluxos de conta (verificação
+// de email, troca de senha). Cada token carrega a finalidade para a qual foi
+// emitido e não é aceito para nenhuma outra.
+type Issuer struct {
+	auth      *jwtauth.JWTAuth
+	expiresIn time.Duration
+}
+
+func NewIssuer(auth *jwtauth.JWTAuth, expiresIn time.Duration) *Issuer {
+	return &Issuer{auth: auth, expiresIn: expiresIn}
+}
+
+func (i *Issuer) Issue(purpose, subject, fingerprint string) (string, error) {
+	_, tokenString, err := i.auth.Encode(map[string]interface{}{
+		"sub":     subject,
+		"purpose": purpose,
+		"fp":      fingerprint,
+		"exp":     time.Now().Add(i.expiresIn).Unix(),
+	})
+	return tokenString, err
+}
+
+func (i *Issuer) Parse(tokenString, purpose string) (*Claims, error) {
+	t, err := jwtauth.VerifyToken(i.auth, tokenString)
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+	claims := t.PrivateClaims()
+	if p, _ := claims["purpose"].(string); p != purpose {
+		return nil, ErrInvalidToken
+	}
+	fingerprint, _ := claims["fp"].(string)
+	return &Claims{Subject: t.Subject(), Fingerprint: fingerprint}, nil
+}
@@ -0,0 +1,46 @@
+package token
+
+import (
+	"testing"
+	"time"
+
+	"github.com/go-chi/jwtauth"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIssueAndParse(t *testing.T) {
+	issuer := NewIssuer(jwtauth.New("HS256", []byte("secret"), nil), time.Minute)
+
+	tokenString, err := issuer.Issue(PurposeVerifyEmail, "user-1", "fp-1")
+	assert.Nil(t, err)
+
+	claims, err := issuer.Parse(tokenString, PurposeVerifyEmail)
+	assert.Nil(t, err)
+	assert.Equal(t, "user-1", claims.Subject)
+	assert.Equal(t, "fp-1", claims.Fingerprint)
+}
+
+func TestParseRejectsOtherPurpose(t *testing.T) {
+	issuer := NewIssuer(jwtauth.New("HS256", []byte("secret"), nil), time.Minute)
+
+	tokenString, _ := issuer.Issue(PurposeVerifyEmail, "user-1", "fp-1")
+	_, err := issuer.Parse(tokenString, PurposeResetPassword)
+	assert.Equal(t, ErrInvalidToken, err)
+}
+
+func TestParseRejectsExpiredToken(t *testing.T) {
+	issuer := NewIssuer(jwtauth.New("HS256", []byte("secret"), nil), -time.Minute)
+
+	tokenString, _ := issuer.Issue(PurposeResetPassword, "user-1", "fp-1")
+	_, err := issuer.Parse(tokenString, PurposeResetPassword)
+	assert.Equal(t, ErrInvalidToken, err)
+}
+
+func TestParseRejectsTokenSignedWithAnotherKey(t *testing.T) {
+	issuer := NewIssuer(jwtauth.New("HS256", []byte("secret"), nil), time.Minute)
+	other := NewIssuer(jwtauth.New("HS256", []byte("other"), nil), time.Minute)
+
+	tokenString, _ := other.Issue(PurposeResetPassword, "user-1", "fp-1")
+	_, err := issuer.Parse(tokenString, PurposeResetPassword)
+	assert.Equal(t, ErrInvalidToken, err)
+}
@@ -0,0 +1,111 @@
+// Package gqlsecurity limita o que um servidor gqlgen aceita executar: custo das
+// listas, profundidade das seleções, origem das subscriptions e, em produção,
+// apenas queries persistidas de um allowlist. É usado pelo 11_GraghQL e pelo 20_CleanArch.
+package gqlsecurity
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/99designs/gqlgen/graphql/errcode"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+const errNotAllowed = "PERSISTED_QUERY_NOT_ALLOWED"
+
+// manifest segue o formato apollo-persisted-query-manifest
+type manifest struct {
+	Format     string `json:"format"`
+	Version    int    `json:"version"`
+	Operations []struct {
+		ID   string `json:"id"`
+		Name string `json:"name"`
+		Type string `json:"type"`
+		Body string `json:"body"`
+	} `json:"operations"`
+}
+
+// Allowlist só deixa executar queries cujo hash sha256 está no manifesto.
+// O cliente pode mandar só o hash (extensions.persistedQuery.sha256Hash) ou a
+// query completa, que precisa ser idêntica à do manifesto.
+type Allowlist struct {
+	queries map[string]string
+}
+
+var _ interface {
+	graphql.HandlerExtension
+	graphql.OperationParameterMutator
+} = &Allowlist{}
+
+// LoadAllowlist lê um manifesto de queries persistidas.
+func LoadAllowlist(path string) (*Allowlist, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var m manifest
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("parse persisted query manifest: %w", err)
+	}
+	if m.Format != "apollo-persisted-query-manifest" || m.Version != 1 {
+		return nil, fmt.Errorf("unsupported persisted query manifest %q version %d", m.Format, m.Version)
+	}
+
+	queries := make(map[string]string, len(m.Operations))
+	for _, op := range m.Operations {
+		if hash := QueryHash(op.Body); hash != op.ID {
+			return nil, fmt.Errorf("persisted query %q: id %s does not match body hash %s", op.Name, op.ID, hash)
+		}
+		queries[op.ID] = op.Body
+	}
+	return NewAllowlist(queries), nil
+}
+
+// NewAllowlist cria o allowlist a partir de hash -> query.
+func NewAllowlist(queries map[string]string) *Allowlist {
+	return &Allowlist{queries: queries}
+}
+
+// QueryHash é o sha256 em hex usado como id das queries persistidas.
+func QueryHash(query string) string {
+	sum := sha256.Sum256([]byte(query))
+	return hex.EncodeToString(sum[:])
+}
+
+// Len retorna quantas queries estão liberadas.
+func (a *Allowlist) Len() int {
+	return len(a.queries)
+}
+
+func (*Allowlist) ExtensionName() string { return "PersistedQueryAllowlist" }
+
+func (*Allowlist) Validate(graphql.ExecutableSchema) error { return nil }
+
+func (a *Allowlist) MutateOperationParameters(ctx context.Context, params *graphql.RawParams) *gqlerror.Error {
+	hash := QueryHash(params.Query)
+	if params.Query == "" {
+		persisted, _ := params.Extensions["persistedQuery"].(map[string]any)
+		hash, _ = persisted["sha256Hash"].(string)
+		if hash == "" {
+			return notAllowed("only persisted queries are accepted; send extensions.persistedQuery.sha256Hash")
+		}
+	}
+
+	query, ok := a.queries[hash]
+	if !ok {
+		return notAllowed("persisted query %s is not in the allowlist", hash)
+	}
+	params.Query = query
+	return nil
+}
+
+func notAllowed(format string, args ...any) *gqlerror.Error {
+	err := gqlerror.Errorf(format, args...)
+	errcode.Set(err, errNotAllowed)
+	return err
+}
@@ -0,0 +1,11 @@
+package gqlsecurity
+
+// ListMultiplier é quantos itens se estima que uma lista retorna no cálculo de complexidade.
+// Sem ele cada campo custa 1 e listas aninhadas parecem baratas.
+const ListMultiplier = 10
+
+// ListComplexity é a função de complexidade dos campos que retornam listas:
+// o próprio campo mais o custo dos filhos multiplicado por ListMultiplier.
+func ListComplexity(childComplexity int) int {
+	return 1 + childComplexity*ListMultiplier
+}
@@ -0,0 +1,15 @@
+module github.com/ElizCarvalho/FC_PosGolang/pkg/gqlsecurity
+
+go 1.24.0
+
+require (
+	github.com/99designs/gqlgen v0.17.81
+	github.com/gorilla/websocket v1.5.0
+	github.com/vektah/gqlparser/v2 v2.5.30
+)
+
+require (
+	github.com/agnivade/levenshtein v1.2.1 // indirect
+	github.com/google/uuid v1.6.0 // indirect
+	github.com/sosodev/duration v1.3.1 // indirect
+)
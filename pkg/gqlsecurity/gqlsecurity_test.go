@@ -0,0 +1,109 @@
+package gqlsecurity
+
+import (
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/vektah/gqlparser/v2"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+var schema = gqlparser.MustLoadSchema(&ast.Source{Input: `
+	type Category { id: ID! courses: [Course!]! }
+	type Course { id: ID! category: Category! }
+	type Query { categories: [Category!]! }
+`})
+
+func TestSelectionDepth(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		want  int
+	}{
+		{"flat", `{ categories { id } }`, 2},
+		{"nested", `{ categories { courses { category { id } } } }`, 4},
+		{"widest branch wins", `{ categories { id courses { id } } }`, 3},
+		{"fragment spread", `{ categories { ...C } } fragment C on Category { courses { id } }`, 3},
+		{"inline fragment", `{ categories { ... on Category { courses { id } } } }`, 3},
+		{"introspection ignored", `{ __schema { types { fields { type { ofType { name } } } } } categories { id } }`, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc := gqlparser.MustLoadQuery(schema, tt.query)
+			if got := selectionDepth(doc.Operations[0].SelectionSet, map[string]bool{}); got != tt.want {
+				t.Fatalf("expected depth %d, got %d", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestLoadAllowlistRejectsInvalidManifests(t *testing.T) {
+	tests := []struct {
+		name     string
+		manifest string
+	}{
+		{"wrong format", `{"format": "other", "version": 1, "operations": []}`},
+		{"hash mismatch", `{"format": "apollo-persisted-query-manifest", "version": 1, "operations": [
+			{"id": "deadbeef", "name": "Q", "type": "query", "body": "{ categories { id } }"}
+		]}`},
+		{"invalid json", `{`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "manifest.json")
+			if err := os.WriteFile(path, []byte(tt.manifest), 0o600); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := LoadAllowlist(path); err == nil {
+				t.Fatal("expected error")
+			}
+		})
+	}
+}
+
+func TestListComplexity(t *testing.T) {
+	// courses { id } = 1 + 1*10; categories { courses { id } } = 1 + 11*10
+	if got := ListComplexity(ListComplexity(1)); got != 111 {
+		t.Fatalf("expected complexity 111, got %d", got)
+	}
+}
+
+func TestCheckOrigin(t *testing.T) {
+	tests := []struct {
+		name    string
+		allowed []string
+		origin  string
+		want    bool
+	}{
+		{"no origin header", []string{"https://app.example.com"}, "", true},
+		{"allowed origin", []string{" https://app.example.com/ ", ""}, "https://APP.example.com", true},
+		{"other site", []string{"https://app.example.com"}, "https://evil.example", false},
+		{"same origin without list", nil, "http://api.example.com:8080", true},
+		{"other origin without list", []string{""}, "http://localhost:3000", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", "http://api.example.com:8080/query", nil)
+			if tt.origin != "" {
+				r.Header.Set("Origin", tt.origin)
+			}
+			if got := CheckOrigin(tt.allowed)(r); got != tt.want {
+				t.Fatalf("CheckOrigin(%v) for %q = %v, want %v", tt.allowed, tt.origin, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWebsocketChecksOriginOnlyInProduction(t *testing.T) {
+	r := httptest.NewRequest("GET", "http://api.example.com/query", nil)
+	r.Header.Set("Origin", "https://evil.example")
+
+	if !Websocket(false, nil).Upgrader.CheckOrigin(r) {
+		t.Fatal("expected any origin in development")
+	}
+	if Websocket(true, []string{"https://app.example.com"}).Upgrader.CheckOrigin(r) {
+		t.Fatal("expected other origins to be rejected in production")
+	}
+}
@@ -0,0 +1,50 @@
+package gqlsecurity
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/99designs/gqlgen/graphql/handler/transport"
+	"github.com/gorilla/websocket"
+)
+
+// Websocket monta o transport de subscriptions. Em desenvolvimento qualquer origem
+// abre o websocket (o playground roda em outra); em produção só as de allowedOrigins,
+// pelo CheckOrigin.
+func Websocket(production bool, allowedOrigins []string) transport.Websocket {
+	checkOrigin := func(r *http.Request) bool { return true }
+	if production {
+		checkOrigin = CheckOrigin(allowedOrigins)
+	}
+	return transport.Websocket{
+		KeepAlivePingInterval: 10 * time.Second,
+		Upgrader:              websocket.Upgrader{CheckOrigin: checkOrigin},
+	}
+}
+
+// CheckOrigin decide se o websocket de subscriptions pode ser aberto a partir do Origin da página.
+// Sem essa checagem qualquer site abriria subscriptions com a sessão do navegador do usuário
+// (cross-site websocket hijacking). Com allowed vazio, só a mesma origem do servidor é aceita.
+// Requisições sem Origin não vêm de navegador e passam.
+func CheckOrigin(allowed []string) func(r *http.Request) bool {
+	origins := make(map[string]bool, len(allowed))
+	for _, origin := range allowed {
+		if origin = strings.TrimRight(strings.TrimSpace(origin), "/"); origin != "" {
+			origins[strings.ToLower(origin)] = true
+		}
+	}
+
+	return func(r *http.Request) bool {
+		origin := r.Header.Get("Origin")
+		if origin == "" {
+			return true
+		}
+		if len(origins) > 0 {
+			return origins[strings.ToLower(origin)]
+		}
+		u, err := url.Parse(origin)
+		return err == nil && strings.EqualFold(u.Host, r.Host)
+	}
+}
@@ -0,0 +1,60 @@
+// Package gateway é a base dos gateways REST/JSON dos servidores gRPC: o mux do
+// grpc-gateway com as opções de JSON, a rota do documento OpenAPI e a conexão em
+// memória com o servidor. Cada projeto informa só os handlers gerados
+// (pb.Register...Handler) e o OpenAPI gerado pelo protoc-gen-openapiv2.
+package gateway
+
+import (
+	"context"
+	"net"
+	"net/http"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+// RegisterFunc tem a assinatura dos pb.Register<Serviço>Handler gerados pelo protoc-gen-grpc-gateway
+type RegisterFunc func(ctx context.Context, mux *runtime.ServeMux, conn *grpc.ClientConn) error
+
+// New monta o handler HTTP: /v1/... traduz para os RPCs feitos em conn e
+// /openapi.json devolve openAPI. O header Authorization é repassado como
+// metadata, então a autenticação do servidor gRPC vale também aqui.
+func New(ctx context.Context, conn *grpc.ClientConn, openAPI []byte, register ...RegisterFunc) (http.Handler, error) {
+	mux := runtime.NewServeMux(
+		runtime.WithMarshalerOption(runtime.MIMEWildcard, &runtime.JSONPb{
+			MarshalOptions:   protojson.MarshalOptions{UseProtoNames: true, EmitUnpopulated: true},
+			UnmarshalOptions: protojson.UnmarshalOptions{DiscardUnknown: true},
+		}),
+	)
+	for _, fn := range register {
+		if err := fn(ctx, mux, conn); err != nil {
+			return nil, err
+		}
+	}
+
+	handler := http.NewServeMux()
+	handler.Handle("/v1/", mux)
+	handler.HandleFunc("GET /openapi.json", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(openAPI)
+	})
+	return handler, nil
+}
+
+// InProcess serve server num listener em memória e devolve uma conexão com ele,
+// para que o gateway rode no mesmo processo sem passar pela rede. server não
+// deve ter credenciais TLS: a conexão nunca sai do processo.
+func InProcess(server *grpc.Server) (*grpc.ClientConn, error) {
+	listener := bufconn.Listen(1024 * 1024)
+	go server.Serve(listener)
+
+	return grpc.NewClient("passthrough:///gateway",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+}
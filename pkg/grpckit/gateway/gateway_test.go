@@ -0,0 +1,72 @@
+package gateway
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+func TestInProcessServesTheServer(t *testing.T) {
+	server := grpc.NewServer()
+	healthpb.RegisterHealthServer(server, health.NewServer())
+	t.Cleanup(server.Stop)
+
+	conn, err := InProcess(server)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	resp, err := healthpb.NewHealthClient(conn).Check(context.Background(), &healthpb.HealthCheckRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Status != healthpb.HealthCheckResponse_SERVING {
+		t.Fatalf("expected SERVING, got %s", resp.Status)
+	}
+}
+
+func TestNewRoutesRegisteredHandlersAndOpenAPI(t *testing.T) {
+	// uma rota manual no lugar de um pb.Register...Handler gerado
+	ping := func(ctx context.Context, mux *runtime.ServeMux, conn *grpc.ClientConn) error {
+		return mux.HandlePath("GET", "/v1/ping", func(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+			io.WriteString(w, "pong")
+		})
+	}
+	handler, err := New(context.Background(), nil, []byte(`{"swagger":"2.0"}`), ping)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for path, want := range map[string]string{"/v1/ping": "pong", "/openapi.json": `{"swagger":"2.0"}`} {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest("GET", path, nil))
+		if rec.Code != http.StatusOK || rec.Body.String() != want {
+			t.Fatalf("GET %s: expected 200 %q, got %d %q", path, want, rec.Code, rec.Body.String())
+		}
+	}
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest("GET", "/other", nil))
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("routes outside /v1 should not be served, got %d", rec.Code)
+	}
+}
+
+func TestNewReturnsRegisterErrors(t *testing.T) {
+	errRegister := errors.New("register failed")
+	_, err := New(context.Background(), nil, nil, func(context.Context, *runtime.ServeMux, *grpc.ClientConn) error {
+		return errRegister
+	})
+	if !errors.Is(err, errRegister) {
+		t.Fatalf("expected the register error, got %v", err)
+	}
+}
@@ -0,0 +1,23 @@
+package interceptor
+
+import (
+	"context"
+	"time"
+
+	"google.golang.org/grpc"
+)
+
+// UnaryDeadline garante que toda chamada unary tenha prazo: aplica defaultTimeout
+// quando o cliente não enviou deadline e reduz para maxTimeout os prazos maiores.
+// O handler deve repassar o ctx ao banco para que o prazo tenha efeito.
+func UnaryDeadline(defaultTimeout, maxTimeout time.Duration) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		timeout := defaultTimeout
+		if deadline, ok := ctx.Deadline(); ok {
+			timeout = min(time.Until(deadline), maxTimeout)
+		}
+		ctx, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
+		return handler(ctx, req)
+	}
+}
@@ -0,0 +1,83 @@
+// Package interceptor reúne os interceptors unary e stream usados pelos servidores
+// gRPC do 13_gRPC_FC e do 20_CleanArch: log estruturado, métricas, recuperação
+// de panics, prazo e validação.
+package interceptor
+
+import (
+	"log/slog"
+	"time"
+
+	"google.golang.org/grpc"
+)
+
+const (
+	DefaultTimeout = 10 * time.Second
+	MaxTimeout     = time.Minute
+)
+
+// Interceptor é um par unary/stream com estado próprio, como Metrics ou o
+// autenticador JWT do 13_gRPC_FC
+type Interceptor interface {
+	UnaryInterceptor() grpc.UnaryServerInterceptor
+	StreamInterceptor() grpc.StreamServerInterceptor
+}
+
+type Options struct {
+	Logger *slog.Logger
+	// Metrics é opcional; sem ele nenhuma métrica é coletada
+	Metrics *Metrics
+	// Auth é opcional; sem ele as chamadas não exigem autenticação
+	Auth Interceptor
+	// DefaultTimeout é aplicado às chamadas unary que chegam sem deadline
+	DefaultTimeout time.Duration
+	// MaxTimeout limita o deadline pedido pelo cliente nas chamadas unary
+	MaxTimeout time.Duration
+}
+
+// ServerOptions monta a cadeia de interceptors na ordem em que devem rodar:
+// o log e as métricas ficam por fora para enxergar o código final da chamada,
+// inclusive o Internal gerado pela recuperação de panics e o Unauthenticated
+// da autenticação, que roda antes de prazo e validação.
+func ServerOptions(opts Options) []grpc.ServerOption {
+	if opts.Logger == nil {
+		opts.Logger = slog.Default()
+	}
+	if opts.DefaultTimeout <= 0 {
+		opts.DefaultTimeout = DefaultTimeout
+	}
+	if opts.MaxTimeout <= 0 {
+		opts.MaxTimeout = MaxTimeout
+	}
+
+	return []grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(unaryChain(opts)...),
+		grpc.ChainStreamInterceptor(streamChain(opts)...),
+	}
+}
+
+func unaryChain(opts Options) []grpc.UnaryServerInterceptor {
+	unary := []grpc.UnaryServerInterceptor{UnaryLogging(opts.Logger)}
+	if opts.Metrics != nil {
+		unary = append(unary, opts.Metrics.UnaryInterceptor())
+	}
+	unary = append(unary, UnaryRecovery(opts.Logger))
+	if opts.Auth != nil {
+		unary = append(unary, opts.Auth.UnaryInterceptor())
+	}
+	return append(unary,
+		UnaryDeadline(opts.DefaultTimeout, opts.MaxTimeout),
+		UnaryValidation(),
+	)
+}
+
+func streamChain(opts Options) []grpc.StreamServerInterceptor {
+	stream := []grpc.StreamServerInterceptor{StreamLogging(opts.Logger)}
+	if opts.Metrics != nil {
+		stream = append(stream, opts.Metrics.StreamInterceptor())
+	}
+	stream = append(stream, StreamRecovery(opts.Logger))
+	if opts.Auth != nil {
+		stream = append(stream, opts.Auth.StreamInterceptor())
+	}
+	return append(stream, StreamValidation())
+}
@@ -0,0 +1,194 @@
+package interceptor
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// testRequest faz o papel de uma mensagem gerada com Validate, como as do validate.go dos projetos
+type testRequest struct {
+	Name string
+}
+
+func (r *testRequest) Validate() error {
+	if r.Name == "" {
+		return errors.New("name is required")
+	}
+	return nil
+}
+
+var (
+	discardLogger = slog.New(slog.NewTextHandler(io.Discard, nil))
+	unaryInfo     = &grpc.UnaryServerInfo{FullMethod: "/pb.CategoryService/CreateCategory"}
+)
+
+// chain encadeia os interceptors como o grpc.ChainUnaryInterceptor faz
+func chain(interceptors []grpc.UnaryServerInterceptor, handler grpc.UnaryHandler) grpc.UnaryHandler {
+	for i := len(interceptors) - 1; i >= 0; i-- {
+		next, interceptor := handler, interceptors[i]
+		handler = func(ctx context.Context, req any) (any, error) {
+			return interceptor(ctx, req, unaryInfo, next)
+		}
+	}
+	return handler
+}
+
+func assertCode(t *testing.T, err error, want codes.Code) {
+	t.Helper()
+	if got := status.Code(err); got != want {
+		t.Fatalf("expected %s, got %s (%v)", want, got, err)
+	}
+}
+
+func TestUnaryRecovery(t *testing.T) {
+	handler := chain([]grpc.UnaryServerInterceptor{UnaryRecovery(discardLogger)}, func(ctx context.Context, req any) (any, error) {
+		panic("boom")
+	})
+	_, err := handler(context.Background(), &testRequest{Name: "Go"})
+	assertCode(t, err, codes.Internal)
+	if status.Convert(err).Message() != "internal error" {
+		t.Fatalf("panic value leaked to the client: %v", err)
+	}
+}
+
+func TestUnaryDeadline(t *testing.T) {
+	var remaining time.Duration
+	handler := chain([]grpc.UnaryServerInterceptor{UnaryDeadline(time.Second, 5*time.Second)}, func(ctx context.Context, req any) (any, error) {
+		deadline, ok := ctx.Deadline()
+		if !ok {
+			t.Fatal("handler called without deadline")
+		}
+		remaining = time.Until(deadline)
+		return nil, nil
+	})
+
+	handler(context.Background(), nil)
+	if remaining > time.Second {
+		t.Fatalf("default timeout not applied: %s", remaining)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Hour)
+	defer cancel()
+	handler(ctx, nil)
+	if remaining > 5*time.Second {
+		t.Fatalf("client deadline not capped: %s", remaining)
+	}
+
+	ctx, cancel = context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	handler(ctx, nil)
+	if remaining <= time.Second || remaining > 3*time.Second {
+		t.Fatalf("client deadline not kept: %s", remaining)
+	}
+}
+
+func TestUnaryValidation(t *testing.T) {
+	called := false
+	handler := chain([]grpc.UnaryServerInterceptor{UnaryValidation()}, func(ctx context.Context, req any) (any, error) {
+		called = true
+		return nil, nil
+	})
+
+	_, err := handler(context.Background(), &testRequest{})
+	assertCode(t, err, codes.InvalidArgument)
+	if status.Convert(err).Message() != "name is required" {
+		t.Fatalf("unexpected validation message: %v", err)
+	}
+	if called {
+		t.Fatal("handler called with invalid request")
+	}
+
+	_, err = handler(context.Background(), &testRequest{Name: "Go"})
+	assertCode(t, err, codes.OK)
+	if !called {
+		t.Fatal("handler not called with valid request")
+	}
+}
+
+func TestMetricsCountsByCode(t *testing.T) {
+	m := NewMetrics(prometheus.NewRegistry())
+	ok := chain([]grpc.UnaryServerInterceptor{m.UnaryInterceptor()}, func(ctx context.Context, req any) (any, error) {
+		return nil, nil
+	})
+	failing := chain([]grpc.UnaryServerInterceptor{m.UnaryInterceptor()}, func(ctx context.Context, req any) (any, error) {
+		return nil, status.Error(codes.NotFound, "not found")
+	})
+
+	ok(context.Background(), nil)
+	ok(context.Background(), nil)
+	failing(context.Background(), nil)
+
+	counter := func(code string) float64 {
+		return testutil.ToFloat64(m.handled.WithLabelValues("pb.CategoryService", "CreateCategory", "unary", code))
+	}
+	if got := counter("OK"); got != 2 {
+		t.Fatalf("expected 2 OK calls, got %v", got)
+	}
+	if got := counter("NotFound"); got != 1 {
+		t.Fatalf("expected 1 NotFound call, got %v", got)
+	}
+}
+
+func TestPanicIsCountedAsInternal(t *testing.T) {
+	// a ordem importa: métricas e log precisam ver o Internal gerado pela recuperação
+	m := NewMetrics(prometheus.NewRegistry())
+	opts := ServerOptions(Options{Logger: discardLogger, Metrics: m})
+	if len(opts) != 2 {
+		t.Fatalf("expected unary and stream chains, got %d options", len(opts))
+	}
+
+	handler := chain([]grpc.UnaryServerInterceptor{
+		UnaryLogging(discardLogger), m.UnaryInterceptor(), UnaryRecovery(discardLogger),
+	}, func(ctx context.Context, req any) (any, error) {
+		panic("boom")
+	})
+	_, err := handler(context.Background(), nil)
+	assertCode(t, err, codes.Internal)
+	if got := testutil.ToFloat64(m.handled.WithLabelValues("pb.CategoryService", "CreateCategory", "unary", "Internal")); got != 1 {
+		t.Fatalf("expected panic counted as Internal, got %v", got)
+	}
+}
+
+// denyAll recusa toda chamada, como um autenticador sem token válido
+type denyAll struct{}
+
+func (denyAll) UnaryInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		return nil, status.Error(codes.Unauthenticated, "denied")
+	}
+}
+
+func (denyAll) StreamInterceptor() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		return status.Error(codes.Unauthenticated, "denied")
+	}
+}
+
+func TestAuthRunsBeforeValidation(t *testing.T) {
+	m := NewMetrics(prometheus.NewRegistry())
+	called := false
+	handler := chain(unaryChain(Options{Logger: discardLogger, Metrics: m, Auth: denyAll{}}), func(ctx context.Context, req any) (any, error) {
+		called = true
+		return nil, nil
+	})
+
+	// a request inválida não chega à validação: quem não se autenticou recebe Unauthenticated
+	_, err := handler(context.Background(), &testRequest{})
+	assertCode(t, err, codes.Unauthenticated)
+	if called {
+		t.Fatal("handler called without authentication")
+	}
+	if got := testutil.ToFloat64(m.handled.WithLabelValues("pb.CategoryService", "CreateCategory", "unary", "Unauthenticated")); got != 1 {
+		t.Fatalf("expected the rejected call counted as Unauthenticated, got %v", got)
+	}
+}
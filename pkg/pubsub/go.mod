@@ -0,0 +1,3 @@
+module github.com/ElizCarvalho/FC_PosGolang/pkg/pubsub
+
+go 1.24.0
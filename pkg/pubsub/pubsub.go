@@ -0,0 +1,63 @@
+// Package pubsub distribui eventos em memória para as subscriptions do GraphQL.
+// É usado pelo 11_GraghQL e pelo 20_CleanArch.
+package pubsub
+
+import (
+	"context"
+	"sync"
+)
+
+// Broker entrega cada valor publicado a todos os assinantes ativos.
+// Assinantes lentos perdem eventos em vez de travar quem publica.
+type Broker[T any] struct {
+	buffer int
+
+	mu   sync.RWMutex
+	subs map[chan T]struct{}
+}
+
+// NewBroker cria um broker cujos assinantes têm buffer de tamanho buffer.
+func NewBroker[T any](buffer int) *Broker[T] {
+	return &Broker[T]{
+		buffer: buffer,
+		subs:   make(map[chan T]struct{}),
+	}
+}
+
+// Subscribe registra um assinante até ctx ser cancelado; o canal é fechado em seguida.
+func (b *Broker[T]) Subscribe(ctx context.Context) <-chan T {
+	ch := make(chan T, b.buffer)
+
+	b.mu.Lock()
+	b.subs[ch] = struct{}{}
+	b.mu.Unlock()
+
+	go func() {
+		<-ctx.Done()
+		b.mu.Lock()
+		delete(b.subs, ch)
+		close(ch)
+		b.mu.Unlock()
+	}()
+
+	return ch
+}
+
+// Publish envia v para todos os assinantes sem bloquear.
+func (b *Broker[T]) Publish(v T) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	for ch := range b.subs {
+		select {
+		case ch <- v:
+		default:
+		}
+	}
+}
+
+// Subscribers retorna quantos assinantes estão ativos.
+func (b *Broker[T]) Subscribers() int {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return len(b.subs)
+}
@@ -0,0 +1,64 @@
+package pubsub
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func waitSubscribers(t *testing.T, b *Broker[int], want int) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for b.Subscribers() != want {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected %d subscribers, got %d", want, b.Subscribers())
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestPublishReachesEverySubscriber(t *testing.T) {
+	b := NewBroker[int](1)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	first, second := b.Subscribe(ctx), b.Subscribe(ctx)
+
+	b.Publish(1)
+	for _, ch := range []<-chan int{first, second} {
+		if got := <-ch; got != 1 {
+			t.Fatalf("expected 1, got %d", got)
+		}
+	}
+}
+
+func TestPublishDropsEventsForSlowSubscribers(t *testing.T) {
+	b := NewBroker[int](1)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ch := b.Subscribe(ctx)
+
+	// o buffer comporta um evento; o segundo é descartado sem bloquear o Publish
+	b.Publish(1)
+	b.Publish(2)
+	if got := <-ch; got != 1 {
+		t.Fatalf("expected 1, got %d", got)
+	}
+	select {
+	case got := <-ch:
+		t.Fatalf("expected dropped event, got %d", got)
+	default:
+	}
+}
+
+func TestSubscribeEndsWithContext(t *testing.T) {
+	b := NewBroker[int](1)
+	ctx, cancel := context.WithCancel(context.Background())
+	ch := b.Subscribe(ctx)
+	waitSubscribers(t, b, 1)
+
+	cancel()
+	waitSubscribers(t, b, 0)
+	if _, ok := <-ch; ok {
+		t.Fatal("expected closed channel")
+	}
+}
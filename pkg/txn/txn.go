@@ -0,0 +1,161 @@
+// Package txn executa funções dentro de uma transação SQL: begin, rollback e commit ficam
+// aqui, com nível de isolamento configurável, modo somente leitura, nova tentativa em
+// deadlock/lock wait timeout do MySQL e hooks executados depois do commit.
+package txn
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+const (
+	DefaultMaxAttempts = 3
+	DefaultBackoff     = 50 * time.Millisecond
+	maxBackoff         = 2 * time.Second
+)
+
+// Códigos do MySQL em que a transação foi desfeita pelo servidor e pode ser repetida inteira
+const (
+	errDeadlock        = 1213 // ER_LOCK_DEADLOCK
+	errLockWaitTimeout = 1205 // ER_LOCK_WAIT_TIMEOUT
+)
+
+type Options struct {
+	Isolation   sql.IsolationLevel // sql.LevelDefault usa o padrão do banco (REPEATABLE READ no MySQL)
+	ReadOnly    bool
+	MaxAttempts int           // Total de tentativas, contando a primeira
+	Backoff     time.Duration // Espera antes da 2ª tentativa; dobra a cada nova tentativa
+}
+
+type Option func(*Options)
+
+func WithIsolation(level sql.IsolationLevel) Option {
+	return func(o *Options) { o.Isolation = level }
+}
+
+func ReadOnly() Option {
+	return func(o *Options) { o.ReadOnly = true }
+}
+
+// WithMaxAttempts define o total de tentativas; 1 desliga as novas tentativas
+func WithMaxAttempts(n int) Option {
+	return func(o *Options) { o.MaxAttempts = max(n, 1) }
+}
+
+func WithBackoff(d time.Duration) Option {
+	return func(o *Options) { o.Backoff = d }
+}
+
+func NewOptions(opts ...Option) Options {
+	o := Options{MaxAttempts: DefaultMaxAttempts, Backoff: DefaultBackoff}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// Tx é a transação de uma tentativa. Hooks registrados numa tentativa que falhou são descartados.
+type Tx struct {
+	*sql.Tx
+	afterCommit []func()
+}
+
+// AfterCommit agenda fn para depois do commit. Não roda em rollback.
+func (t *Tx) AfterCommit(fn func()) {
+	t.afterCommit = append(t.afterCommit, fn)
+}
+
+// Run executa fn numa transação: rollback se fn falhar, commit caso contrário.
+// Em deadlock ou lock wait timeout a transação inteira é repetida, então fn não deve ter
+// efeitos fora do banco; use AfterCommit para eles.
+func Run(ctx context.Context, db *sql.DB, fn func(tx *Tx) error, opts ...Option) error {
+	o := NewOptions(opts...)
+	txOpts := &sql.TxOptions{Isolation: o.Isolation, ReadOnly: o.ReadOnly}
+
+	var err error
+	for attempt := 1; ; attempt++ {
+		var tx *Tx
+		tx, err = attemptTx(ctx, db, txOpts, fn)
+		if err == nil {
+			for _, hook := range tx.afterCommit {
+				hook()
+			}
+			return nil
+		}
+		if attempt >= o.MaxAttempts || !IsRetryable(err) {
+			return err
+		}
+		if errWait := wait(ctx, backoff(o.Backoff, attempt)); errWait != nil {
+			return fmt.Errorf("%w (retry aborted: %w)", err, errWait)
+		}
+	}
+}
+
+// RunQueries é o Run para Queries geradas pelo sqlc: newQueries recebe a transação
+// da tentativa, normalmente func(tx *sql.Tx) *db.Queries { return db.New(tx) }.
+func RunQueries[Q any](ctx context.Context, db *sql.DB, newQueries func(*sql.Tx) Q, fn func(q Q, tx *Tx) error, opts ...Option) error {
+	return Run(ctx, db, func(tx *Tx) error {
+		return fn(newQueries(tx.Tx), tx)
+	}, opts...)
+}
+
+func attemptTx(ctx context.Context, db *sql.DB, txOpts *sql.TxOptions, fn func(tx *Tx) error) (*Tx, error) {
+	sqlTx, err := db.BeginTx(ctx, txOpts)
+	if err != nil {
+		return nil, err
+	}
+	tx := &Tx{Tx: sqlTx}
+	if err := fn(tx); err != nil {
+		if errRollback := sqlTx.Rollback(); errRollback != nil {
+			return nil, fmt.Errorf("rollback error: %w, original error: %w", errRollback, err)
+		}
+		return nil, err
+	}
+	if err := sqlTx.Commit(); err != nil {
+		return nil, err
+	}
+	return tx, nil
+}
+
+// IsRetryable indica se err é um deadlock ou lock wait timeout do MySQL
+func IsRetryable(err error) bool {
+	var mysqlErr *mysql.MySQLError
+	if !errors.As(err, &mysqlErr) {
+		return false
+	}
+	return mysqlErr.Number == errDeadlock || mysqlErr.Number == errLockWaitTimeout
+}
+
+// backoff dobra a espera a cada tentativa, com jitter para que as transações
+// que colidiram não tentem de novo ao mesmo tempo
+func backoff(base time.Duration, attempt int) time.Duration {
+	if base <= 0 {
+		return 0
+	}
+	d := base
+	for i := 1; i < attempt && d < maxBackoff; i++ {
+		d *= 2
+	}
+	d = min(d, maxBackoff)
+	return d/2 + rand.N(d/2+1)
+}
+
+func wait(ctx context.Context, d time.Duration) error {
+	if d == 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
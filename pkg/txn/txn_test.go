@@ -0,0 +1,156 @@
+package txn
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestDB(t *testing.T) *sql.DB {
+	t.Helper()
+	dbt, err := sql.Open("sqlite3", ":memory:")
+	assert.NoError(t, err)
+	dbt.SetMaxOpenConns(1) // Cada conexão teria seu próprio banco em memória
+	t.Cleanup(func() { dbt.Close() })
+
+	_, err = dbt.Exec("CREATE TABLE categories (id INTEGER PRIMARY KEY AUTOINCREMENT, name varchar(255) NOT NULL)")
+	assert.NoError(t, err)
+	return dbt
+}
+
+func countCategories(t *testing.T, dbt *sql.DB) int {
+	t.Helper()
+	var total int
+	assert.NoError(t, dbt.QueryRow("SELECT COUNT(*) FROM categories").Scan(&total))
+	return total
+}
+
+func insertCategory(tx *Tx, name string) error {
+	_, err := tx.Exec("INSERT INTO categories (name) VALUES (?)", name)
+	return err
+}
+
+func TestRunCommitsAndRunsHooks(t *testing.T) {
+	dbt := newTestDB(t)
+	var hooks []string
+
+	err := Run(context.Background(), dbt, func(tx *Tx) error {
+		tx.AfterCommit(func() { hooks = append(hooks, "first") })
+		tx.AfterCommit(func() { hooks = append(hooks, "second") })
+		assert.Empty(t, hooks, "hooks must wait for the commit")
+		return insertCategory(tx, "Backend")
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, countCategories(t, dbt))
+	assert.Equal(t, []string{"first", "second"}, hooks)
+}
+
+func TestRunRollsBackOnError(t *testing.T) {
+	dbt := newTestDB(t)
+	errFail := errors.New("fail after insert")
+	hookCalled := false
+
+	err := Run(context.Background(), dbt, func(tx *Tx) error {
+		tx.AfterCommit(func() { hookCalled = true })
+		if err := insertCategory(tx, "Backend"); err != nil {
+			return err
+		}
+		return errFail
+	})
+
+	assert.ErrorIs(t, err, errFail)
+	assert.Equal(t, 0, countCategories(t, dbt))
+	assert.False(t, hookCalled)
+}
+
+func TestRunRetriesDeadlocks(t *testing.T) {
+	dbt := newTestDB(t)
+	attempts, hooks := 0, 0
+
+	err := Run(context.Background(), dbt, func(tx *Tx) error {
+		attempts++
+		tx.AfterCommit(func() { hooks++ })
+		if err := insertCategory(tx, "Backend"); err != nil {
+			return err
+		}
+		if attempts == 1 {
+			return &mysql.MySQLError{Number: errDeadlock, Message: "Deadlock found when trying to get lock"}
+		}
+		return nil
+	}, WithBackoff(time.Millisecond))
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2, attempts)
+	assert.Equal(t, 1, countCategories(t, dbt), "the failed attempt must be rolled back")
+	assert.Equal(t, 1, hooks, "hooks from the failed attempt must be discarded")
+}
+
+func TestRunStopsRetrying(t *testing.T) {
+	dbt := newTestDB(t)
+	lockTimeout := &mysql.MySQLError{Number: errLockWaitTimeout, Message: "Lock wait timeout exceeded"}
+
+	attempts := 0
+	err := Run(context.Background(), dbt, func(tx *Tx) error {
+		attempts++
+		return lockTimeout
+	}, WithMaxAttempts(4), WithBackoff(time.Millisecond))
+	assert.ErrorIs(t, err, lockTimeout)
+	assert.Equal(t, 4, attempts)
+
+	attempts = 0
+	err = Run(context.Background(), dbt, func(tx *Tx) error {
+		attempts++
+		return &mysql.MySQLError{Number: 1062, Message: "Duplicate entry"}
+	})
+	assert.Error(t, err)
+	assert.Equal(t, 1, attempts, "only deadlocks and lock timeouts are retried")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	attempts = 0
+	err = Run(ctx, dbt, func(tx *Tx) error {
+		attempts++
+		cancel()
+		return lockTimeout
+	}, WithBackoff(time.Hour))
+	assert.ErrorIs(t, err, lockTimeout)
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, 1, attempts)
+}
+
+func TestRunQueries(t *testing.T) {
+	dbt := newTestDB(t)
+	type queries struct{ tx *sql.Tx }
+
+	err := RunQueries(context.Background(), dbt,
+		func(tx *sql.Tx) *queries { return &queries{tx: tx} },
+		func(q *queries, tx *Tx) error {
+			assert.Same(t, tx.Tx, q.tx)
+			_, err := q.tx.Exec("INSERT INTO categories (name) VALUES (?)", "Backend")
+			return err
+		})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, countCategories(t, dbt))
+}
+
+func TestOptions(t *testing.T) {
+	o := NewOptions()
+	assert.Equal(t, Options{MaxAttempts: DefaultMaxAttempts, Backoff: DefaultBackoff}, o)
+
+	o = NewOptions(WithIsolation(sql.LevelSerializable), ReadOnly(), WithMaxAttempts(0), WithBackoff(0))
+	assert.Equal(t, Options{Isolation: sql.LevelSerializable, ReadOnly: true, MaxAttempts: 1}, o)
+
+	for attempt := 1; attempt <= 10; attempt++ {
+		d := backoff(DefaultBackoff, attempt)
+		assert.LessOrEqual(t, d, maxBackoff)
+		assert.Greater(t, d, time.Duration(0))
+	}
+	assert.Zero(t, backoff(0, 3))
+}